@@ -45,8 +45,11 @@ func (this *APaymentTokenController) Transfer() {
 }
 
 // @Title Get Transactions
-// @Description get all transactions
-// @Success 200 {Object} models.APaymentTokenTransaction
+// @Description get a page of the transactions, filtered by request and account
+// @Param	offset	query	int	false	"index of the first item"
+// @Param	limit	query	int	false	"number of items, at most 500"
+// @Param	sort	query	string	false	"blockNumber or amount, prefixed with - for descending order"
+// @Success 200 {Object} services.Page
 // @router /transactions [get]
 func (this *APaymentTokenController) GetAllTransactions() {
 
@@ -60,11 +63,6 @@ func (this *APaymentTokenController) GetAllTransactions() {
 		this.CustomAbort(401, "Unauthorized")
 	}
 
-	transactions, err := services.GetTransactions()
-	if err != nil {
-		beego.Error("Error while getting transactions. ", err)
-		this.CustomAbort(500, err.Error())
-	}
-	this.Data["json"] = transactions
-	this.ServeJSON()
+	page, err := services.GetAllTransactions(listQuery(&this.Controller))
+	servePage(&this.Controller, page, err)
 }
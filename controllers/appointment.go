@@ -0,0 +1,245 @@
+package controllers
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/scmo/apayment-backend/models"
+	"github.com/scmo/apayment-backend/services"
+)
+
+// Operations about the appointments of inspections
+type AppointmentController struct {
+	beego.Controller
+}
+
+func (this *AppointmentController) getUser() *models.User {
+	claims, err := services.ParseToken(this.Ctx.Request.Header.Get("Authorization"))
+	if err != nil {
+		this.CustomAbort(401, "Unauthorized")
+	}
+	user, err := services.GetUserByUsername(claims.Subject)
+	if err != nil {
+		this.CustomAbort(404, err.Error())
+	}
+	return user
+}
+
+// @Title Propose
+// @Description Propose the date of the inspection of a request. Unannounced appointments are not shown to the farmer before they are held.
+// @Param	body		body 	models.InspectionAppointment	true		"body with the requestId, the date, the duration in minutes and whether it is unannounced"
+// @Success 200 {object} models.InspectionAppointment
+// @router / [post]
+func (this *AppointmentController) Post() {
+	var appointment models.InspectionAppointment
+	json.Unmarshal(this.Ctx.Input.RequestBody, &appointment)
+
+	user := this.getUser()
+	if user.HasRole("Inspector") {
+		request := services.GetRequestById(appointment.RequestId, false)
+		if request.Inspector == nil || request.Inspector.Id != user.Id {
+			this.CustomAbort(401, "Unauthorized")
+		}
+	} else if (user.HasRole("Admin") || user.HasRole("Canton")) == false {
+		this.CustomAbort(401, "Unauthorized")
+	}
+
+	if err := services.ProposeAppointment(&appointment); err != nil {
+		this.abortWithAppointmentError(err)
+	}
+	this.Data["json"] = appointment
+	this.ServeJSON()
+}
+
+// @Title Get
+// @Description get an appointment
+// @Param	appointmentId		path 	int64	true		"The id of the appointment"
+// @Success 200 {object} models.InspectionAppointment
+// @router /:appointmentId [get]
+func (this *AppointmentController) Get() {
+	this.Data["json"] = this.appointmentOf(this.getUser(), false)
+	this.ServeJSON()
+}
+
+// @Title Confirm
+// @Description The farmer confirms the proposed date
+// @Param	appointmentId		path 	int64	true		"The id of the appointment"
+// @Success 200 {object} models.InspectionAppointment
+// @router /:appointmentId/confirm [post]
+func (this *AppointmentController) Confirm() {
+	user := this.getUser()
+	appointment := this.appointmentOf(user, false)
+	if appointment.Request.User.Id != user.Id {
+		this.CustomAbort(401, "Unauthorized")
+	}
+	if err := services.ConfirmAppointment(appointment); err != nil {
+		this.abortWithAppointmentError(err)
+	}
+	this.Data["json"] = appointment
+	this.ServeJSON()
+}
+
+// @Title Reschedule
+// @Description Move an open appointment to another date, an announced one has to be confirmed again
+// @Param	appointmentId		path 	int64	true		"The id of the appointment"
+// @Param	body		body 	models.InspectionAppointment	true		"body with the new date"
+// @Success 200 {object} models.InspectionAppointment
+// @router /:appointmentId/reschedule [put]
+func (this *AppointmentController) Reschedule() {
+	var body models.InspectionAppointment
+	json.Unmarshal(this.Ctx.Input.RequestBody, &body)
+
+	appointment := this.appointmentOf(this.getUser(), true)
+	if err := services.RescheduleAppointment(appointment, body.Date); err != nil {
+		this.abortWithAppointmentError(err)
+	}
+	this.Data["json"] = appointment
+	this.ServeJSON()
+}
+
+// @Title Cancel
+// @Description Cancel an open appointment
+// @Param	appointmentId		path 	int64	true		"The id of the appointment"
+// @Success 200 {object} models.InspectionAppointment
+// @router /:appointmentId/cancel [post]
+func (this *AppointmentController) Cancel() {
+	appointment := this.appointmentOf(this.getUser(), true)
+	if err := services.CancelAppointment(appointment); err != nil {
+		this.abortWithAppointmentError(err)
+	}
+	this.Data["json"] = appointment
+	this.ServeJSON()
+}
+
+// @Title Get for request
+// @Description get the appointments of a request
+// @Param	requestId		path 	int64	true		"The id of the request"
+// @Success 200 {object} []models.InspectionAppointment
+// @router /request/:requestId [get]
+func (this *AppointmentController) GetForRequest() {
+	requestId, err := this.GetInt64(":requestId")
+	if err != nil {
+		this.CustomAbort(400, "No Request Id provided")
+	}
+	user := this.getUser()
+	request := services.GetRequestById(requestId, false)
+	if request.Id == 0 {
+		this.CustomAbort(404, "Request not found")
+	}
+	farmer := request.User.Id == user.Id
+	inspector := request.Inspector != nil && request.Inspector.Id == user.Id
+	if !farmer && !inspector && (user.HasRole("Admin") || user.HasRole("Canton")) == false {
+		this.CustomAbort(401, "Unauthorized")
+	}
+
+	appointments, err := services.GetRequestAppointments(requestId, farmer)
+	if err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+	this.Data["json"] = appointments
+	this.ServeJSON()
+}
+
+// @Title Calendar
+// @Description get the appointments of an inspector, as iCalendar with format=ics
+// @Param	inspectorId		path 	int64	true		"The id of the inspector"
+// @Param	from	query	string	false	"first day, 2006-01-02, default today"
+// @Param	to	query	string	false	"day after the last day, 2006-01-02, default a year after from"
+// @Param	format	query	string	false	"ics for an iCalendar file"
+// @Success 200 {object} []models.InspectionAppointment
+// @router /calendar/:inspectorId [get]
+func (this *AppointmentController) Calendar() {
+	inspectorId, err := this.GetInt64(":inspectorId")
+	if err != nil {
+		this.CustomAbort(400, "No Inspector Id provided")
+	}
+	user := this.getUser()
+	if user.Id != inspectorId && (user.HasRole("Admin") || user.HasRole("Canton")) == false {
+		this.CustomAbort(401, "Unauthorized")
+	}
+
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if value := this.GetString("from"); value != "" {
+		if from, err = time.ParseInLocation("2006-01-02", value, time.Local); err != nil {
+			this.CustomAbort(400, "Invalid date "+value)
+		}
+	}
+	to := from.AddDate(1, 0, 0)
+	if value := this.GetString("to"); value != "" {
+		if to, err = time.ParseInLocation("2006-01-02", value, time.Local); err != nil {
+			this.CustomAbort(400, "Invalid date "+value)
+		}
+	}
+
+	appointments, err := services.GetInspectorCalendar(inspectorId, from, to)
+	if err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+	if this.GetString("format") == "ics" {
+		this.Ctx.Output.Header("Content-Type", "text/calendar; charset=utf-8")
+		this.Ctx.Output.Header("Content-Disposition", "attachment; filename=\"inspections.ics\"")
+		this.Ctx.WriteString(services.AppointmentsICalendar(appointments))
+		return
+	}
+	this.Data["json"] = appointments
+	this.ServeJSON()
+}
+
+// @Title Unannounced share
+// @Description get the share of unannounced inspections of the requests with a RAUS contribution
+// @Param	year	query	int	false	"year of the requests, default the current year"
+// @Success 200 {object} models.UnannouncedShare
+// @router /unannounced [get]
+func (this *AppointmentController) GetUnannouncedShare() {
+	user := this.getUser()
+	if (user.HasRole("Admin") || user.HasRole("Canton")) == false {
+		this.CustomAbort(401, "Unauthorized")
+	}
+	year, err := this.GetInt("year", time.Now().Year())
+	if err != nil {
+		this.CustomAbort(400, "Invalid year")
+	}
+	share, err := services.GetUnannouncedShare(year)
+	if err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+	this.Data["json"] = share
+	this.ServeJSON()
+}
+
+// appointmentOf loads an appointment the user takes part in. Only the inspector and
+// the admins may change it, the farmer sees it once it is announced or held.
+func (this *AppointmentController) appointmentOf(user *models.User, change bool) *models.InspectionAppointment {
+	appointmentId, err := this.GetInt64(":appointmentId")
+	if err != nil {
+		this.CustomAbort(400, "No Appointment Id provided")
+	}
+	appointment, err := services.GetAppointmentById(appointmentId)
+	if err != nil {
+		this.abortWithAppointmentError(err)
+	}
+	switch {
+	case user.HasRole("Admin") || user.HasRole("Canton") || appointment.Inspector.Id == user.Id:
+	case !change && appointment.Request.User.Id == user.Id && (!appointment.Unannounced || appointment.Status == models.AppointmentStatusHeld):
+	default:
+		this.CustomAbort(401, "Unauthorized")
+	}
+	return appointment
+}
+
+// abortWithAppointmentError answers with the status matching an error of the appointment services
+func (this *AppointmentController) abortWithAppointmentError(err error) {
+	switch err.(type) {
+	case *services.RequestValidationError:
+		this.CustomAbort(400, err.Error())
+	case *services.AppointmentError:
+		this.CustomAbort(409, err.Error())
+	}
+	if err == orm.ErrNoRows {
+		this.CustomAbort(404, err.Error())
+	}
+	this.CustomAbort(500, err.Error())
+}
@@ -53,10 +53,13 @@ func (this *ContributionController) Get() {
 }
 
 // @Title GetAll
-// @Description get all Contributions
-// @Success 200 {Object} models.Contribution
+// @Description get a page of the Contributions, filtered by code
+// @Param	offset	query	int	false	"index of the first item"
+// @Param	limit	query	int	false	"number of items, at most 500"
+// @Param	sort	query	string	false	"field to sort by, prefixed with - for descending order"
+// @Success 200 {Object} services.Page
 // @router / [get]
 func (this *ContributionController) GetAll() {
-	this.Data["json"] = services.GetAllContributions()
-	this.ServeJSON()
+	page, err := services.GetAllContributions(listQuery(&this.Controller))
+	servePage(&this.Controller, page, err)
 }
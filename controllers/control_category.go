@@ -33,10 +33,13 @@ func (this *ControlCategoryController) Post() {
 }
 
 // @Title GetAll
-// @Description get all Control Categories
-// @Success 200 {Object} models.ControlCategory
+// @Description get a page of the Control Categories, filtered by contribution
+// @Param	offset	query	int	false	"index of the first item"
+// @Param	limit	query	int	false	"number of items, at most 500"
+// @Param	sort	query	string	false	"field to sort by, prefixed with - for descending order"
+// @Success 200 {Object} services.Page
 // @router / [get]
 func (this *ControlCategoryController) GetAll() {
-	this.Data["json"] = services.GetAllControlCategories()
-	this.ServeJSON()
+	page, err := services.GetAllControlCategories(listQuery(&this.Controller))
+	servePage(&this.Controller, page, err)
 }
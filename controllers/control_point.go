@@ -33,10 +33,13 @@ func (this *ControlPointController) Post() {
 }
 
 // @Title GetAll
-// @Description get all Control Points
-// @Success 200 {Object} models.ControlPoint
+// @Description get a page of the Control Points, filtered by pointGroup
+// @Param	offset	query	int	false	"index of the first item"
+// @Param	limit	query	int	false	"number of items, at most 500"
+// @Param	sort	query	string	false	"field to sort by, prefixed with - for descending order"
+// @Success 200 {Object} services.Page
 // @router / [get]
 func (this *ControlPointController) GetAll() {
-	this.Data["json"] = services.GetAllControlPoints()
-	this.ServeJSON()
+	page, err := services.GetAllControlPoints(listQuery(&this.Controller))
+	servePage(&this.Controller, page, err)
 }
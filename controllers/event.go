@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/astaxie/beego"
+	"github.com/scmo/apayment-backend/services"
+)
+
+// Operations about Events
+type EventController struct {
+	beego.Controller
+}
+
+// @Title Event Ticket
+// @Description Issues a short-lived ticket to open the event stream once. EventSource cannot set headers, the ticket is passed as query parameter instead of the JWT token.
+// @Param   Authorization     header   string true       "JWT token"
+// @Success 200 {object} models.EventTicket
+// @router /ticket [post]
+func (this *EventController) Ticket() {
+	claims, err := services.ParseToken(this.Ctx.Request.Header.Get("Authorization"))
+	if err != nil {
+		this.CustomAbort(401, "Unauthorized")
+	}
+	user, err := services.GetUserByUsername(claims.Subject)
+	if err != nil {
+		this.CustomAbort(404, err.Error())
+	}
+	ticket, err := services.IssueEventTicket(user)
+	if err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+	this.Data["json"] = ticket
+	this.ServeJSON()
+}
+
+// @Title Event Stream
+// @Description Server-sent events about requests and payments the user may see
+// @Param   Authorization     header   string false       "JWT token"
+// @Param   ticket     query   string false       "Ticket from /event/ticket"
+// @Success 200 {object} models.Event
+// @router /stream [get]
+func (this *EventController) Stream() {
+	var username string
+	if token := this.Ctx.Request.Header.Get("Authorization"); token != "" {
+		claims, err := services.ParseToken(token)
+		if err != nil {
+			this.CustomAbort(401, "Unauthorized")
+		}
+		username = claims.Subject
+	} else {
+		var err error
+		username, err = services.RedeemEventTicket(this.GetString("ticket"))
+		if err != nil {
+			this.CustomAbort(401, "Unauthorized")
+		}
+	}
+	user, err := services.GetUserByUsername(username)
+	if err != nil {
+		this.CustomAbort(404, err.Error())
+	}
+
+	subscription := services.SubscribeEvents(user)
+	defer services.UnsubscribeEvents(subscription)
+
+	w := this.Ctx.ResponseWriter
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(200)
+	w.Flush()
+
+	keepAlive := time.NewTicker(30 * time.Second)
+	defer keepAlive.Stop()
+	closed := w.CloseNotify()
+	for {
+		select {
+		case event := <-subscription.Events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				beego.Error("Failed to marshal event: ", err)
+				continue
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Id, event.Type, data)
+			w.Flush()
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			w.Flush()
+		case <-closed:
+			return
+		}
+	}
+}
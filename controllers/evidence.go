@@ -0,0 +1,204 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/scmo/apayment-backend/ethereum"
+	"github.com/scmo/apayment-backend/models"
+	"github.com/scmo/apayment-backend/services"
+)
+
+// Operations about the evidence of lacks
+type EvidenceController struct {
+	beego.Controller
+}
+
+func (this *EvidenceController) getUser() *models.User {
+	claims, err := services.ParseToken(this.Ctx.Request.Header.Get("Authorization"))
+	if err != nil {
+		this.CustomAbort(401, "Unauthorized")
+	}
+	user, err := services.GetUserByUsername(claims.Subject)
+	if err != nil {
+		this.CustomAbort(404, err.Error())
+	}
+	return user
+}
+
+// @Title Upload
+// @Description Upload a photo or PDF as evidence of a lack, as multipart form
+// @Param	file	formData	file	true	"the photo or PDF"
+// @Param	requestId	formData	int64	true	"the id of the request"
+// @Param	contributionCode	formData	int	true	"the lack's contribution code"
+// @Param	controlCategoryId	formData	int64	true	"the lack's control category"
+// @Param	pointGroupCode	formData	int	true	"the lack's point group code"
+// @Param	controlPointId	formData	int64	true	"the lack's control point"
+// @Param	lackId	formData	int64	true	"the lack"
+// @Success 200 {object} models.Evidence
+// @router / [post]
+func (this *EvidenceController) Post() {
+	var evidence models.Evidence
+	var err error
+	if evidence.RequestId, err = this.GetInt64("requestId"); err != nil {
+		this.CustomAbort(400, "No Request Id provided")
+	}
+	contributionCode, err1 := this.GetUint16("contributionCode")
+	pointGroupCode, err2 := this.GetUint16("pointGroupCode")
+	controlCategoryId, err3 := this.GetInt64("controlCategoryId")
+	controlPointId, err4 := this.GetInt64("controlPointId")
+	lackId, err5 := this.GetInt64("lackId")
+	if err1 != nil || err2 != nil || err3 != nil || err4 != nil || err5 != nil {
+		this.CustomAbort(400, "The lack of the evidence is incomplete")
+	}
+	evidence.ContributionCode = contributionCode
+	evidence.ControlCategoryId = controlCategoryId
+	evidence.PointGroupCode = pointGroupCode
+	evidence.ControlPointId = controlPointId
+	evidence.LackId = lackId
+
+	this.requestOfInspector(evidence.RequestId)
+	file, header, err := this.GetFile("file")
+	if err != nil {
+		this.CustomAbort(400, "No file provided")
+	}
+	defer file.Close()
+	evidence.FileName = header.Filename
+
+	if err := services.AddEvidence(&evidence, file); err != nil {
+		this.abortWithEvidenceError(err)
+	}
+	this.Data["json"] = evidence
+	this.ServeJSON()
+}
+
+// @Title Get for request
+// @Description get the evidence of a request and the root hash anchored on chain
+// @Param	requestId		path 	int64	true		"The id of the request"
+// @Success 200 {object} models.EvidenceRecord
+// @router /request/:requestId [get]
+func (this *EvidenceController) GetForRequest() {
+	requestId, err := this.GetInt64(":requestId")
+	if err != nil {
+		this.CustomAbort(400, "No Request Id provided")
+	}
+	this.requestOfParticipant(requestId)
+	record, err := services.GetEvidenceRecord(requestId)
+	if err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+	this.Data["json"] = record
+	this.ServeJSON()
+}
+
+// @Title Download
+// @Description download the file of an evidence
+// @Param	evidenceId		path 	int64	true		"The id of the evidence"
+// @router /:evidenceId/file [get]
+func (this *EvidenceController) Download() {
+	evidence := this.evidence()
+	this.requestOfParticipant(evidence.RequestId)
+	data, err := services.ReadEvidenceFile(evidence)
+	if err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+	this.Ctx.Output.Header("Content-Type", evidence.ContentType)
+	this.Ctx.Output.Header("Content-Disposition", "attachment; filename="+strconv.Quote(evidence.FileName))
+	this.Ctx.Output.Body(data)
+}
+
+// @Title Delete
+// @Description delete evidence which is not anchored yet
+// @Param	evidenceId		path 	int64	true		"The id of the evidence"
+// @Success 200 {string} delete success!
+// @router /:evidenceId [delete]
+func (this *EvidenceController) Delete() {
+	evidence := this.evidence()
+	this.requestOfInspector(evidence.RequestId)
+	if err := services.DeleteEvidence(evidence); err != nil {
+		this.abortWithEvidenceError(err)
+	}
+	this.Data["json"] = "delete success!"
+	this.ServeJSON()
+}
+
+// @Title Anchor
+// @Description anchor the evidence of a request on chain, when it failed after the lacks were added
+// @Param	requestId		path 	int64	true		"The id of the request"
+// @Success 200 {object} models.EvidenceRecord
+// @router /anchor/:requestId [post]
+func (this *EvidenceController) Anchor() {
+	requestId, err := this.GetInt64(":requestId")
+	if err != nil {
+		this.CustomAbort(400, "No Request Id provided")
+	}
+	user, request := this.requestOfInspector(requestId)
+	auth, err := ethereum.GetAuth(user.EtherumAddress)
+	if err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+	if err := services.AnchorEvidence(request, auth); err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+	record, err := services.GetEvidenceRecord(requestId)
+	if err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+	this.Data["json"] = record
+	this.ServeJSON()
+}
+
+func (this *EvidenceController) evidence() *models.Evidence {
+	evidenceId, err := this.GetInt64(":evidenceId")
+	if err != nil {
+		this.CustomAbort(400, "No Evidence Id provided")
+	}
+	evidence, err := services.GetEvidenceById(evidenceId)
+	if err != nil {
+		this.abortWithEvidenceError(err)
+	}
+	return evidence
+}
+
+// requestOfInspector checks that the user is the inspector of the request or an admin
+func (this *EvidenceController) requestOfInspector(requestId int64) (*models.User, *models.Request) {
+	user := this.getUser()
+	request := services.GetRequestById(requestId, false)
+	if request.Id == 0 {
+		this.CustomAbort(404, "Request not found")
+	}
+	if user.HasRole("Admin") == false && (request.Inspector == nil || request.Inspector.Id != user.Id) {
+		this.CustomAbort(401, "Unauthorized")
+	}
+	return user, request
+}
+
+// requestOfParticipant checks that the user is the farmer or the inspector of the
+// request, an admin or the canton
+func (this *EvidenceController) requestOfParticipant(requestId int64) {
+	user := this.getUser()
+	request := services.GetRequestById(requestId, false)
+	if request.Id == 0 {
+		this.CustomAbort(404, "Request not found")
+	}
+	farmer := request.User != nil && request.User.Id == user.Id
+	inspector := request.Inspector != nil && request.Inspector.Id == user.Id
+	if !farmer && !inspector && (user.HasRole("Admin") || user.HasRole("Canton")) == false {
+		this.CustomAbort(401, "Unauthorized")
+	}
+}
+
+// abortWithEvidenceError answers with the status matching an error of the evidence services
+func (this *EvidenceController) abortWithEvidenceError(err error) {
+	switch err.(type) {
+	case *services.RequestValidationError:
+		this.CustomAbort(400, err.Error())
+	case *services.RequestTransitionError:
+		this.CustomAbort(409, err.Error())
+	}
+	if err == orm.ErrNoRows {
+		this.CustomAbort(404, err.Error())
+	}
+	this.CustomAbort(500, err.Error())
+}
@@ -33,10 +33,13 @@ func (this *LackController) Post() {
 }
 
 // @Title GetAll
-// @Description get all Lacks
-// @Success 200 {Object} models.Lack
+// @Description get a page of the Lacks, filtered by controlPoint
+// @Param	offset	query	int	false	"index of the first item"
+// @Param	limit	query	int	false	"number of items, at most 500"
+// @Param	sort	query	string	false	"field to sort by, prefixed with - for descending order"
+// @Success 200 {Object} services.Page
 // @router / [get]
 func (this *LackController) GetAll() {
-	this.Data["json"] = services.GetAllLacks()
-	this.ServeJSON()
+	page, err := services.GetAllLacks(listQuery(&this.Controller))
+	servePage(&this.Controller, page, err)
 }
@@ -10,10 +10,13 @@ type LegalFormController struct {
 }
 
 // @Title GetAll
-// @Description get all LegalForms
-// @Success 200 {Object} models.LegalForm
+// @Description get a page of the LegalForms
+// @Param	offset	query	int	false	"index of the first item"
+// @Param	limit	query	int	false	"number of items, at most 500"
+// @Param	sort	query	string	false	"field to sort by, prefixed with - for descending order"
+// @Success 200 {Object} services.Page
 // @router / [get]
 func (this *LegalFormController) GetAll() {
-	this.Data["json"] = services.GetAllLegalForms()
-	this.ServeJSON()
+	page, err := services.GetAllLegalForms(listQuery(&this.Controller))
+	servePage(&this.Controller, page, err)
 }
@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/astaxie/beego"
+	"github.com/scmo/apayment-backend/services"
+)
+
+// listQuery reads the page, the sort order and the filters of a list from the query string.
+// Every parameter besides offset, limit, sort and consistency is a filter.
+func listQuery(c *beego.Controller) *services.ListQuery {
+	query := &services.ListQuery{Filters: make(map[string]string)}
+	query.Offset, _ = c.GetInt64("offset", 0)
+	query.Limit, _ = c.GetInt64("limit", 0)
+	query.Sort = c.GetString("sort")
+	for name, values := range c.Ctx.Request.URL.Query() {
+		switch name {
+		case "offset", "limit", "sort", "consistency":
+			continue
+		}
+		query.Filters[name] = values[0]
+	}
+	return query
+}
+
+// servePage answers with the page envelope and the link to the next page
+func servePage(c *beego.Controller, page *services.Page, err error) {
+	if _, ok := err.(*services.ListQueryError); ok {
+		c.CustomAbort(400, err.Error())
+	} else if err != nil {
+		beego.Error("Error while loading list: ", err)
+		c.CustomAbort(500, err.Error())
+	}
+	if page.HasNext() {
+		next := *c.Ctx.Request.URL
+		values := next.Query()
+		values.Set("offset", strconv.FormatInt(page.Offset+page.Limit, 10))
+		values.Set("limit", strconv.FormatInt(page.Limit, 10))
+		next.RawQuery = values.Encode()
+		page.Next = next.RequestURI()
+	}
+	c.Data["json"] = page
+	c.ServeJSON()
+}
@@ -0,0 +1,230 @@
+package controllers
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/scmo/apayment-backend/ethereum"
+	"github.com/scmo/apayment-backend/models"
+	"github.com/scmo/apayment-backend/services"
+)
+
+// Operations about objections of farmers against lacks
+type ObjectionController struct {
+	beego.Controller
+}
+
+func (this *ObjectionController) getUser() *models.User {
+	claims, err := services.ParseToken(this.Ctx.Request.Header.Get("Authorization"))
+	if err != nil {
+		this.CustomAbort(401, "Unauthorized")
+	}
+	user, err := services.GetUserByUsername(claims.Subject)
+	if err != nil {
+		this.CustomAbort(404, err.Error())
+	}
+	return user
+}
+
+// @Title File
+// @Description file an objection against lacks of an inspected request, the payment is blocked until it is settled
+// @Param	body		body 	models.Objection	true		"requestId, justification and the lackIndexes objected to"
+// @Success 200 {object} models.Objection
+// @router / [post]
+func (this *ObjectionController) Post() {
+	var objection models.Objection
+	if err := json.Unmarshal(this.Ctx.Input.RequestBody, &objection); err != nil {
+		this.CustomAbort(400, err.Error())
+	}
+	user := this.getUser()
+	request := services.GetRequestById(objection.RequestId, false)
+	if request.Id == 0 {
+		this.CustomAbort(404, "Request not found")
+	}
+	if request.User == nil || request.User.Id != user.Id {
+		this.CustomAbort(401, "Unauthorized")
+	}
+	if err := services.FileObjection(&objection); err != nil {
+		this.abortWithObjectionError(err)
+	}
+	this.Data["json"] = objection
+	this.ServeJSON()
+}
+
+// @Title Attach
+// @Description attach a photo or PDF to an open objection, as multipart form
+// @Param	objectionId		path 	int64	true		"The id of the objection"
+// @Param	file	formData	file	true	"the photo or PDF"
+// @Success 200 {object} models.ObjectionAttachment
+// @router /:objectionId/attachment [post]
+func (this *ObjectionController) Attach() {
+	objection := this.objection()
+	user := this.getUser()
+	if objection.Farmer == nil || objection.Farmer.Id != user.Id {
+		this.CustomAbort(401, "Unauthorized")
+	}
+	file, header, err := this.GetFile("file")
+	if err != nil {
+		this.CustomAbort(400, "No file provided")
+	}
+	defer file.Close()
+	attachment, err := services.AddObjectionAttachment(objection, header.Filename, file)
+	if err != nil {
+		this.abortWithObjectionError(err)
+	}
+	this.Data["json"] = attachment
+	this.ServeJSON()
+}
+
+// @Title Download attachment
+// @Description download the file attached to an objection
+// @Param	attachmentId		path 	int64	true		"The id of the attachment"
+// @router /attachment/:attachmentId/file [get]
+func (this *ObjectionController) Download() {
+	attachmentId, err := this.GetInt64(":attachmentId")
+	if err != nil {
+		this.CustomAbort(400, "No Attachment Id provided")
+	}
+	attachment, err := services.GetObjectionAttachmentById(attachmentId)
+	if err != nil {
+		this.abortWithObjectionError(err)
+	}
+	objection, err := services.GetObjectionById(attachment.Objection.Id)
+	if err != nil {
+		this.abortWithObjectionError(err)
+	}
+	this.requestOfParticipant(objection.RequestId)
+	data, err := services.ReadObjectionAttachment(attachment)
+	if err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+	this.Ctx.Output.Header("Content-Type", attachment.ContentType)
+	this.Ctx.Output.Header("Content-Disposition", "attachment; filename="+strconv.Quote(attachment.FileName))
+	this.Ctx.Output.Body(data)
+}
+
+// @Title Get
+// @Description get an objection with its lacks and attachments
+// @Param	objectionId		path 	int64	true		"The id of the objection"
+// @Success 200 {object} models.Objection
+// @router /:objectionId [get]
+func (this *ObjectionController) Get() {
+	objection := this.objection()
+	this.requestOfParticipant(objection.RequestId)
+	this.Data["json"] = objection
+	this.ServeJSON()
+}
+
+// @Title Get for request
+// @Description get the objections against the lacks of a request
+// @Param	requestId		path 	int64	true		"The id of the request"
+// @Success 200 {object} []models.Objection
+// @router /request/:requestId [get]
+func (this *ObjectionController) GetForRequest() {
+	requestId, err := this.GetInt64(":requestId")
+	if err != nil {
+		this.CustomAbort(400, "No Request Id provided")
+	}
+	this.requestOfParticipant(requestId)
+	objections, err := services.GetRequestObjections(requestId)
+	if err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+	this.Data["json"] = objections
+	this.ServeJSON()
+}
+
+// @Title Review
+// @Description decide on an open objection: uphold the lacks or remove them, the removed lacks are reversed in the contract with the reason
+// @Param	objectionId		path 	int64	true		"The id of the objection"
+// @Param	body		body 	models.ObjectionReview	true		"decision, reason and optionally the lackIndexes to remove"
+// @Success 200 {object} models.Objection
+// @router /:objectionId/review [post]
+func (this *ObjectionController) Review() {
+	var review models.ObjectionReview
+	if err := json.Unmarshal(this.Ctx.Input.RequestBody, &review); err != nil {
+		this.CustomAbort(400, err.Error())
+	}
+	user := this.cantonUser()
+	objection := this.objection()
+	auth, err := ethereum.GetAuth(user.EtherumAddress)
+	if err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+	if err := services.ReviewObjection(objection, &review, user, auth); err != nil {
+		this.abortWithObjectionError(err)
+	}
+	this.Data["json"] = objection
+	this.ServeJSON()
+}
+
+// @Title Retry reversals
+// @Description send the reversals of removed lacks again, which were not sent or failed
+// @Param	objectionId		path 	int64	true		"The id of the objection"
+// @Success 200 {object} models.Objection
+// @router /:objectionId/reversals [post]
+func (this *ObjectionController) RetryReversals() {
+	user := this.cantonUser()
+	objection := this.objection()
+	auth, err := ethereum.GetAuth(user.EtherumAddress)
+	if err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+	if err := services.RetryLackReversals(objection, auth); err != nil {
+		this.abortWithObjectionError(err)
+	}
+	this.Data["json"] = objection
+	this.ServeJSON()
+}
+
+func (this *ObjectionController) objection() *models.Objection {
+	objectionId, err := this.GetInt64(":objectionId")
+	if err != nil {
+		this.CustomAbort(400, "No Objection Id provided")
+	}
+	objection, err := services.GetObjectionById(objectionId)
+	if err != nil {
+		this.abortWithObjectionError(err)
+	}
+	return objection
+}
+
+// cantonUser checks that the user decides for the canton
+func (this *ObjectionController) cantonUser() *models.User {
+	user := this.getUser()
+	if (user.HasRole("Admin") || user.HasRole("Canton")) == false {
+		this.CustomAbort(401, "Unauthorized")
+	}
+	return user
+}
+
+// requestOfParticipant checks that the user is the farmer or the inspector of the
+// request, an admin or the canton
+func (this *ObjectionController) requestOfParticipant(requestId int64) {
+	user := this.getUser()
+	request := services.GetRequestById(requestId, false)
+	if request.Id == 0 {
+		this.CustomAbort(404, "Request not found")
+	}
+	farmer := request.User != nil && request.User.Id == user.Id
+	inspector := request.Inspector != nil && request.Inspector.Id == user.Id
+	if !farmer && !inspector && (user.HasRole("Admin") || user.HasRole("Canton")) == false {
+		this.CustomAbort(401, "Unauthorized")
+	}
+}
+
+// abortWithObjectionError answers with the status matching an error of the objection services
+func (this *ObjectionController) abortWithObjectionError(err error) {
+	switch err.(type) {
+	case *services.RequestValidationError:
+		this.CustomAbort(400, err.Error())
+	case *services.ObjectionDecidedError, *services.ContractOutdatedError:
+		this.CustomAbort(409, err.Error())
+	}
+	if err == orm.ErrNoRows {
+		this.CustomAbort(404, err.Error())
+	}
+	this.CustomAbort(500, err.Error())
+}
@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"encoding/json"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/scmo/apayment-backend/models"
+	"github.com/scmo/apayment-backend/services"
+)
+
+// Operations about paying the due tranches of all requests of a year at once
+type PaymentRunController struct {
+	beego.Controller
+}
+
+// @Title Dry run
+// @Description start listing the transfers of the next due tranche of every request of a year which is not blocked, nothing is sent. The run is planning until the dry run finished.
+// @Param	body		body 	models.PaymentRun	true		"year and optionally the canton"
+// @Success 202 {object} models.PaymentRun
+// @router / [post]
+func (this *PaymentRunController) Post() {
+	var run models.PaymentRun
+	if err := json.Unmarshal(this.Ctx.Input.RequestBody, &run); err != nil {
+		this.CustomAbort(400, err.Error())
+	}
+	user := this.cantonUser()
+	if err := services.PlanPaymentRun(&run, user); err != nil {
+		this.abortWithPaymentRunError(err)
+	}
+	this.Ctx.Output.SetStatus(202)
+	this.Data["json"] = run
+	this.ServeJSON()
+}
+
+// @Title GetAll
+// @Description get a page of the payment runs with their progress, filtered by year and canton
+// @Param	offset	query	int	false	"index of the first item"
+// @Param	limit	query	int	false	"number of items, at most 500"
+// @Param	sort	query	string	false	"created, year or canton, prefixed with - for descending order"
+// @Param	year	query	int	false	"year of the requests"
+// @Param	canton	query	string	false	"shortname of the canton"
+// @Success 200 {object} services.Page
+// @router / [get]
+func (this *PaymentRunController) GetAll() {
+	this.cantonUser()
+	page, err := services.GetAllPaymentRuns(listQuery(&this.Controller))
+	servePage(&this.Controller, page, err)
+}
+
+// @Title Get
+// @Description get a payment run with its transfers and the progress of sending them
+// @Param	runId		path 	int64	true		"The id of the payment run"
+// @Success 200 {object} models.PaymentRun
+// @router /:runId [get]
+func (this *PaymentRunController) Get() {
+	this.cantonUser()
+	this.Data["json"] = this.run()
+	this.ServeJSON()
+}
+
+// @Title Submit
+// @Description propose the transfers of the dry run, they are sent once another employee approved the payout proposal
+// @Param	runId		path 	int64	true		"The id of the payment run"
+// @Success 200 {object} models.PaymentRun
+// @Failure 409 the payment run is submitted already or its dry run is not finished
+// @router /:runId/submit [post]
+func (this *PaymentRunController) Submit() {
+	user := this.cantonUser()
+	run := this.run()
+	if err := services.SubmitPaymentRun(run, user); err != nil {
+		this.abortWithPaymentRunError(err)
+	}
+	this.Data["json"] = run
+	this.ServeJSON()
+}
+
+// @Title Get report
+// @Description get the summary of a payment run: who prepared and approved it, the amounts sent, the failed and the blocked requests
+// @Param	runId		path 	int64	true		"The id of the payment run"
+// @Success 200 {object} models.PaymentRunReport
+// @router /:runId/report [get]
+func (this *PaymentRunController) GetReport() {
+	this.cantonUser()
+	runId, err := this.GetInt64(":runId")
+	if err != nil {
+		this.CustomAbort(400, "No Run Id provided")
+	}
+	report, err := services.GetPaymentRunReport(runId)
+	if err != nil {
+		this.abortWithPaymentRunError(err)
+	}
+	this.Data["json"] = report
+	this.ServeJSON()
+}
+
+func (this *PaymentRunController) run() *models.PaymentRun {
+	runId, err := this.GetInt64(":runId")
+	if err != nil {
+		this.CustomAbort(400, "No Run Id provided")
+	}
+	run, err := services.GetPaymentRunById(runId)
+	if err != nil {
+		this.abortWithPaymentRunError(err)
+	}
+	return run
+}
+
+// cantonUser checks that the user is an admin or works for the canton
+func (this *PaymentRunController) cantonUser() *models.User {
+	claims, err := services.ParseToken(this.Ctx.Request.Header.Get("Authorization"))
+	if err != nil {
+		this.CustomAbort(401, "Unauthorized")
+	}
+	user, err := services.GetUserByUsername(claims.Subject)
+	if err != nil {
+		this.CustomAbort(404, err.Error())
+	}
+	if (user.HasRole("Admin") || user.HasRole("Canton")) == false {
+		this.CustomAbort(401, "Unauthorized")
+	}
+	return user
+}
+
+// abortWithPaymentRunError answers with the status matching an error of the payment run services
+func (this *PaymentRunController) abortWithPaymentRunError(err error) {
+	switch err.(type) {
+	case *services.RequestValidationError:
+		this.CustomAbort(400, err.Error())
+	case *services.PaymentRunSubmittedError, *services.PaymentRunPlanningError:
+		this.CustomAbort(409, err.Error())
+	}
+	if err == orm.ErrNoRows {
+		this.CustomAbort(404, err.Error())
+	}
+	this.CustomAbort(500, err.Error())
+}
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"encoding/json"
+
+	"github.com/astaxie/beego"
+	"github.com/scmo/apayment-backend/models"
+	"github.com/scmo/apayment-backend/services"
+)
+
+// Operations about the tranches the cantons pay the requests in
+type PaymentScheduleController struct {
+	beego.Controller
+}
+
+// @Title Put
+// @Description store the tranches of a canton for a year, the last one pays the remainder. It cannot be changed once requests were paid with it
+// @Param	body		body 	models.PaymentSchedule	true		"canton, year and the tranches in the order they are paid"
+// @Success 200 {object} models.PaymentSchedule
+// @Failure 400 schedule is invalid
+// @router / [put]
+func (this *PaymentScheduleController) Put() {
+	var schedule models.PaymentSchedule
+	if err := json.Unmarshal(this.Ctx.Input.RequestBody, &schedule); err != nil {
+		this.CustomAbort(400, err.Error())
+	}
+	user := this.cantonUser()
+	if err := services.SetPaymentSchedule(&schedule, user.EtherumAddress); err != nil {
+		if _, ok := err.(*services.RequestValidationError); ok {
+			this.CustomAbort(400, err.Error())
+		}
+		this.CustomAbort(500, err.Error())
+	}
+	this.Data["json"] = schedule
+	this.ServeJSON()
+}
+
+// @Title Get
+// @Description get the tranches of a canton for a year, the two tranches of the contract when none are stored
+// @Param	canton		path 	string	true		"shortname of the canton"
+// @Param	year		path 	int	true		"year of the requests"
+// @Success 200 {object} models.PaymentSchedule
+// @router /:canton/:year [get]
+func (this *PaymentScheduleController) Get() {
+	this.cantonUser()
+	year, err := this.GetInt(":year")
+	if err != nil {
+		this.CustomAbort(400, "Invalid year")
+	}
+	schedule, err := services.GetPaymentSchedule(this.GetString(":canton"), year)
+	if err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+	this.Data["json"] = schedule
+	this.ServeJSON()
+}
+
+// cantonUser checks that the user is an admin or works for the canton
+func (this *PaymentScheduleController) cantonUser() *models.User {
+	claims, err := services.ParseToken(this.Ctx.Request.Header.Get("Authorization"))
+	if err != nil {
+		this.CustomAbort(401, "Unauthorized")
+	}
+	user, err := services.GetUserByUsername(claims.Subject)
+	if err != nil {
+		this.CustomAbort(404, err.Error())
+	}
+	if (user.HasRole("Admin") || user.HasRole("Canton")) == false {
+		this.CustomAbort(401, "Unauthorized")
+	}
+	return user
+}
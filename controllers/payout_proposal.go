@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"encoding/json"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/scmo/apayment-backend/models"
+	"github.com/scmo/apayment-backend/services"
+)
+
+// Operations about the payouts prepared by one employee of the canton and approved by another
+type PayoutProposalController struct {
+	beego.Controller
+}
+
+// @Title Prepare
+// @Description propose to pay the next due tranche of requests, two approvals are needed above the thresholds
+// @Param	body		body 	models.PayoutProposal	true		"note and the items with the requestId and optionally the tranche"
+// @Param	Idempotency-Key		header 	string	false		"repeating a proposal with the key returns the proposal stored the first time"
+// @Success 200 {object} models.PayoutProposal
+// @Failure 409 a tranche is not due or a payment is not settled
+// @router / [post]
+func (this *PayoutProposalController) Post() {
+	var proposal models.PayoutProposal
+	if err := json.Unmarshal(this.Ctx.Input.RequestBody, &proposal); err != nil {
+		this.CustomAbort(400, err.Error())
+	}
+	user := this.cantonUser()
+	proposal.IdempotencyKey = this.Ctx.Request.Header.Get("Idempotency-Key")
+	if err := services.PrepareProposal(&proposal, user); err != nil {
+		this.abortWithPayoutError(err)
+	}
+	this.Data["json"] = proposal
+	this.ServeJSON()
+}
+
+// @Title GetAll
+// @Description get a page of the payout proposals with their items and decisions, filtered by status
+// @Param	offset	query	int	false	"index of the first item"
+// @Param	limit	query	int	false	"number of items, at most 500"
+// @Param	sort	query	string	false	"created, status or total, prefixed with - for descending order"
+// @Param	status	query	string	false	"proposed, approved, executed or rejected"
+// @Success 200 {object} services.Page
+// @router / [get]
+func (this *PayoutProposalController) GetAll() {
+	this.cantonUser()
+	page, err := services.GetAllPayoutProposals(listQuery(&this.Controller))
+	servePage(&this.Controller, page, err)
+}
+
+// @Title Get
+// @Description get a payout proposal with who prepared and who decided on it
+// @Param	proposalId		path 	int64	true		"The id of the proposal"
+// @Success 200 {object} models.PayoutProposal
+// @router /:proposalId [get]
+func (this *PayoutProposalController) Get() {
+	this.cantonUser()
+	this.Data["json"] = this.proposal()
+	this.ServeJSON()
+}
+
+// @Title Approve
+// @Description approve a payout proposal prepared by another employee, the transfers are sent in the background after the last approval needed
+// @Param	proposalId		path 	int64	true		"The id of the proposal"
+// @Success 200 {object} models.PayoutProposal
+// @Failure 403 the proposal was prepared or approved by the user
+// @router /:proposalId/approve [post]
+func (this *PayoutProposalController) Approve() {
+	this.decide(&models.PayoutDecision{Decision: models.PayoutDecisionApprove})
+}
+
+// @Title Reject
+// @Description reject a payout proposal prepared by another employee, none of its transfers is sent
+// @Param	proposalId		path 	int64	true		"The id of the proposal"
+// @Param	body		body 	models.PayoutDecision	true		"reason of the rejection"
+// @Success 200 {object} models.PayoutProposal
+// @Failure 403 the proposal was prepared or decided on by the user
+// @router /:proposalId/reject [post]
+func (this *PayoutProposalController) Reject() {
+	var decision models.PayoutDecision
+	if err := json.Unmarshal(this.Ctx.Input.RequestBody, &decision); err != nil {
+		this.CustomAbort(400, err.Error())
+	}
+	decision.Decision = models.PayoutDecisionReject
+	this.decide(&decision)
+}
+
+func (this *PayoutProposalController) decide(decision *models.PayoutDecision) {
+	user := this.cantonUser()
+	proposal := this.proposal()
+	if err := services.DecideProposal(proposal, decision, user); err != nil {
+		this.abortWithPayoutError(err)
+	}
+	this.Data["json"] = proposal
+	this.ServeJSON()
+}
+
+func (this *PayoutProposalController) proposal() *models.PayoutProposal {
+	proposalId, err := this.GetInt64(":proposalId")
+	if err != nil {
+		this.CustomAbort(400, "No Proposal Id provided")
+	}
+	proposal, err := services.GetPayoutProposalById(proposalId)
+	if err != nil {
+		this.abortWithPayoutError(err)
+	}
+	return proposal
+}
+
+// cantonUser checks that the user is an admin or works for the canton
+func (this *PayoutProposalController) cantonUser() *models.User {
+	claims, err := services.ParseToken(this.Ctx.Request.Header.Get("Authorization"))
+	if err != nil {
+		this.CustomAbort(401, "Unauthorized")
+	}
+	user, err := services.GetUserByUsername(claims.Subject)
+	if err != nil {
+		this.CustomAbort(404, err.Error())
+	}
+	if (user.HasRole("Admin") || user.HasRole("Canton")) == false {
+		this.CustomAbort(401, "Unauthorized")
+	}
+	return user
+}
+
+// abortWithPayoutError answers with the status matching an error of the payout services
+func (this *PayoutProposalController) abortWithPayoutError(err error) {
+	switch err.(type) {
+	case *services.RequestValidationError:
+		this.CustomAbort(400, err.Error())
+	case *services.FourEyesError:
+		this.CustomAbort(403, err.Error())
+	case *services.PayoutProposalDecidedError, *services.RequestTransitionError, *services.TrancheNotDueError, *services.ObjectionPendingError, *services.ReconciliationFlaggedError, *services.ReconciliationMissingError:
+		this.CustomAbort(409, err.Error())
+	case *services.IdempotencyKeyError:
+		this.CustomAbort(422, err.Error())
+	}
+	if err == orm.ErrNoRows {
+		this.CustomAbort(404, err.Error())
+	}
+	this.CustomAbort(500, err.Error())
+}
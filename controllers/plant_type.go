@@ -10,10 +10,13 @@ type PlantTypeController struct {
 }
 
 // @Title GetAll
-// @Description get all PlantTypes
-// @Success 200 {Object} models.PlantType
+// @Description get a page of the PlantTypes
+// @Param	offset	query	int	false	"index of the first item"
+// @Param	limit	query	int	false	"number of items, at most 500"
+// @Param	sort	query	string	false	"field to sort by, prefixed with - for descending order"
+// @Success 200 {Object} services.Page
 // @router / [get]
 func (this *PlantTypeController) GetAll() {
-	this.Data["json"] = services.GetAllPlantTypes()
-	this.ServeJSON()
+	page, err := services.GetAllPlantTypes(listQuery(&this.Controller))
+	servePage(&this.Controller, page, err)
 }
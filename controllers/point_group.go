@@ -33,10 +33,13 @@ func (this *PointGroupController) Post() {
 }
 
 // @Title GetAll
-// @Description get all Control Categories
-// @Success 200 {Object} models.ControlCategory
+// @Description get a page of the Point Groups, filtered by controlCategory and pointGroupCode
+// @Param	offset	query	int	false	"index of the first item"
+// @Param	limit	query	int	false	"number of items, at most 500"
+// @Param	sort	query	string	false	"field to sort by, prefixed with - for descending order"
+// @Success 200 {Object} services.Page
 // @router / [get]
 func (this *PointGroupController) GetAll() {
-	this.Data["json"] = services.GetAllPointGroups()
-	this.ServeJSON()
+	page, err := services.GetAllPointGroups(listQuery(&this.Controller))
+	servePage(&this.Controller, page, err)
 }
@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/scmo/apayment-backend/services"
+)
+
+// Operations about the reconciliation of the contracts with the off-chain calculation
+type ReconciliationController struct {
+	beego.Controller
+}
+
+// @Title GetAll
+// @Description get a page of the latest reconciliations of the requests with their discrepancies, filtered by status and request
+// @Param	offset	query	int	false	"index of the first item"
+// @Param	limit	query	int	false	"number of items, at most 500"
+// @Param	sort	query	string	false	"checked or status, prefixed with - for descending order"
+// @Param	status	query	string	false	"matched or flagged"
+// @Success 200 {object} services.Page
+// @router / [get]
+func (this *ReconciliationController) GetAll() {
+	this.cantonUser()
+	page, err := services.GetAllReconciliations(listQuery(&this.Controller))
+	servePage(&this.Controller, page, err)
+}
+
+// @Title Get for request
+// @Description get the latest reconciliation of a request
+// @Param	requestId		path 	int64	true		"The id of the request"
+// @Success 200 {object} models.RequestReconciliation
+// @router /request/:requestId [get]
+func (this *ReconciliationController) GetForRequest() {
+	this.cantonUser()
+	requestId, err := this.GetInt64(":requestId")
+	if err != nil {
+		this.CustomAbort(400, "No Request Id provided")
+	}
+	reconciliation, err := services.GetRequestReconciliation(requestId)
+	if err == orm.ErrNoRows {
+		this.CustomAbort(404, "Request was not reconciled yet")
+	} else if err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+	this.Data["json"] = reconciliation
+	this.ServeJSON()
+}
+
+// @Title Reconcile
+// @Description reconcile a request now, a matching reconciliation unblocks its payment
+// @Param	requestId		path 	int64	true		"The id of the request"
+// @Success 200 {object} models.RequestReconciliation
+// @router /request/:requestId [post]
+func (this *ReconciliationController) Reconcile() {
+	this.cantonUser()
+	requestId, err := this.GetInt64(":requestId")
+	if err != nil {
+		this.CustomAbort(400, "No Request Id provided")
+	}
+	request := services.GetRequestById(requestId, false)
+	if request.Id == 0 {
+		this.CustomAbort(404, "Request not found")
+	}
+	reconciliation, err := services.ReconcileRequest(request)
+	if _, ok := err.(*services.RequestValidationError); ok {
+		this.CustomAbort(400, err.Error())
+	} else if err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+	this.Data["json"] = reconciliation
+	this.ServeJSON()
+}
+
+// cantonUser checks that the user is an admin or works for the canton
+func (this *ReconciliationController) cantonUser() {
+	claims, err := services.ParseToken(this.Ctx.Request.Header.Get("Authorization"))
+	if err != nil {
+		this.CustomAbort(401, "Unauthorized")
+	}
+	user, err := services.GetUserByUsername(claims.Subject)
+	if err != nil {
+		this.CustomAbort(404, err.Error())
+	}
+	if (user.HasRole("Admin") || user.HasRole("Canton")) == false {
+		this.CustomAbort(401, "Unauthorized")
+	}
+}
@@ -1,6 +1,8 @@
 package controllers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"github.com/astaxie/beego"
 	"github.com/scmo/apayment-backend/ethereum"
@@ -31,10 +33,162 @@ func (this *RequestController) Post() {
 	}
 	request.User = user
 
-	err = services.CreateRequest(&request, ethereum.GetAuth(user.EtherumAddress))
+	auth, err := ethereum.GetAuth(user.EtherumAddress)
 	if err != nil {
 		this.CustomAbort(500, err.Error())
 	}
+	err = services.CreateRequest(&request, auth)
+	if err != nil {
+		this.abortWithRequestError(err)
+	}
+	this.ServeJSON()
+}
+
+// @Title Create a Draft
+// @Description Store a new Request without deploying its contract, it can be edited until it is submitted
+// @Param	body		body 	models.Request	true		"body for request content"
+// @Success 200 {Object} models.Request
+// @router /draft [post]
+func (this *RequestController) PostDraft() {
+	var request models.Request
+	json.Unmarshal(this.Ctx.Input.RequestBody, &request)
+
+	claims, _ := services.ParseToken(this.Ctx.Request.Header.Get("Authorization"))
+	user, err := services.GetUserByUsername(claims.Subject)
+	if err != nil {
+		this.CustomAbort(404, err.Error())
+	}
+	if user.HasRole("Farmer") == false {
+		this.CustomAbort(401, "Unauthorized")
+	}
+	request.User = user
+
+	err = services.CreateDraft(&request)
+	if err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+	this.Data["json"] = request
+	this.ServeJSON()
+}
+
+// @Title Update a Draft
+// @Description Replace the contributions and the remark of a draft
+// @Param	body		body 	models.Request	true		"body for request content"
+// @Success 200 {Object} models.Request
+// @router /draft [put]
+func (this *RequestController) PutDraft() {
+	var request models.Request
+	json.Unmarshal(this.Ctx.Input.RequestBody, &request)
+
+	this.requestOfFarmer(request.Id)
+	err := services.UpdateDraft(&request)
+	if err != nil {
+		this.abortWithRequestError(err)
+	}
+	this.Data["json"] = request
+	this.ServeJSON()
+}
+
+// @Title Validate
+// @Description Check the content of a request without storing it
+// @Param	body		body 	models.Request	true		"body for request content"
+// @Success 200 {Object} models.Request
+// @Failure 400 content is invalid
+// @router /validate [post]
+func (this *RequestController) Validate() {
+	var request models.Request
+	json.Unmarshal(this.Ctx.Input.RequestBody, &request)
+
+	err := services.ValidateRequest(&request)
+	if err != nil {
+		this.abortWithRequestError(err)
+	}
+	this.Data["json"] = request
+	this.ServeJSON()
+}
+
+// @Title Preview
+// @Description calculate the point groups and payments with hypothetical lacks, like the contract would. Amounts of the point groups are multiplied by 10'000 as in the contract
+// @Param	body		body 	models.RequestPreview	true		"contributionCodes, gve and lacks, or the requestId of an inspected request and the lacks to add"
+// @Success 200 {object} calculation.Result
+// @Failure 400 content is invalid
+// @router /preview [post]
+func (this *RequestController) Preview() {
+	var preview models.RequestPreview
+	if err := json.Unmarshal(this.Ctx.Input.RequestBody, &preview); err != nil {
+		this.CustomAbort(400, err.Error())
+	}
+	claims, _ := services.ParseToken(this.Ctx.Request.Header.Get("Authorization"))
+	user, err := services.GetUserByUsername(claims.Subject)
+	if err != nil {
+		this.CustomAbort(404, err.Error())
+	}
+	if preview.RequestId != 0 {
+		this.requestOfParticipant(preview.RequestId)
+	}
+	result, err := services.PreviewPayments(&preview, user)
+	if err != nil {
+		this.abortWithRequestError(err)
+	}
+	this.Data["json"] = result
+	this.ServeJSON()
+}
+
+// @Title Submit a Draft
+// @Description Validate a draft and deploy its contract
+// @Param	body		body 	models.Request	true		"body with the id of the draft"
+// @Success 200 {Object} models.Request
+// @Failure 400 content is invalid
+// @router /submit [post]
+func (this *RequestController) Submit() {
+	var r models.Request
+	json.Unmarshal(this.Ctx.Input.RequestBody, &r)
+
+	user := this.requestOfFarmer(r.Id).User
+	auth, err := ethereum.GetAuth(user.EtherumAddress)
+	if err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+	request, err := services.SubmitRequest(r.Id, auth)
+	if err != nil {
+		this.abortWithRequestError(err)
+	}
+	this.Data["json"] = request
+	this.ServeJSON()
+}
+
+// @Title Amend
+// @Description The farmer replaces the contract of a request which has not been inspected yet
+// @Param	body		body 	models.Request	true		"body for request content"
+// @Success 200 {Object} models.Request
+// @Failure 400 content is invalid
+// @router /amend [put]
+func (this *RequestController) Amend() {
+	var r models.Request
+	json.Unmarshal(this.Ctx.Input.RequestBody, &r)
+
+	claims, _ := services.ParseToken(this.Ctx.Request.Header.Get("Authorization"))
+	user, err := services.GetUserByUsername(claims.Subject)
+	if err != nil {
+		this.CustomAbort(404, err.Error())
+	}
+	stored := services.GetRequestById(r.Id, false)
+	if stored.Id == 0 {
+		this.CustomAbort(404, "Request not found")
+	}
+	// the old contract can only be killed by its owner, the farmer
+	if stored.User.Id != user.Id {
+		this.CustomAbort(401, "Unauthorized")
+	}
+	auth, err := ethereum.GetAuth(user.EtherumAddress)
+	if err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+	request, err := services.AmendRequest(&r, auth)
+	if err != nil {
+		this.abortWithRequestError(err)
+	}
+	this.Data["json"] = request
 	this.ServeJSON()
 }
 
@@ -70,11 +224,17 @@ func (this *RequestController) Get() {
 }
 
 // @Title GetAll
-// @Description get all request
-// @Success 200 {object} models.Request
+// @Description get a page of the requests, filtered by year, status, contribution, inspector, canton, createdFrom and createdTo
+// @Param	offset	query	int	false	"index of the first item"
+// @Param	limit	query	int	false	"number of items, at most 500"
+// @Param	sort	query	string	false	"field to sort by, prefixed with - for descending order"
+// @Param	consistency	query	string	false	"chain to read the requests from their contracts"
+// @Success 200 {object} services.Page
 // @router / [get]
 func (this *RequestController) GetAll() {
-	requests := []*models.Request{}
+	var page *services.Page
+	query := listQuery(&this.Controller)
+	chain := this.GetString("consistency") == services.ConsistencyChain
 
 	claims, _ := services.ParseToken(this.Ctx.Request.Header.Get("Authorization"))
 	user, err := services.GetUserByUsername(claims.Subject)
@@ -83,23 +243,27 @@ func (this *RequestController) GetAll() {
 	}
 
 	if user.HasRole("Farmer") {
-		requests = services.GetAllRequestsByUserId(user.Id)
+		page, err = services.GetAllRequestsByUserId(user.Id, query, chain)
 	} else if user.HasRole("Admin") || user.HasRole("Canton") {
-		requests = services.GetAllRequests()
+		page, err = services.GetAllRequests(query, chain)
 	} else {
 		this.CustomAbort(401, "Unauthorized")
 	}
-
-	this.Data["json"] = requests
-	this.ServeJSON()
+	servePage(&this.Controller, page, err)
 }
 
 // @Title GetAll
-// @Description get all request which have an inspector assigned
-// @Success 200 {object} models.Request
+// @Description get a page of the requests which have an inspector assigned, filtered like the list of all requests
+// @Param	offset	query	int	false	"index of the first item"
+// @Param	limit	query	int	false	"number of items, at most 500"
+// @Param	sort	query	string	false	"field to sort by, prefixed with - for descending order"
+// @Param	consistency	query	string	false	"chain to read the requests from their contracts"
+// @Success 200 {object} services.Page
 // @router /inspection [get]
 func (this *RequestController) GetAllForInspection() {
-	requests := []*models.Request{}
+	var page *services.Page
+	query := listQuery(&this.Controller)
+	chain := this.GetString("consistency") == services.ConsistencyChain
 	claims, _ := services.ParseToken(this.Ctx.Request.Header.Get("Authorization"))
 	user, err := services.GetUserByUsername(claims.Subject)
 	if err != nil {
@@ -107,15 +271,13 @@ func (this *RequestController) GetAllForInspection() {
 	}
 
 	if user.HasRole("Inspector") {
-		requests = services.GetAllRequestsForInspectionByInspectorId(user.Id)
+		page, err = services.GetAllRequestsForInspectionByInspectorId(user.Id, query, chain)
 	} else if user.HasRole("Admin") || user.HasRole("Canton") {
-		requests = services.GetAllRequestsForInspection()
+		page, err = services.GetAllRequestsForInspection(query, chain)
 	} else {
 		this.CustomAbort(401, "Unauthorized")
 	}
-
-	this.Data["json"] = requests
-	this.ServeJSON()
+	servePage(&this.Controller, page, err)
 }
 
 // @Title Add Inspector
@@ -135,7 +297,14 @@ func (this *RequestController) AddInspector() {
 
 	if user.HasRole("Admin") || user.HasRole("Canton") {
 		//requests = services.GetAllRequests()
-		services.AddInspectorToRequest(&request, ethereum.GetAuth(user.EtherumAddress))
+		auth, err := ethereum.GetAuth(user.EtherumAddress)
+		if err != nil {
+			this.CustomAbort(500, err.Error())
+		}
+		err = services.AddInspectorToRequest(&request, auth)
+		if err != nil {
+			this.abortWithRequestError(err)
+		}
 	} else {
 		this.CustomAbort(401, "Unauthorized")
 	}
@@ -144,10 +313,68 @@ func (this *RequestController) AddInspector() {
 	this.ServeJSON()
 }
 
+// @Title Propose inspectors
+// @Description Propose the inspectors with free capacity who are certified for the contributions of the request, best first
+// @Param	requestId		path 	int64	true		"The id of the request"
+// @Success 200 {object} []models.InspectorProposal
+// @router /:requestId/inspectors [get]
+func (this *RequestController) GetInspectorProposals() {
+	requestId, err := this.GetInt64(":requestId")
+	if err != nil {
+		this.CustomAbort(400, "No Request Id provided")
+	}
+	claims, _ := services.ParseToken(this.Ctx.Request.Header.Get("Authorization"))
+	user, err := services.GetUserByUsername(claims.Subject)
+	if err != nil {
+		this.CustomAbort(404, err.Error())
+	}
+	if (user.HasRole("Admin") || user.HasRole("Canton")) == false {
+		this.CustomAbort(401, "Unauthorized")
+	}
+
+	proposals, err := services.ProposeInspectors(requestId)
+	if err != nil {
+		this.CustomAbort(404, err.Error())
+	}
+	this.Data["json"] = proposals
+	this.ServeJSON()
+}
+
+// @Title Assign inspectors
+// @Description Assign inspectors to several requests, the best proposed inspector where none is given. Without a body all submitted requests are assigned.
+// @Param	body		body 	[]models.InspectorAssignment	false		"the requests and their inspectors"
+// @Success 200 {object} []models.InspectorAssignment
+// @router /inspector/bulk [put]
+func (this *RequestController) AssignInspectors() {
+	assignments := make([]*models.InspectorAssignment, 0)
+	json.Unmarshal(this.Ctx.Input.RequestBody, &assignments)
+
+	claims, _ := services.ParseToken(this.Ctx.Request.Header.Get("Authorization"))
+	user, err := services.GetUserByUsername(claims.Subject)
+	if err != nil {
+		this.CustomAbort(404, err.Error())
+	}
+	if (user.HasRole("Admin") || user.HasRole("Canton")) == false {
+		this.CustomAbort(401, "Unauthorized")
+	}
+	auth, err := ethereum.GetAuth(user.EtherumAddress)
+	if err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+
+	assignments, err = services.AssignInspectors(assignments, auth)
+	if err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+	this.Data["json"] = assignments
+	this.ServeJSON()
+}
+
 // @Title Add Inspection
-// @Description Add the report of the inspection
+// @Description Add the report of the inspection. The lacks are checked against the catalog, lacks without points get the points of the catalog.
 // @Param	body		body 	models.Request	true		"body for requestion content"
 // @Success 200 {object} models.Request
+// @Failure 400 {object} services.InspectionValidationError
 // @router /inspection [post]
 func (this *RequestController) AddInspection() {
 	var inspection models.Inspection
@@ -163,14 +390,99 @@ func (this *RequestController) AddInspection() {
 	} else {
 		this.CustomAbort(401, "Unauthorized")
 	}
-	err = services.AddLacksToRequest(&inspection, ethereum.GetAuth(user.EtherumAddress))
+	auth, err := ethereum.GetAuth(user.EtherumAddress)
 	if err != nil {
 		this.CustomAbort(500, err.Error())
 	}
+	err = services.AddLacksToRequest(&inspection, auth)
+	if err != nil {
+		this.abortWithRequestError(err)
+	}
 	this.Data["json"] = inspection
 	this.ServeJSON()
 }
 
+// @Title Create an Inspection Draft
+// @Description Start the draft of an inspection, it is updated from the inspector's device until it is finalised
+// @Param	body		body 	models.InspectionDraft	true		"body with the requestId, the device id, the lacks and the notes"
+// @Success 200 {object} models.InspectionDraft
+// @Failure 409 {object} services.InspectionConflictError
+// @router /inspection/draft [post]
+func (this *RequestController) PostInspectionDraft() {
+	var draft models.InspectionDraft
+	json.Unmarshal(this.Ctx.Input.RequestBody, &draft)
+
+	this.requestOfInspector(draft.RequestId)
+	err := services.CreateInspectionDraft(&draft)
+	if err != nil {
+		this.abortWithRequestError(err)
+	}
+	this.Data["json"] = draft
+	this.ServeJSON()
+}
+
+// @Title Update an Inspection Draft
+// @Description Replace the lacks and the notes of an inspection draft. The version must be the one the change is based on.
+// @Param	body		body 	models.InspectionDraft	true		"body with the requestId, the version, the device id, the lacks and the notes"
+// @Success 200 {object} models.InspectionDraft
+// @Failure 409 {object} services.InspectionConflictError
+// @router /inspection/draft [put]
+func (this *RequestController) PutInspectionDraft() {
+	var draft models.InspectionDraft
+	json.Unmarshal(this.Ctx.Input.RequestBody, &draft)
+
+	this.requestOfInspector(draft.RequestId)
+	err := services.UpdateInspectionDraft(&draft)
+	if err != nil {
+		this.abortWithRequestError(err)
+	}
+	this.Data["json"] = draft
+	this.ServeJSON()
+}
+
+// @Title Get an Inspection Draft
+// @Description get the inspection draft of a request
+// @Param	requestId		path 	int64	true		"The id of the request"
+// @Success 200 {object} models.InspectionDraft
+// @router /inspection/draft/:requestId [get]
+func (this *RequestController) GetInspectionDraft() {
+	requestId, err := this.GetInt64(":requestId")
+	if err != nil {
+		this.CustomAbort(400, "No Request Id provided")
+	}
+	this.requestOfInspector(requestId)
+	draft, err := services.GetInspectionDraft(requestId)
+	if err != nil {
+		this.CustomAbort(404, err.Error())
+	}
+	this.Data["json"] = draft
+	this.ServeJSON()
+}
+
+// @Title Finalise an Inspection Draft
+// @Description Validate the lacks of the draft and add them to the request
+// @Param	body		body 	models.InspectionDraft	true		"body with the requestId and the version"
+// @Success 200 {object} models.InspectionDraft
+// @Failure 400 {object} services.InspectionValidationError
+// @Failure 409 {object} services.InspectionConflictError
+// @router /inspection/finalise [post]
+func (this *RequestController) FinaliseInspection() {
+	var draft models.InspectionDraft
+	json.Unmarshal(this.Ctx.Input.RequestBody, &draft)
+
+	user := this.requestOfInspector(draft.RequestId)
+	auth, err := ethereum.GetAuth(user.EtherumAddress)
+	if err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+	finalised, err := services.FinaliseInspection(&draft, auth)
+	if err != nil {
+		this.abortWithRequestError(err)
+	}
+	this.Data["json"] = finalised
+	this.ServeJSON()
+}
+
 // @Title Update GVE
 // @Description Update GVE of request
 // @Param	body		body 	models.Request	true		"body for requestion content"
@@ -199,15 +511,75 @@ func (this *RequestController) UpdateGVE() {
 	this.ServeJSON()
 }
 
-// @Title Pay DirectPayment
-// @Description Update GVE of request
-// @Param	body		body 	models.Request	true		"body for requestion content"
+// @Title Withdraw
+// @Description Withdraw a request which has not been paid, its contract is killed
+// @Param	body		body 	models.RequestDecision	true		"body with the id of the request and the reason"
 // @Success 200 {object} models.Request
+// @Failure 400 reason is missing
+// @router /withdraw [post]
+func (this *RequestController) Withdraw() {
+	var decision models.RequestDecision
+	json.Unmarshal(this.Ctx.Input.RequestBody, &decision)
+
+	user := this.requestOfFarmer(decision.RequestId).User
+	auth, err := ethereum.GetAuth(user.EtherumAddress)
+	if err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+	request, err := services.WithdrawRequest(&decision, user.EtherumAddress, auth)
+	if err != nil {
+		this.abortWithRequestError(err)
+	}
+	this.Data["json"] = request
+	this.ServeJSON()
+}
+
+// @Title Reject
+// @Description Reject a request which has not been paid, its contract is killed
+// @Param	body		body 	models.RequestDecision	true		"body with the id of the request and the reason"
+// @Success 200 {object} models.Request
+// @Failure 400 reason is missing
+// @router /reject [post]
+func (this *RequestController) Reject() {
+	var decision models.RequestDecision
+	json.Unmarshal(this.Ctx.Input.RequestBody, &decision)
+
+	claims, _ := services.ParseToken(this.Ctx.Request.Header.Get("Authorization"))
+	user, err := services.GetUserByUsername(claims.Subject)
+	if err != nil {
+		this.CustomAbort(404, err.Error())
+	}
+	if (user.HasRole("Admin") || user.HasRole("Canton")) == false {
+		this.CustomAbort(401, "Unauthorized")
+	}
+	stored := services.GetRequestById(decision.RequestId, false)
+	if stored.Id == 0 {
+		this.CustomAbort(404, "Request not found")
+	}
+	// admins and canton employees kill the contract with their own key
+	auth, err := ethereum.GetAuth(user.EtherumAddress)
+	if err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+	request, err := services.RejectRequest(&decision, user.EtherumAddress, auth)
+	if err != nil {
+		this.abortWithRequestError(err)
+	}
+	this.Data["json"] = request
+	this.ServeJSON()
+}
+
+// @Title Pay DirectPayment
+// @Description propose to pay the next due tranche of the payment plan of a request, it is paid once another employee approved the proposal
+// @Param	body		body 	models.TranchePayment	true		"id of the request and optionally the position of the tranche"
+// @Param	Idempotency-Key		header 	string	false		"repeating a payment with the key returns the proposal stored the first time"
+// @Success 200 {object} models.PayoutProposal
+// @Failure 409 no tranche is due or a payment is not settled
+// @Failure 422 the Idempotency-Key was used for another payment
 // @router /pay [post]
 func (this *RequestController) Pay() {
-
-	var r models.Request
-	json.Unmarshal(this.Ctx.Input.RequestBody, &r)
+	var payment models.TranchePayment
+	json.Unmarshal(this.Ctx.Input.RequestBody, &payment)
 
 	claims, _ := services.ParseToken(this.Ctx.Request.Header.Get("Authorization"))
 	user, err := services.GetUserByUsername(claims.Subject)
@@ -219,46 +591,154 @@ func (this *RequestController) Pay() {
 		this.CustomAbort(401, "Unauthorized")
 	}
 
-	request := services.GetRequestById(r.Id, true)
-
-	apaymentTransfer := &models.APaymentTokenTransfer{
-		From: user.EtherumAddress,
-		To:   request.User.EtherumAddress,
+	request := services.GetRequestById(payment.Id, false)
+	if request.Id == 0 {
+		this.CustomAbort(404, "Request not found")
 	}
-	if len(request.Payments) == 0 {
-		beego.Debug("make first payment")
-		amount, err := services.GetFirstPaymentAmount(request)
-		beego.Info("GetFirstPaymentAmount: ", amount)
-		if err != nil {
-			beego.Error("Error while getting first payment amount. ", err)
-			this.CustomAbort(500, err.Error())
-		}
-		//amount.Div(amount, big.NewInt(2)) // 50% of the amount
 
-		apaymentTransfer.Amount = amount
-		apaymentTransfer.Message = "First Payment"
-		err = services.Transfer(apaymentTransfer, request.Address)
-		if err != nil {
-			beego.Debug("Error while transfer", err)
-			this.CustomAbort(500, err.Error())
-		}
-	} else if len(request.Payments) == 1 {
-		beego.Debug("make second payment")
-		amount, err := services.GetSecondPaymentAmount(request)
-		if err != nil {
-			beego.Error("Error while getting second payment amount. ", err)
-			this.CustomAbort(500, err.Error())
-		}
-		apaymentTransfer.Amount = amount
-		apaymentTransfer.Message = "Second Payment"
-		err = services.Transfer(apaymentTransfer, request.Address)
-		if err != nil {
-			beego.Debug("Error while transfer", err)
-			this.CustomAbort(500, err.Error())
-		}
+	proposal := &models.PayoutProposal{
+		Items:          []*models.PayoutProposalItem{{RequestId: request.Id, Tranche: payment.Tranche}},
+		IdempotencyKey: this.Ctx.Request.Header.Get("Idempotency-Key"),
 	}
+	if err := services.PrepareProposal(proposal, user); err != nil {
+		beego.Error("Error while proposing payment of request ", request.Id, ": ", err)
+		this.abortWithRequestError(err)
+	}
+	this.Data["json"] = proposal
+	this.ServeJSON()
+}
 
-	//request = services.GetRequestById(r.Id)
-	this.Data["json"] = request
+// @Title Get payment plan
+// @Description get the planned, due and paid tranches of a request
+// @Param jwtToken header string true "jwt Token for Authorization"
+// @Param	requestId		path 	int64	true		"The id of the request"
+// @Success 200 {object} models.PaymentPlan
+// @router /:requestId/plan [get]
+func (this *RequestController) GetPlan() {
+	requestId, err := this.GetInt64(":requestId")
+	if err != nil {
+		this.CustomAbort(400, "No Request Id provided")
+	}
+	this.requestOfParticipant(requestId)
+	plan, err := services.GetPaymentPlan(services.GetRequestById(requestId, false))
+	if err != nil {
+		this.abortWithRequestError(err)
+	}
+	this.Data["json"] = plan
 	this.ServeJSON()
 }
+
+// @Title Get report
+// @Description get the inspection report of a request as PDF, the same data always gives the same document. The system account signs the SHA-256 digest of the document as an Ethereum signed message: X-Report-Digest, X-Report-Signature and X-Report-Signer.
+// @Param jwtToken header string true "jwt Token for Authorization"
+// @Param	requestId		path 	int64	true		"The id of the request"
+// @Success 200 the PDF document
+// @router /:requestId/report.pdf [get]
+func (this *RequestController) GetReport() {
+	requestId, err := this.GetInt64(":requestId")
+	if err != nil {
+		this.CustomAbort(400, "No Request Id provided")
+	}
+	this.requestOfParticipant(requestId)
+	report, err := services.RequestReport(requestId)
+	if err != nil {
+		this.abortWithRequestError(err)
+	}
+	sum := sha256.Sum256(report)
+	etag := strconv.Quote(hex.EncodeToString(sum[:]))
+	this.Ctx.Output.Header("ETag", etag)
+	if this.Ctx.Input.Header("If-None-Match") == etag {
+		this.Ctx.Output.SetStatus(304)
+		return
+	}
+	signature, err := services.SignReport(report)
+	if err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+	this.Ctx.Output.Header("X-Report-Digest", signature.Digest)
+	this.Ctx.Output.Header("X-Report-Signature", signature.Signature)
+	this.Ctx.Output.Header("X-Report-Signer", signature.Signer)
+	this.Ctx.Output.Header("Content-Type", "application/pdf")
+	this.Ctx.Output.Header("Content-Disposition", "inline; filename=\"request-"+strconv.FormatInt(requestId, 10)+"-report.pdf\"")
+	this.Ctx.Output.Body(report)
+}
+
+// requestOfParticipant checks that the authenticated user is the farmer or the inspector
+// of the request, an admin or the canton
+func (this *RequestController) requestOfParticipant(requestId int64) {
+	claims, _ := services.ParseToken(this.Ctx.Request.Header.Get("Authorization"))
+	user, err := services.GetUserByUsername(claims.Subject)
+	if err != nil {
+		this.CustomAbort(404, err.Error())
+	}
+	request := services.GetRequestById(requestId, false)
+	if request.Id == 0 {
+		this.CustomAbort(404, "Request not found")
+	}
+	farmer := request.User != nil && request.User.Id == user.Id
+	inspector := request.Inspector != nil && request.Inspector.Id == user.Id
+	if !farmer && !inspector && (user.HasRole("Admin") || user.HasRole("Canton")) == false {
+		this.CustomAbort(401, "Unauthorized")
+	}
+}
+
+// requestOfFarmer loads a request of the authenticated farmer
+func (this *RequestController) requestOfFarmer(requestId int64) *models.Request {
+	claims, _ := services.ParseToken(this.Ctx.Request.Header.Get("Authorization"))
+	user, err := services.GetUserByUsername(claims.Subject)
+	if err != nil {
+		this.CustomAbort(404, err.Error())
+	}
+	request := services.GetRequestById(requestId, false)
+	if request.Id == 0 {
+		this.CustomAbort(404, "Request not found")
+	}
+	if request.User.Id != user.Id {
+		this.CustomAbort(401, "Unauthorized")
+	}
+	return request
+}
+
+// requestOfInspector checks that the authenticated user is the inspector of the request
+// or an admin and returns the user
+func (this *RequestController) requestOfInspector(requestId int64) *models.User {
+	claims, _ := services.ParseToken(this.Ctx.Request.Header.Get("Authorization"))
+	user, err := services.GetUserByUsername(claims.Subject)
+	if err != nil {
+		this.CustomAbort(404, err.Error())
+	}
+	request := services.GetRequestById(requestId, false)
+	if request.Id == 0 {
+		this.CustomAbort(404, "Request not found")
+	}
+	if user.HasRole("Admin") == false && (request.Inspector == nil || request.Inspector.Id != user.Id) {
+		this.CustomAbort(401, "Unauthorized")
+	}
+	return user
+}
+
+// abortWithRequestError answers with the status matching an error of the request services
+func (this *RequestController) abortWithRequestError(err error) {
+	switch err.(type) {
+	case *services.RequestValidationError:
+		this.CustomAbort(400, err.Error())
+	case *services.InspectionValidationError:
+		// the field errors are answered as json
+		this.Ctx.Output.SetStatus(400)
+		this.Data["json"] = err
+		this.ServeJSON()
+		this.StopRun()
+	case *services.InspectionConflictError:
+		// the device gets the stored draft to merge its changes
+		this.Ctx.Output.SetStatus(409)
+		this.Data["json"] = err
+		this.ServeJSON()
+		this.StopRun()
+	case *services.RequestTransitionError, *services.TrancheNotDueError, *services.ObjectionPendingError, *services.ReconciliationFlaggedError, *services.ReconciliationMissingError, *services.PayoutPendingError:
+		this.CustomAbort(409, err.Error())
+	case *services.IdempotencyKeyError:
+		this.CustomAbort(422, err.Error())
+	default:
+		this.CustomAbort(500, err.Error())
+	}
+}
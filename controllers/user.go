@@ -34,8 +34,11 @@ func (this *UserController) Post() {
 }
 
 // @Title GetAll
-// @Description get all Users
-// @Success 200 {Object} models.User
+// @Description get a page of the Users, filtered by role and tvd
+// @Param	offset	query	int	false	"index of the first item"
+// @Param	limit	query	int	false	"number of items, at most 500"
+// @Param	sort	query	string	false	"field to sort by, prefixed with - for descending order"
+// @Success 200 {Object} services.Page
 // @router / [get]
 func (this *UserController) GetAll() {
 	claims, _ := services.ParseToken(this.Ctx.Request.Header.Get("Authorization"))
@@ -43,21 +46,11 @@ func (this *UserController) GetAll() {
 	if err != nil {
 		this.CustomAbort(404, err.Error())
 	}
-	if user.HasRole("Admin") || user.HasRole("Canton") {
-		role := this.Ctx.Input.Query("role")
-		if role == "Inspector" {
-			this.Data["json"], err = services.GetAllUsersByRole(role)
-			if err != nil {
-				beego.Error("Error while fetching users by Role", err)
-			}
-		} else {
-			this.Data["json"] = services.GetAllUsers()
-		}
-
-	} else {
+	if (user.HasRole("Admin") || user.HasRole("Canton")) == false {
 		this.CustomAbort(401, "Unauthorized")
 	}
-	this.ServeJSON()
+	page, err := services.GetAllUsers(listQuery(&this.Controller))
+	servePage(&this.Controller, page, err)
 }
 
 // @Title Get my Profile
@@ -110,6 +103,106 @@ func (u *UserController) Put() {
 	u.ServeJSON()
 }
 
+// @Title Set previous year amount
+// @Description Set the amount of the previous year for a farmer who is new or had no request last year
+// @Param	uid		path 	string	true		"The uid of the farmer"
+// @Param	body		body 	models.PreviousYearAmount	true		"body with the year and the amount"
+// @Success 200 {object} models.PreviousYearAmount
+// @Failure 403 :uid is not int
+// @router /:uid/previousyearamount [put]
+func (this *UserController) PutPreviousYearAmount() {
+	uid, err := this.GetInt64(":uid")
+	if err != nil {
+		beego.Error("GetInt64 ", err.Error())
+		this.CustomAbort(500, "No User Id provided")
+	}
+	claims, _ := services.ParseToken(this.Ctx.Request.Header.Get("Authorization"))
+	admin, err := services.GetUserByUsername(claims.Subject)
+	if err != nil {
+		this.CustomAbort(404, err.Error())
+	}
+	if admin.HasRole("Admin") == false {
+		this.CustomAbort(401, "Unauthorized")
+	}
+
+	var amount models.PreviousYearAmount
+	json.Unmarshal(this.Ctx.Input.RequestBody, &amount)
+	amount.User = &models.User{Id: uid}
+	amount.Actor = admin.EtherumAddress
+	err = services.SetPreviousYearAmount(&amount)
+	if err != nil {
+		this.CustomAbort(400, err.Error())
+	}
+	this.Data["json"] = amount
+	this.ServeJSON()
+}
+
+// @Title Set inspector profile
+// @Description Set the capacity, the certified contributions and the base of an inspector
+// @Param	uid		path 	string	true		"The uid of the inspector"
+// @Param	body		body 	models.InspectorProfile	true		"body with the capacity, certifications and coordinates"
+// @Success 200 {object} models.InspectorProfile
+// @Failure 403 :uid is not int
+// @router /:uid/inspectorprofile [put]
+func (this *UserController) PutInspectorProfile() {
+	uid, err := this.GetInt64(":uid")
+	if err != nil {
+		beego.Error("GetInt64 ", err.Error())
+		this.CustomAbort(500, "No User Id provided")
+	}
+	claims, _ := services.ParseToken(this.Ctx.Request.Header.Get("Authorization"))
+	admin, err := services.GetUserByUsername(claims.Subject)
+	if err != nil {
+		this.CustomAbort(404, err.Error())
+	}
+	if admin.HasRole("Admin") == false {
+		this.CustomAbort(401, "Unauthorized")
+	}
+
+	var profile models.InspectorProfile
+	json.Unmarshal(this.Ctx.Input.RequestBody, &profile)
+	profile.User = &models.User{Id: uid}
+	err = services.SetInspectorProfile(&profile)
+	if err != nil {
+		this.CustomAbort(400, err.Error())
+	}
+	this.Data["json"] = profile
+	this.ServeJSON()
+}
+
+// @Title Set plant
+// @Description Set the plant of a farmer, its coordinates are used to propose inspectors
+// @Param	uid		path 	string	true		"The uid of the farmer"
+// @Param	body		body 	models.Plant	true		"body for plant content"
+// @Success 200 {object} models.Plant
+// @Failure 403 :uid is not int
+// @router /:uid/plant [put]
+func (this *UserController) PutPlant() {
+	uid, err := this.GetInt64(":uid")
+	if err != nil {
+		beego.Error("GetInt64 ", err.Error())
+		this.CustomAbort(500, "No User Id provided")
+	}
+	claims, _ := services.ParseToken(this.Ctx.Request.Header.Get("Authorization"))
+	user, err := services.GetUserByUsername(claims.Subject)
+	if err != nil {
+		this.CustomAbort(404, err.Error())
+	}
+	if user.Id != uid && user.HasRole("Admin") == false {
+		this.CustomAbort(401, "Unauthorized")
+	}
+
+	var plant models.Plant
+	json.Unmarshal(this.Ctx.Input.RequestBody, &plant)
+	plant.User = &models.User{Id: uid}
+	err = services.SetFarmPlant(&plant)
+	if err != nil {
+		this.CustomAbort(500, err.Error())
+	}
+	this.Data["json"] = plant
+	this.ServeJSON()
+}
+
 // @Title Delete
 // @Description delete the user
 // @Param	uid		path 	string	true		"The uid you want to delete"
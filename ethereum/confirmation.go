@@ -0,0 +1,51 @@
+package ethereum
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/astaxie/beego"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// ConfirmationDepth is the number of blocks that have to be mined on top of a
+// block before its state is used for business decisions ('confirmationDepth').
+func ConfirmationDepth() uint64 {
+	depth := beego.AppConfig.DefaultInt64("confirmationDepth", 12)
+	if depth < 0 {
+		return 0
+	}
+	return uint64(depth)
+}
+
+// HeadBlockNumber returns the number of the latest block of the node
+func HeadBlockNumber() (uint64, error) {
+	header, err := ethereumController.Client.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		beego.Error("Failed to read latest block header: ", err)
+		return 0, err
+	}
+	return header.Number.Uint64(), nil
+}
+
+// ConfirmedBlockNumber returns the latest block with ConfirmationDepth blocks on top
+func ConfirmedBlockNumber() (uint64, error) {
+	head, err := HeadBlockNumber()
+	if err != nil {
+		return 0, err
+	}
+	depth := ConfirmationDepth()
+	if head < depth {
+		return 0, nil
+	}
+	return head - depth, nil
+}
+
+// ConfirmedCallOpts reads contract state as of the latest confirmed block
+func ConfirmedCallOpts() (*bind.CallOpts, error) {
+	number, err := ConfirmedBlockNumber()
+	if err != nil {
+		return nil, err
+	}
+	return &bind.CallOpts{BlockNumber: new(big.Int).SetUint64(number)}, nil
+}
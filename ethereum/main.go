@@ -6,10 +6,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/ethclient"
 
-	"bytes"
-	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
-	"io/ioutil"
 	"math/big"
 
 	"context"
@@ -20,9 +17,9 @@ import (
 )
 
 type EthereumController struct {
-	Auth     *bind.TransactOpts
-	Client   *ethclient.Client
-	Keystore *keystore.KeyStore
+	Auth   *bind.TransactOpts
+	Client *ethclient.Client
+	Signer Signer
 }
 
 var ethereumController EthereumController
@@ -42,8 +39,17 @@ func Init() {
 
 	// Keystore to administrate accounts
 	ks := keystore.NewKeyStore(pathToEthereum+"keystore", keystore.LightScryptN, keystore.LightScryptP)
-	ethereumController = EthereumController{Auth: nil, Client: client, Keystore: ks}
-	auth := GetAuth(beego.AppConfig.String("systemAccountAddress"))
+	signer, err := newSigner(beego.AppConfig.String("signer"), ks)
+	if err != nil {
+		// every transaction is signed by the signer, nothing works without it
+		beego.Critical("Failed to create signer: ", err)
+		panic("Failed to create signer: " + err.Error())
+	}
+	ethereumController = EthereumController{Auth: nil, Client: client, Signer: signer}
+	auth, err := GetAuth(beego.AppConfig.String("systemAccountAddress"))
+	if err != nil {
+		beego.Critical("Failed to create authorized transactor for system account: ", err)
+	}
 	ethereumController.Auth = auth
 
 	deployRoleBasedAccessControlContract()
@@ -74,60 +80,80 @@ func deployAPaymentTokenContract() {
 		if err != nil {
 			beego.Critical("Error while deploying APaymentTokenContract: ", err)
 		}
-		beego.Debug(address.Str())
+		beego.Debug(address.Hex())
 		beego.AppConfig.Set("tokenSupply", address.String())
 	}
 }
 
-func SendWei(from string, to string, amount *big.Int) {
+// SendWei signs and sends a plain ether transfer
+func SendWei(from string, to string, amount *big.Int) (*types.Transaction, error) {
 	beego.Info("Send ether from: ", from, "to: ", to, "amount:", amount)
+	return sendTransaction(from, to, amount, nil)
+}
+
+// SendData signs and sends a transaction without value that only records the data on chain
+func SendData(from string, to string, data []byte) (*types.Transaction, error) {
+	beego.Info("Send data from: ", from, "to: ", to)
+	return sendTransaction(from, to, big.NewInt(0), data)
+}
+
+func sendTransaction(from string, to string, amount *big.Int, data []byte) (*types.Transaction, error) {
 	ctx := context.Background()
-	fromAccount, err := ethereumController.Keystore.Find(accounts.Account{Address: common.HexToAddress(from)})
-	if fromAccount.Address.String() != from {
-		beego.Debug(fromAccount.Address.String(), from)
-		beego.Error("something wrong")
-		return
+	auth, err := GetAuth(from)
+	if err != nil {
+		beego.Error("Failed to get signer for: ", from)
+		return nil, err
 	}
-	nonce, err := ethereumController.Client.PendingNonceAt(ctx, fromAccount.Address)
+	nonce, err := ethereumController.Client.PendingNonceAt(ctx, auth.From)
 	if err != nil {
-		beego.Critical("Failed to get nounce: ", err)
+		beego.Error("Failed to get nounce: ", err)
+		return nil, err
 	}
-	estimateGas, err := ethereumController.Client.EstimateGas(ctx, ethereum.CallMsg{From: fromAccount.Address, Value: amount, Data: nil})
+	toAddress := common.HexToAddress(to)
+	estimateGas, err := ethereumController.Client.EstimateGas(ctx, ethereum.CallMsg{From: auth.From, To: &toAddress, Value: amount, Data: data})
 	if err != nil {
-		beego.Critical("Failed to estimate gas: ", err)
+		beego.Error("Failed to estimate gas: ", err)
+		return nil, err
 	}
 
-	tx := types.NewTransaction(nonce, common.HexToAddress(to), amount, estimateGas, big.NewInt(50000000000), nil)
+	tx := types.NewTransaction(nonce, toAddress, amount, estimateGas, big.NewInt(50000000000), data)
 	chainId, err := beego.AppConfig.Int64("chainId")
 	if err != nil {
-		beego.Critical("Failed to get chainID: ", err)
+		beego.Error("Failed to get chainID: ", err)
+		return nil, err
 	}
-	tx, err = ethereumController.Keystore.SignTxWithPassphrase(fromAccount, beego.AppConfig.String("systemAccountPassword"), tx, big.NewInt(chainId))
+	tx, err = auth.Signer(types.NewEIP155Signer(big.NewInt(chainId)), auth.From, tx)
 	if err != nil {
-		beego.Critical("Failed to Sign Transaction: ", err)
+		beego.Error("Failed to Sign Transaction: ", err)
+		return nil, err
 	}
 	err = ethereumController.Client.SendTransaction(ctx, tx)
 	if err != nil {
-		beego.Critical("Failed to Send Transaction: ", err)
+		beego.Error("Failed to Send Transaction: ", err)
+		return nil, err
 	}
+	beego.Info("Transaction waiting to be mined: ", tx.Hash().String())
+	return tx, nil
 }
 
-func GetAuth(address string) *bind.TransactOpts {
-	account, err := ethereumController.Keystore.Find(accounts.Account{Address: common.HexToAddress(address)})
+// GetAuth returns the transaction options for the given account from the configured signer.
+func GetAuth(address string) (*bind.TransactOpts, error) {
+	auth, err := ethereumController.Signer.TransactOpts(common.HexToAddress(address))
 	if err != nil {
-		beego.Error("Error find: ", err)
+		beego.Error("Failed to create authorized transactor: ", err)
 	}
-	dat, err := ioutil.ReadFile(account.URL.Path)
+	return auth, err
+}
 
-	password := beego.AppConfig.String("userAccountPassword")
-	if address == beego.AppConfig.String("systemAccountAddress") {
-		password = beego.AppConfig.String("systemAccountPassword")
-	}
-	auth, err := bind.NewTransactor(bytes.NewReader(dat), password)
-	if err != nil {
-		beego.Critical("Failed  to create authorized transactor: ", err)
-	}
-	return auth
+// SignMessage signs a message as an Ethereum signed message with the configured signer.
+func SignMessage(address string, message []byte) ([]byte, error) {
+	return ethereumController.Signer.SignMessage(common.HexToAddress(address), message)
+}
+
+// NewAccount creates a new account with the configured signer.
+func NewAccount() (string, error) {
+	address, err := ethereumController.Signer.NewAccount()
+	return address.String(), err
 }
 
 func GetEthereumController() EthereumController {
@@ -0,0 +1,181 @@
+package ethereum
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/astaxie/beego"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer holds the keys of the accounts managed by the backend. Callers only
+// get transaction options back and never see a password or a private key.
+type Signer interface {
+	// NewAccount creates a new account and returns its address.
+	NewAccount() (common.Address, error)
+	// TransactOpts returns the options to send transactions from the given account.
+	TransactOpts(address common.Address) (*bind.TransactOpts, error)
+	// SignMessage signs the message with the "\x19Ethereum Signed Message:\n" prefix
+	// (EIP-191), the V of the signature is 27 or 28.
+	SignMessage(address common.Address, message []byte) ([]byte, error)
+}
+
+// PassphraseProvider returns the passphrase protecting the key of an account.
+type PassphraseProvider interface {
+	Passphrase(address common.Address) (string, error)
+}
+
+// newSigner creates the signer configured with 'signer' (keystore, derived or clef).
+func newSigner(mode string, ks *keystore.KeyStore) (Signer, error) {
+	switch mode {
+	case "", "keystore":
+		return &keystoreSigner{keystore: ks, passphrases: sharedPassphrase{}}, nil
+	case "derived":
+		masterKey, err := newFileMasterKey(beego.AppConfig.String("signerMasterKeyFile"))
+		if err != nil {
+			return nil, err
+		}
+		return &keystoreSigner{keystore: ks, passphrases: masterKey}, nil
+	case "clef":
+		return newClefSigner(beego.AppConfig.String("clefEndpoint"))
+	}
+	return nil, errors.New("Unknown signer: " + mode)
+}
+
+// keystoreSigner signs with the keys stored in the local keystore of the node.
+type keystoreSigner struct {
+	keystore    *keystore.KeyStore
+	passphrases PassphraseProvider
+}
+
+func (s *keystoreSigner) NewAccount() (common.Address, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return common.Address{}, err
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	passphrase, err := s.passphrases.Passphrase(address)
+	if err != nil {
+		return common.Address{}, err
+	}
+	account, err := s.keystore.ImportECDSA(key, passphrase)
+	return account.Address, err
+}
+
+func (s *keystoreSigner) TransactOpts(address common.Address) (*bind.TransactOpts, error) {
+	account, err := s.keystore.Find(accounts.Account{Address: address})
+	if err != nil {
+		beego.Error("Error find: ", err)
+		return nil, err
+	}
+	passphrase, err := s.passphrases.Passphrase(address)
+	if err != nil {
+		return nil, err
+	}
+	dat, err := ioutil.ReadFile(account.URL.Path)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := bind.NewTransactor(bytes.NewReader(dat), passphrase)
+	if err == keystore.ErrDecrypt {
+		// Key still protected by the shared password, re-encrypt it with its own passphrase
+		if auth, err = s.migrate(account, dat, passphrase); err != nil {
+			return nil, err
+		}
+	}
+	return auth, err
+}
+
+func (s *keystoreSigner) SignMessage(address common.Address, message []byte) ([]byte, error) {
+	account, err := s.keystore.Find(accounts.Account{Address: address})
+	if err != nil {
+		return nil, err
+	}
+	passphrase, err := s.passphrases.Passphrase(address)
+	if err != nil {
+		return nil, err
+	}
+	hash := crypto.Keccak256([]byte("\x19Ethereum Signed Message:\n" + strconv.Itoa(len(message)) + string(message)))
+	signature, err := s.keystore.SignHashWithPassphrase(account, passphrase, hash)
+	if err != nil {
+		return nil, err
+	}
+	signature[64] += 27
+	return signature, nil
+}
+
+func (s *keystoreSigner) migrate(account accounts.Account, dat []byte, passphrase string) (*bind.TransactOpts, error) {
+	if _, ok := s.passphrases.(sharedPassphrase); ok {
+		return nil, keystore.ErrDecrypt
+	}
+	legacy, _ := sharedPassphrase{}.Passphrase(account.Address)
+	auth, err := bind.NewTransactor(bytes.NewReader(dat), legacy)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.keystore.Update(account, legacy, passphrase); err != nil {
+		beego.Error("Failed to re-encrypt key: ", err)
+		return nil, err
+	}
+	beego.Info("Key re-encrypted with derived passphrase: ", account.Address.String())
+	return auth, nil
+}
+
+// sharedPassphrase protects every user key with 'userAccountPassword'.
+type sharedPassphrase struct{}
+
+func (sharedPassphrase) Passphrase(address common.Address) (string, error) {
+	if isSystemAccount(address) {
+		return beego.AppConfig.String("systemAccountPassword"), nil
+	}
+	return beego.AppConfig.String("userAccountPassword"), nil
+}
+
+// fileMasterKey derives a passphrase per account from a master key. The key
+// file stands in for a KMS and must not be stored next to the keystore.
+type fileMasterKey struct {
+	key []byte
+}
+
+func newFileMasterKey(path string) (*fileMasterKey, error) {
+	if path == "" {
+		return nil, errors.New("signerMasterKeyFile not set")
+	}
+	dat, err := ioutil.ReadFile(path)
+	if err != nil {
+		beego.Error("Failed to read master key: ", err)
+		return nil, err
+	}
+	key, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(dat)), "0x"))
+	if err != nil {
+		return nil, err
+	}
+	if len(key) < 32 {
+		return nil, errors.New("master key must be at least 32 bytes")
+	}
+	return &fileMasterKey{key: key}, nil
+}
+
+func (m *fileMasterKey) Passphrase(address common.Address) (string, error) {
+	// The system account is created outside of the backend and keeps its own password
+	if isSystemAccount(address) {
+		return beego.AppConfig.String("systemAccountPassword"), nil
+	}
+	mac := hmac.New(sha256.New, m.key)
+	mac.Write(address.Bytes())
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+func isSystemAccount(address common.Address) bool {
+	return address == common.HexToAddress(beego.AppConfig.String("systemAccountAddress"))
+}
@@ -0,0 +1,90 @@
+package ethereum
+
+import (
+	"errors"
+
+	"github.com/astaxie/beego"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// clefSigner delegates key custody to an external Clef instance. Every
+// transaction has to be approved by Clef's rules or its operator.
+type clefSigner struct {
+	client *rpc.Client
+}
+
+// clefTransaction is the SendTxArgs object expected by account_signTransaction
+type clefTransaction struct {
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to"`
+	Gas      hexutil.Uint64  `json:"gas"`
+	GasPrice *hexutil.Big    `json:"gasPrice"`
+	Value    *hexutil.Big    `json:"value"`
+	Nonce    hexutil.Uint64  `json:"nonce"`
+	Data     hexutil.Bytes   `json:"data"`
+}
+
+type clefSignResult struct {
+	Raw hexutil.Bytes `json:"raw"`
+}
+
+func newClefSigner(endpoint string) (*clefSigner, error) {
+	if endpoint == "" {
+		return nil, errors.New("clefEndpoint not set")
+	}
+	client, err := rpc.Dial(endpoint)
+	if err != nil {
+		beego.Error("Failed to connect to Clef: ", err)
+		return nil, err
+	}
+	beego.Info("Clef endpoint: ", endpoint)
+	return &clefSigner{client: client}, nil
+}
+
+func (s *clefSigner) NewAccount() (common.Address, error) {
+	var address common.Address
+	err := s.client.Call(&address, "account_new")
+	return address, err
+}
+
+func (s *clefSigner) SignMessage(address common.Address, message []byte) ([]byte, error) {
+	var signature hexutil.Bytes
+	// text/plain is signed with the EIP-191 prefix
+	err := s.client.Call(&signature, "account_signData", "text/plain", common.NewMixedcaseAddress(address), hexutil.Bytes(message))
+	return signature, err
+}
+
+func (s *clefSigner) TransactOpts(address common.Address) (*bind.TransactOpts, error) {
+	return &bind.TransactOpts{
+		From: address,
+		Signer: func(signer types.Signer, from common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if from != address {
+				return nil, errors.New("not authorized to sign this account")
+			}
+			args := clefTransaction{
+				From:     from,
+				To:       tx.To(),
+				Gas:      hexutil.Uint64(tx.Gas()),
+				GasPrice: (*hexutil.Big)(tx.GasPrice()),
+				Value:    (*hexutil.Big)(tx.Value()),
+				Nonce:    hexutil.Uint64(tx.Nonce()),
+				Data:     tx.Data(),
+			}
+			var result clefSignResult
+			if err := s.client.Call(&result, "account_signTransaction", args); err != nil {
+				beego.Error("Clef refused to sign transaction: ", err)
+				return nil, err
+			}
+			signed := new(types.Transaction)
+			if err := rlp.DecodeBytes(result.Raw, signed); err != nil {
+				return nil, err
+			}
+			return signed, nil
+		},
+	}, nil
+}
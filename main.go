@@ -3,9 +3,11 @@ package main
 import (
 	"github.com/astaxie/beego"
 	"github.com/astaxie/beego/plugins/cors"
+	"github.com/astaxie/beego/toolbox"
 	"github.com/scmo/apayment-backend/db"
 	"github.com/scmo/apayment-backend/ethereum"
 	_ "github.com/scmo/apayment-backend/routers"
+	"github.com/scmo/apayment-backend/services"
 	"os"
 )
 
@@ -44,6 +46,12 @@ func main() {
 		AllowCredentials: true,
 	}))
 
+	// Background jobs
+	services.InitTasks()
+	services.WatchRequests()
+	toolbox.StartTask()
+	defer toolbox.StopTask()
+
 	beego.Run()
 }
 
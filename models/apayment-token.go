@@ -10,12 +10,13 @@ type APaymentTokenTransfer struct {
 }
 
 type APaymentTokenTransaction struct {
-	Timestamp string   `json:"timestamp"`
-	From      *User    `json:"from"`
-	To        *User    `json:"to"`
-	Amount    *big.Int `json:"amount"`
-	Message   string   `json:"message"`
-	Request   *Request `json:"request"`
+	Timestamp   string   `json:"timestamp"`
+	BlockNumber uint64   `json:"blockNumber"`
+	From        *User    `json:"from"`
+	To          *User    `json:"to"`
+	Amount      *big.Int `json:"amount"`
+	Message     string   `json:"message"`
+	Request     *Request `json:"request"`
 }
 
 type EtherScanTransactionResult struct {
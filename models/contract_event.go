@@ -0,0 +1,39 @@
+package models
+
+import "github.com/astaxie/beego/orm"
+
+const (
+	ContractEventInspectorAssigned = "InspectorAssigned"
+	ContractEventLacksAdded        = "LacksAdded"
+	ContractEventGVESet            = "GVESet"
+	ContractEventLackReversed      = "LackReversed"
+)
+
+// ContractEvent is an event of a Request contract applied to the read model. The
+// block hash tells which events have to be rolled back when their block is orphaned.
+type ContractEvent struct {
+	Id              int64    `json:"id"`
+	Request         *Request `orm:"rel(fk)" json:"-"`
+	Name            string   `json:"name"`
+	BlockNumber     uint64   `json:"blockNumber"`
+	BlockHash       string   `json:"blockHash"`
+	TransactionHash string   `json:"transactionHash"`
+	LogIndex        uint     `json:"logIndex"`
+
+	// payload, depending on the event
+	Inspector      string `json:"inspector,omitempty"`
+	NumLacks       int64  `json:"numLacks,omitempty"`
+	PointGroupCode uint16 `json:"pointGroupCode,omitempty"`
+	Gve            uint32 `json:"gve,omitempty"`
+	LackIndex      int64  `json:"lackIndex"`
+	Reason         string `json:"reason,omitempty"`
+}
+
+func (e *ContractEvent) TableUnique() [][]string {
+	return [][]string{{"BlockHash", "LogIndex"}}
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(ContractEvent))
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"github.com/astaxie/beego/orm"
+	"time"
+)
+
+const (
+	ContractKillStatusPending = "pending" // not sent yet or its transaction failed, it is tried again
+	ContractKillStatusSent    = "sent"
+	ContractKillStatusFailed  = "failed" // given up after contractKillAttempts or ignored by the contract
+)
+
+// ContractKill is stored for a request contract which is retired, so that a kill which
+// could not be sent is tried again
+type ContractKill struct {
+	Id          int64                `json:"id"`
+	Request     *Request             `orm:"rel(fk)" json:"-"`
+	Address     string               `json:"address"`
+	Actor       string               `json:"actor"` // signs the kill
+	Status      string               `json:"status"`
+	Attempts    int                  `json:"attempts"`
+	Error       string               `orm:"type(text)" json:"error"`
+	Transaction *EthereumTransaction `orm:"rel(fk);null" json:"-"`
+	Created     time.Time            `orm:"auto_now_add;type(datetime)" json:"created"`
+	Updated     time.Time            `orm:"auto_now;type(datetime)" json:"updated"`
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(ContractKill))
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+const (
+	EventTypeRoleChanged       = "roleChanged"
+	EventTypeInspectorAssigned = "inspectorAssigned"
+	EventTypeLacksAdded        = "lacksAdded"
+	EventTypePaymentReceived   = "paymentReceived"
+)
+
+// Event is pushed to the connected clients when a transaction has been confirmed
+type Event struct {
+	Id              int64     `json:"id"`
+	Type            string    `json:"type"`
+	RequestId       int64     `json:"requestId,omitempty"`
+	Account         string    `json:"account,omitempty"`
+	Detail          string    `json:"detail,omitempty"`
+	Amount          string    `json:"amount,omitempty"`
+	TransactionHash string    `json:"transactionHash"`
+	Created         time.Time `json:"created"`
+
+	// used to decide who may see the event
+	FarmerId    int64 `json:"-"`
+	InspectorId int64 `json:"-"`
+}
+
+// EventTicket authorizes one connection to the event stream, EventSource cannot send the JWT token as header
+type EventTicket struct {
+	Ticket  string    `json:"ticket"`
+	Expires time.Time `json:"expires"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"github.com/astaxie/beego/orm"
+	"time"
+)
+
+// Evidence is a photo or document of a lack found in an inspection. The file is kept
+// in the blob store under its SHA-256 hash, the hashes of the evidence of a request
+// are anchored on chain when its lacks are added.
+type Evidence struct {
+	Id                int64                `json:"id"`
+	Request           *Request             `orm:"rel(fk)" json:"-"`
+	RequestId         int64                `orm:"-" json:"requestId"`
+	Inspector         *User                `orm:"rel(fk)" json:"-"`
+	ContributionCode  uint16               `json:"contributionCode"`
+	ControlCategoryId int64                `json:"controlCategoryId"`
+	PointGroupCode    uint16               `json:"pointGroupCode"`
+	ControlPointId    int64                `json:"controlPointId"`
+	LackId            int64                `json:"lackId"`
+	FileName          string               `json:"fileName"`
+	ContentType       string               `json:"contentType"`
+	Size              int64                `json:"size"`
+	Hash              string               `json:"hash"` // SHA-256, hex
+	Anchor            *EthereumTransaction `orm:"rel(fk);null" json:"anchor"`
+	Created           time.Time            `orm:"auto_now_add;type(datetime)" json:"created"`
+}
+
+// EvidenceRecord lists the evidence of a request with the root hash anchored on chain
+type EvidenceRecord struct {
+	Evidence []*Evidence `json:"evidence"`
+	Root     string      `json:"root"`
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(Evidence))
+}
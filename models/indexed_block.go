@@ -0,0 +1,16 @@
+package models
+
+import "github.com/astaxie/beego/orm"
+
+// IndexedBlock is a block seen by the indexer. The chain of parent hashes is used to detect reorganisations.
+type IndexedBlock struct {
+	Id         int64  `json:"id"`
+	Number     uint64 `orm:"unique" json:"number"`
+	Hash       string `json:"hash"`
+	ParentHash string `json:"parentHash"`
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(IndexedBlock))
+}
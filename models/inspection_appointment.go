@@ -0,0 +1,46 @@
+package models
+
+import (
+	"github.com/astaxie/beego/orm"
+	"time"
+)
+
+const (
+	AppointmentStatusProposed  = "proposed"
+	AppointmentStatusConfirmed = "confirmed"
+	AppointmentStatusCancelled = "cancelled"
+	AppointmentStatusHeld      = "held"
+)
+
+// InspectionAppointment is the date an inspector inspects the farm of a request. An
+// announced appointment is proposed by the inspector and confirmed by the farmer, an
+// unannounced one is confirmed right away and not shown to the farmer before it is held.
+type InspectionAppointment struct {
+	Id          int64     `json:"id"`
+	Request     *Request  `orm:"rel(fk)" json:"-"`
+	RequestId   int64     `orm:"-" json:"requestId"`
+	Inspector   *User     `orm:"rel(fk)" json:"inspector"`
+	Date        time.Time `orm:"type(datetime)" json:"date"`
+	Duration    int       `json:"duration"` // minutes
+	Status      string    `json:"status"`
+	Unannounced bool      `json:"unannounced"`
+	Rescheduled int       `json:"rescheduled"` // times the date was changed
+	Remark      string    `json:"remark"`
+	Created     time.Time `orm:"auto_now_add;type(datetime)" json:"created"`
+	Modified    time.Time `orm:"auto_now;type(datetime)" json:"modified"`
+}
+
+// UnannouncedShare counts the unannounced inspections of the requests with a RAUS
+// contribution in a year
+type UnannouncedShare struct {
+	Year        int     `json:"year"`
+	Inspections int64   `json:"inspections"`
+	Unannounced int64   `json:"unannounced"`
+	Share       float64 `json:"share"`
+	Required    float64 `json:"required"`
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(InspectionAppointment))
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"github.com/astaxie/beego/orm"
+	"time"
+)
+
+const (
+	InspectionDraftStatusOpen      = "open"
+	InspectionDraftStatusFinalised = "finalised"
+)
+
+// InspectionDraft collects the lacks of an inspection on the inspector's device while
+// offline. Every change raises the version, a change based on an older version is
+// rejected as a conflict.
+type InspectionDraft struct {
+	Id        int64             `json:"id"`
+	Request   *Request          `orm:"rel(one)" json:"-"`
+	RequestId int64             `orm:"-" json:"requestId"`
+	Inspector *User             `orm:"rel(fk)" json:"-"`
+	Version   int               `json:"version"`
+	Status    string            `json:"status"`
+	DeviceId  string            `json:"deviceId"`           // device of the last change
+	LackList  string            `orm:"type(text)" json:"-"` // json of the lacks
+	Lacks     []*InspectionLack `orm:"-" json:"lacks"`
+	Notes     string            `orm:"type(text)" json:"notes"`
+	Started   time.Time         `orm:"null;type(datetime)" json:"started"`  // on the device
+	Recorded  time.Time         `orm:"null;type(datetime)" json:"recorded"` // last change on the device
+	Created   time.Time         `orm:"auto_now_add;type(datetime)" json:"created"`
+	Modified  time.Time         `orm:"auto_now;type(datetime)" json:"modified"`
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(InspectionDraft))
+}
@@ -0,0 +1,18 @@
+package models
+
+// InspectorProposal is an inspector who can take the inspection of a request
+type InspectorProposal struct {
+	Inspector       *User   `json:"inspector"`
+	OpenInspections int     `json:"openInspections"`
+	Capacity        int     `json:"capacity"`
+	Distance        float64 `json:"distance"` // km, -1 if the farm or the inspector has no location
+	Score           float64 `json:"score"`    // lower is better
+}
+
+// InspectorAssignment assigns an inspector to a request. Without an inspector the
+// best proposed one is taken.
+type InspectorAssignment struct {
+	RequestId   int64  `json:"requestId"`
+	InspectorId int64  `json:"inspectorId"`
+	Error       string `json:"error,omitempty"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"github.com/astaxie/beego/orm"
+	"time"
+)
+
+// InspectorProfile holds what the assignment of inspectors needs to know about an
+// inspector: the number of open inspections they can take, the contributions they
+// are certified for and where they are based (Swiss grid coordinates in meters).
+type InspectorProfile struct {
+	Id                int64     `json:"id"`
+	User              *User     `orm:"rel(one)" json:"-"`
+	Capacity          int       `json:"capacity"`
+	ContributionCodes string    `json:"-"` // e.g. ",5416,5417,"
+	Certifications    []uint16  `orm:"-" json:"certifications"`
+	XCoordinate       uint32    `json:"xCoordinate"`
+	YCoordinate       uint32    `json:"yCoordinate"`
+	Modified          time.Time `orm:"auto_now;type(datetime)" json:"modified"`
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(InspectorProfile))
+}
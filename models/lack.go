@@ -9,6 +9,7 @@ type Lack struct {
 	Francs       int16         `json:"francs"`
 	ControlPoint *ControlPoint `orm:"rel(fk);null" json:"-"`
 	Computed     bool          `orm:"default(false)" json:"computed"`
+	Variable     bool          `orm:"default(false)" json:"variable"` // the inspector may set the points
 }
 
 func init() {
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"github.com/astaxie/beego/orm"
+	"time"
+)
+
+const (
+	ObjectionStatusOpen    = "open"
+	ObjectionStatusUpheld  = "upheld"  // the lacks stand
+	ObjectionStatusRemoved = "removed" // lacks are reversed in the contract
+
+	ObjectionDecisionUphold = "uphold"
+	ObjectionDecisionRemove = "remove"
+)
+
+// Objection is filed by a farmer against lacks of an inspection. The payment of the
+// request is blocked until the canton decided on it and the removed lacks are
+// reversed on chain.
+type Objection struct {
+	Id            int64                  `json:"id"`
+	Request       *Request               `orm:"rel(fk)" json:"-"`
+	RequestId     int64                  `orm:"-" json:"requestId"`
+	Farmer        *User                  `orm:"rel(fk)" json:"-"`
+	Status        string                 `json:"status"`
+	Justification string                 `orm:"type(text)" json:"justification"`
+	LackIndexes   []int64                `orm:"-" json:"lackIndexes,omitempty"` // lacks objected to, when filed
+	Lacks         []*ObjectionLack       `orm:"-" json:"lacks"`
+	Attachments   []*ObjectionAttachment `orm:"-" json:"attachments"`
+	Reviewer      *User                  `orm:"rel(fk);null" json:"-"`
+	Reason        string                 `orm:"type(text)" json:"reason"` // of the decision, sent with the reversals
+	Created       time.Time              `orm:"auto_now_add;type(datetime)" json:"created"`
+	Decided       time.Time              `orm:"null;type(datetime)" json:"decided"`
+}
+
+// ObjectionReview is the decision of the canton on an objection. Removing may be
+// limited to some of the lacks, the others are upheld.
+type ObjectionReview struct {
+	Decision    string  `json:"decision"`
+	Reason      string  `json:"reason"`
+	LackIndexes []int64 `json:"lackIndexes"`
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(Objection))
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"github.com/astaxie/beego/orm"
+	"time"
+)
+
+// ObjectionAttachment is a photo or document supporting an objection, kept in the
+// evidence store under its SHA-256 hash
+type ObjectionAttachment struct {
+	Id          int64      `json:"id"`
+	Objection   *Objection `orm:"rel(fk)" json:"-"`
+	FileName    string     `json:"fileName"`
+	ContentType string     `json:"contentType"`
+	Size        int64      `json:"size"`
+	Hash        string     `json:"hash"` // SHA-256, hex
+	Created     time.Time  `orm:"auto_now_add;type(datetime)" json:"created"`
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(ObjectionAttachment))
+}
@@ -0,0 +1,23 @@
+package models
+
+import "github.com/astaxie/beego/orm"
+
+// ObjectionLack is a lack of a Request contract an objection is filed against. A lack
+// can be objected to once.
+type ObjectionLack struct {
+	Id        int64                `json:"id"`
+	Objection *Objection           `orm:"rel(fk)" json:"-"`
+	Request   *Request             `orm:"rel(fk)" json:"-"`
+	Index     int64                `json:"index"` // index of the lack in the contract
+	Removed   bool                 `json:"removed"`
+	Reversal  *EthereumTransaction `orm:"rel(fk);null" json:"reversal"`
+}
+
+func (l *ObjectionLack) TableUnique() [][]string {
+	return [][]string{{"Request", "Index"}}
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(ObjectionLack))
+}
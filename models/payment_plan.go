@@ -0,0 +1,42 @@
+package models
+
+import (
+	"math/big"
+	"time"
+)
+
+const (
+	TrancheStatusPlanned = "planned"
+	TrancheStatusDue     = "due"
+	TrancheStatusPending = "pending" // sent, not confirmed yet
+	TrancheStatusPaid    = "paid"
+)
+
+// PaymentPlan shows the tranches of a request with the amounts paid and the amounts
+// planned from the current state of its contract
+type PaymentPlan struct {
+	RequestId   int64             `json:"requestId"`
+	Canton      string            `json:"canton"`
+	Year        int               `json:"year"`
+	Entitlement *big.Int          `json:"entitlement"` // amount after deductions
+	Paid        *big.Int          `json:"paid"`
+	Tranches    []*PlannedTranche `json:"tranches"`
+}
+
+type PlannedTranche struct {
+	Position    int        `json:"position"`
+	Name        string     `json:"name"`
+	Rule        string     `json:"rule"`
+	Percentage  int        `json:"percentage"`
+	Due         *time.Time `json:"due"` // nil when due once inspected
+	Status      string     `json:"status"`
+	Amount      *big.Int   `json:"amount"` // nil for payments made before they were tracked
+	Transaction string     `json:"transaction,omitempty"`
+}
+
+// TranchePayment asks to pay the next due tranche of a request. The tranche is optional,
+// when given it has to be the next due one.
+type TranchePayment struct {
+	Id      int64 `json:"id"`
+	Tranche int   `json:"tranche"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"github.com/astaxie/beego/orm"
+	"time"
+)
+
+const (
+	PaymentRunStatusPlanning  = "planning" // the dry run is listing the transfers
+	PaymentRunStatusDryRun    = "dryRun"   // the transfers are listed, nothing is sent
+	PaymentRunStatusProposed  = "proposed"
+	PaymentRunStatusRunning   = "running"
+	PaymentRunStatusCompleted = "completed"
+	PaymentRunStatusRejected  = "rejected"
+)
+
+// PaymentRun pays the next due tranche of every request of a year which is not blocked.
+// Its dry run lists the transfers, once submitted they are paid as a payout proposal and
+// the run follows the status of the proposal.
+type PaymentRun struct {
+	Id         int64               `json:"id"`
+	Year       int                 `json:"year"`
+	Canton     string              `json:"canton"` // all cantons when empty
+	Status     string              `orm:"-" json:"status"`
+	Preparer   *User               `orm:"rel(fk)" json:"-"`
+	PreparedBy string              `orm:"-" json:"preparedBy"` // username
+	Proposal   *PayoutProposal     `orm:"rel(fk);null" json:"-"`
+	ProposalId int64               `orm:"-" json:"proposalId,omitempty"`
+	Requests   int                 `json:"requests"`  // checked by the dry run
+	Checked    int                 `json:"checked"`   // checked so far
+	Transfers  int                 `json:"transfers"` // listed by the dry run
+	Total      string              `json:"total"`
+	Skipped    int                 `json:"skipped"` // blocked requests
+	NotDue     int                 `json:"notDue"`  // requests without a due tranche
+	Errors     int                 `json:"errors"`  // requests which could not be checked
+	Progress   *PaymentRunProgress `orm:"-" json:"progress,omitempty"`
+	Items      []*PaymentRunItem   `orm:"-" json:"items,omitempty"`
+	Created    time.Time           `orm:"auto_now_add;type(datetime)" json:"created"`
+	Planned    time.Time           `orm:"null;type(datetime)" json:"planned"` // when the dry run finished
+	Submitted  time.Time           `orm:"null;type(datetime)" json:"submitted"`
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(PaymentRun))
+}
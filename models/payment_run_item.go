@@ -0,0 +1,32 @@
+package models
+
+import "github.com/astaxie/beego/orm"
+
+const (
+	PaymentRunItemStatusPlanned = "planned"
+	PaymentRunItemStatusSkipped = "skipped" // blocked when the dry run listed the transfers
+	PaymentRunItemStatusError   = "error"   // could not be checked by the dry run, e.g. the node did not answer
+	PaymentRunItemStatusDropped = "dropped" // not due anymore or changed when submitted
+)
+
+// PaymentRunItem is a transfer listed by the dry run of a payment run, or a request it
+// skipped. Once submitted it shows the status of its payout proposal item.
+type PaymentRunItem struct {
+	Id           int64               `json:"id"`
+	Run          *PaymentRun         `orm:"rel(fk)" json:"-"`
+	Request      *Request            `orm:"rel(fk)" json:"-"`
+	RequestId    int64               `orm:"-" json:"requestId"`
+	Tranche      int                 `json:"tranche,omitempty"`
+	Name         string              `json:"name,omitempty"`
+	Amount       string              `json:"amount,omitempty"`
+	Status       string              `json:"status"`
+	Reason       string              `orm:"type(text)" json:"reason,omitempty"` // why it was skipped or not sent
+	ProposalItem *PayoutProposalItem `orm:"rel(fk);null" json:"-"`
+	Attempts     int                 `orm:"-" json:"attempts,omitempty"`
+	Transaction  string              `orm:"-" json:"transaction,omitempty"`
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(PaymentRunItem))
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// PaymentRunProgress counts the transfers of a submitted payment run by their status
+type PaymentRunProgress struct {
+	Proposed  int    `json:"proposed"` // not sent yet
+	Sent      int    `json:"sent"`
+	Failed    int    `json:"failed"`
+	SentTotal string `json:"sentTotal"`
+}
+
+// PaymentRunReport sums up a payment run, who prepared and who decided on it, what was
+// paid and what was not
+type PaymentRunReport struct {
+	RunId      int64               `json:"runId"`
+	Year       int                 `json:"year"`
+	Canton     string              `json:"canton"`
+	Status     string              `json:"status"`
+	PreparedBy string              `json:"preparedBy"`
+	Decisions  []*PayoutDecision   `json:"decisions"`
+	Created    time.Time           `json:"created"`
+	Submitted  *time.Time          `json:"submitted"`
+	Decided    *time.Time          `json:"decided"`
+	Finished   *time.Time          `json:"finished"`
+	Transfers  int                 `json:"transfers"` // listed by the dry run
+	Total      string              `json:"total"`
+	Skipped    int                 `json:"skipped"`
+	NotDue     int                 `json:"notDue"`
+	Errors     []*PaymentRunItem   `json:"errors"` // not checked by the dry run
+	Progress   *PaymentRunProgress `json:"progress"`
+	Failures   []*PaymentRunItem   `json:"failures"` // dropped when submitted or not sent
+	Blocked    []*PaymentRunItem   `json:"blocked"`  // skipped by the dry run
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"github.com/astaxie/beego/orm"
+	"time"
+)
+
+// PaymentSchedule lists the tranches a canton pays the requests of a year in. Requests
+// without a schedule are paid in the two tranches of the contract.
+type PaymentSchedule struct {
+	Id       int64             `json:"id"`
+	Canton   string            `json:"canton"` // shortname, as of the requests
+	Year     int               `json:"year"`
+	Tranches []*PaymentTranche `orm:"-" json:"tranches"`
+	Actor    string            `json:"actor"`
+	Modified time.Time         `orm:"auto_now;type(datetime)" json:"modified"`
+}
+
+func (s *PaymentSchedule) TableUnique() [][]string {
+	return [][]string{{"Canton", "Year"}}
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(PaymentSchedule))
+}
@@ -0,0 +1,34 @@
+package models
+
+import "github.com/astaxie/beego/orm"
+
+const (
+	TrancheRulePreviousYear = "previousYear" // percentage of the amount of the previous year
+	TrancheRuleEntitlement  = "entitlement"  // percentage of the amount after deductions, less the tranches before
+	TrancheRuleRemainder    = "remainder"    // the amount after deductions, less the tranches before
+)
+
+// PaymentTranche is a payment of a schedule. It is due on its date of the request's year,
+// or of the following year with a YearOffset of 1, and once the request is inspected.
+// Tranches without a month are due once the request is inspected. Tranches on the amount of
+// the previous year with a month are due on their date once the request is submitted.
+type PaymentTranche struct {
+	Id         int64            `json:"id"`
+	Schedule   *PaymentSchedule `orm:"rel(fk)" json:"-"`
+	Position   int              `json:"position"` // starting at 1, in the order they are paid
+	Name       string           `json:"name"`     // message of the transfer
+	Rule       string           `json:"rule"`
+	Percentage int              `json:"percentage"`
+	DueMonth   int              `json:"dueMonth"`
+	DueDay     int              `json:"dueDay"`
+	YearOffset int              `json:"yearOffset"`
+}
+
+func (t *PaymentTranche) TableUnique() [][]string {
+	return [][]string{{"Schedule", "Position"}}
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(PaymentTranche))
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"github.com/astaxie/beego/orm"
+	"time"
+)
+
+const (
+	PayoutStatusReserved  = "reserved" // stored before the transfer is sent
+	PayoutStatusSent      = "sent"
+	PayoutStatusConfirmed = "confirmed"
+	PayoutStatusFailed    = "failed" // the tranche may be paid again
+)
+
+// Payout is stored for a tranche of a request before its transfer is sent, so that the
+// tranche cannot be paid twice while the transfer is not mined yet
+type Payout struct {
+	Id             int64                `json:"id"`
+	Request        *Request             `orm:"rel(fk)" json:"-"`
+	Tranche        int                  `json:"tranche"` // position in the schedule
+	Name           string               `json:"name"`
+	Amount         string               `json:"amount"`
+	Status         string               `json:"status"`
+	IdempotencyKey string               `orm:"index" json:"-"` // the payout proposal item it was paid for, proposal-<proposal id>-<item id>
+	Transaction    *EthereumTransaction `orm:"rel(fk);null" json:"-"`
+	Block          uint64               `json:"-"` // head when reserved, its transfer is looked for from there
+	Actor          string               `json:"actor"`
+	Created        time.Time            `orm:"auto_now_add;type(datetime)" json:"created"`
+	Updated        time.Time            `orm:"auto_now;type(datetime)" json:"updated"`
+}
+
+func (p *Payout) TableUnique() [][]string {
+	return [][]string{{"Request", "Tranche"}}
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(Payout))
+}
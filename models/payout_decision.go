@@ -0,0 +1,32 @@
+package models
+
+import (
+	"github.com/astaxie/beego/orm"
+	"time"
+)
+
+const (
+	PayoutDecisionApprove = "approve"
+	PayoutDecisionReject  = "reject"
+)
+
+// PayoutDecision is the approval or rejection of a payout proposal by an employee of the
+// canton. Every employee decides once on a proposal.
+type PayoutDecision struct {
+	Id        int64           `json:"id"`
+	Proposal  *PayoutProposal `orm:"rel(fk)" json:"-"`
+	User      *User           `orm:"rel(fk)" json:"-"`
+	DecidedBy string          `orm:"-" json:"decidedBy"` // username
+	Decision  string          `json:"decision"`
+	Reason    string          `orm:"type(text)" json:"reason"`
+	Created   time.Time       `orm:"auto_now_add;type(datetime)" json:"created"`
+}
+
+func (d *PayoutDecision) TableUnique() [][]string {
+	return [][]string{{"Proposal", "User"}}
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(PayoutDecision))
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"github.com/astaxie/beego/orm"
+	"time"
+)
+
+const (
+	PayoutProposalStatusProposed = "proposed" // waiting for the approvals
+	PayoutProposalStatusApproved = "approved" // the transfers are being sent
+	PayoutProposalStatusExecuted = "executed"
+	PayoutProposalStatusRejected = "rejected"
+)
+
+// PayoutProposal is a batch of tranches prepared by an employee of the canton. Its transfers
+// are only signed once other employees than the one who prepared it approved it.
+type PayoutProposal struct {
+	Id             int64                 `json:"id"`
+	Note           string                `orm:"type(text)" json:"note"`
+	Status         string                `json:"status"`
+	Total          string                `json:"total"`
+	Required       int                   `json:"required"` // approvals needed, two above the thresholds
+	IdempotencyKey string                `orm:"index" json:"-"`
+	Preparer       *User                 `orm:"rel(fk)" json:"-"`
+	PreparedBy     string                `orm:"-" json:"preparedBy"` // username
+	Items          []*PayoutProposalItem `orm:"-" json:"items"`
+	Decisions      []*PayoutDecision     `orm:"-" json:"decisions"`
+	Created        time.Time             `orm:"auto_now_add;type(datetime)" json:"created"`
+	Decided        time.Time             `orm:"null;type(datetime)" json:"decided"` // approved or rejected
+	Executed       time.Time             `orm:"null;type(datetime)" json:"executed"`
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(PayoutProposal))
+}
@@ -0,0 +1,30 @@
+package models
+
+import "github.com/astaxie/beego/orm"
+
+const (
+	PayoutItemStatusProposed = "proposed"
+	PayoutItemStatusSent     = "sent"
+	PayoutItemStatusFailed   = "failed" // not sent, the tranche can be proposed again
+)
+
+// PayoutProposalItem is the tranche of a request paid by a proposal, with the amount
+// planned when it was prepared
+type PayoutProposalItem struct {
+	Id          int64           `json:"id"`
+	Proposal    *PayoutProposal `orm:"rel(fk)" json:"-"`
+	Request     *Request        `orm:"rel(fk)" json:"-"`
+	RequestId   int64           `orm:"-" json:"requestId"`
+	Tranche     int             `json:"tranche"` // position in the schedule, optional when proposed
+	Name        string          `json:"name"`
+	Amount      string          `json:"amount"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"` // to send the transfer
+	Transaction string          `json:"transaction,omitempty"`
+	Message     string          `orm:"type(text)" json:"message,omitempty"` // why it was not sent
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(PayoutProposalItem))
+}
@@ -4,12 +4,13 @@ import "github.com/astaxie/beego/orm"
 
 type Plant struct {
 	Id                int64      `json:"id"`
+	User              *User      `orm:"rel(fk);null" json:"-"`
 	CantonalPlantNr   string     `json:"cantonalPlantNr"` // e.g. 1234/70/123
 	Community         string     `json:"community"`       // Deutsch: Gemeinde
 	CommunityNr       uint16     `json:"communityNr"`
 	Name              string     `json:"name"`
 	XCoordinate       uint32     `json:"xCoordinate"`
-	YCoordinate       uint32     `json:"xCoordinate"`
+	YCoordinate       uint32     `json:"yCoordinate"`
 	PlantType         *PlantType `orm:"rel(fk);null" json:"plantType"`
 	MetersAboveTheSea int16      `json:"metersAboveTheSea"`
 	// TODO:
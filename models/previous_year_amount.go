@@ -0,0 +1,26 @@
+package models
+
+import (
+	"github.com/astaxie/beego/orm"
+	"time"
+)
+
+// PreviousYearAmount is set by an admin for a farmer who is new or had no request in
+// the previous year. It replaces the amount paid for the previous year's request.
+type PreviousYearAmount struct {
+	Id       int64     `json:"id"`
+	User     *User     `orm:"rel(fk)" json:"-"`
+	Year     int       `json:"year"`
+	Amount   string    `json:"amount"` // aPayment token
+	Actor    string    `json:"actor"`
+	Modified time.Time `orm:"auto_now;type(datetime)" json:"modified"`
+}
+
+func (a *PreviousYearAmount) TableUnique() [][]string {
+	return [][]string{{"User", "Year"}}
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(PreviousYearAmount))
+}
@@ -0,0 +1,26 @@
+package models
+
+import "github.com/astaxie/beego/orm"
+
+const (
+	DiscrepancyKindMismatch  = "mismatch"  // the contract differs from the calculation
+	DiscrepancyKindUnderflow = "underflow" // the contract arithmetic wraps around
+)
+
+// ReconciliationDiscrepancy is a value of a point group which was flagged in a reconciliation.
+// The point group code is 0 for the final payment.
+type ReconciliationDiscrepancy struct {
+	Id             int64                  `json:"id"`
+	Reconciliation *RequestReconciliation `orm:"rel(fk)" json:"-"`
+	Kind           string                 `json:"kind"`
+	PointGroupCode uint16                 `json:"pointGroupCode"`
+	Field          string                 `json:"field"`
+	Expected       string                 `json:"expected"` // calculated off-chain
+	Actual         string                 `json:"actual"`   // read from the contract
+	Message        string                 `json:"message"`
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(ReconciliationDiscrepancy))
+}
@@ -10,21 +10,42 @@ func init() {
 	orm.RegisterModel(new(Request))
 }
 
+// a farmer makes one request per year, withdrawn and rejected requests free their year
+func (r *Request) TableUnique() [][]string {
+	return [][]string{{"User", "OpenYear"}}
+}
+
 type Request struct {
 	Id   int64 `json:"id"`
 	User *User `orm:"rel(fk)" json:"user"`
 
 	Address       string          `json:"address"`
+	Year          int             `json:"year"` // reference year of GVE, catalog, RAUS checks and payments
+	OpenYear      *int            `orm:"null" json:"-"` // year of a request not withdrawn or rejected, one per farmer
 	Contributions []*Contribution `orm:"-" json:"contributions"`
-	Remark        string          `orm:"-" json:"remark"`
+	Remark        string          `orm:"type(text)" json:"remark"`
 	Created       *big.Int        `orm:"-" json:"created"`
 	Modified      *big.Int        `orm:"-" json:"modified"`
 
+	Status      string               `json:"status"`
+	Transitions []*RequestTransition `orm:"reverse(many)" json:"transitions"`
+	Amendments  []*RequestAmendment  `orm:"reverse(many)" json:"amendments"`
+
 	Inspector              *User           `orm:"rel(fk);null" json:"inspector"`
 	ContributionsWithLacks []*Contribution `orm:"-" json:"contributionsWithLacks"`
 
 	GVE      []*GVE                      `orm:"-" json:"gve"`
 	Payments []*APaymentTokenTransaction `orm:"-" json:"payments"`
+
+	// read model, updated from the events of the contract
+	NumLacks    int64  `json:"numLacks"`
+	SyncedBlock uint64 `json:"-"` // last block whose events have been applied
+
+	// read model, copied from the contract when our transactions are mined and by the sync job
+	ContractCreated   uint64 `json:"-"`
+	ContractModified  uint64 `json:"-"`
+	ContributionCodes string `json:"-"` // comma separated, enclosed in commas
+	Canton            string `json:"canton"`
 }
 
 type GVE struct {
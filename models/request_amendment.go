@@ -0,0 +1,21 @@
+package models
+
+import (
+	"github.com/astaxie/beego/orm"
+	"time"
+)
+
+// RequestAmendment links the contract replacing an amended request to the retired one
+type RequestAmendment struct {
+	Id              int64     `json:"id"`
+	Request         *Request  `orm:"rel(fk)" json:"-"`
+	PreviousAddress string    `json:"previousAddress"` // killed contract
+	Address         string    `json:"address"`         // replacement contract
+	Actor           string    `json:"actor"`
+	Created         time.Time `orm:"auto_now_add;type(datetime)" json:"created"`
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(RequestAmendment))
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"github.com/astaxie/beego/orm"
+	"time"
+)
+
+// RequestDraft holds the content of a request that has not been submitted yet.
+// Once submitted it is stored in the contract of the request, it is archived here
+// again when the contract is killed.
+type RequestDraft struct {
+	Id                int64     `json:"id"`
+	Request           *Request  `orm:"rel(one)" json:"-"`
+	ContributionCodes string    `json:"contributionCodes"` // comma separated
+	Remark            string    `orm:"type(text)" json:"remark"`
+	Modified          time.Time `orm:"auto_now;type(datetime)" json:"modified"`
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(RequestDraft))
+}
@@ -0,0 +1,27 @@
+package models
+
+import "github.com/astaxie/beego/orm"
+
+// RequestLack is a lack stored in a Request contract, copied from the contract when LacksAdded is emitted.
+// Points keeps the points the lack was added with, also once it is reversed.
+type RequestLack struct {
+	Id                int64    `json:"id"`
+	Request           *Request `orm:"rel(fk)" json:"-"`
+	Index             int64    `json:"index"` // index of the lack in the contract
+	ContributionCode  uint16   `json:"contributionCode"`
+	ControlCategoryId int64    `json:"controlCategoryId"`
+	PointGroupCode    uint16   `json:"pointGroupCode"`
+	ControlPointId    int64    `json:"controlPointId"`
+	LackId            int64    `json:"lackId"`
+	Points            uint8    `json:"points"`
+	Reversed          bool     `json:"reversed"` // after an objection, the points no longer count
+}
+
+func (l *RequestLack) TableUnique() [][]string {
+	return [][]string{{"Request", "Index"}}
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(RequestLack))
+}
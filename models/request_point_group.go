@@ -0,0 +1,20 @@
+package models
+
+import "github.com/astaxie/beego/orm"
+
+// RequestPointGroup holds the GVE of a point group of a Request contract, copied from its GVESet events
+type RequestPointGroup struct {
+	Id             int64    `json:"id"`
+	Request        *Request `orm:"rel(fk)" json:"-"`
+	PointGroupCode uint16   `json:"pointGroupCode"`
+	Gve            uint32   `json:"gve"` // multiplied by 10'000
+}
+
+func (p *RequestPointGroup) TableUnique() [][]string {
+	return [][]string{{"Request", "PointGroupCode"}}
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(RequestPointGroup))
+}
@@ -0,0 +1,26 @@
+package models
+
+import "math/big"
+
+// RequestPreview asks what a request pays with hypothetical lacks. With a requestId the
+// GVE, the amount of the previous year and the lacks come from the request's contract
+// and the lacks of the preview are added to them.
+type RequestPreview struct {
+	RequestId          int64          `json:"requestId"`
+	Year               int            `json:"year"`
+	ContributionCodes  []uint16       `json:"contributionCodes"`
+	GVE                []*PreviewGVE  `json:"gve"`
+	AmountPreviousYear *big.Int       `json:"amountPreviousYear"` // aPayment token, by default of the farmer's previous request
+	Lacks              []*PreviewLack `json:"lacks"`
+}
+
+type PreviewGVE struct {
+	PointGroupCode uint16  `json:"pointGroupCode"`
+	Amount         float64 `json:"amount"`
+}
+
+type PreviewLack struct {
+	ContributionCode uint16 `json:"contributionCode"`
+	PointGroupCode   uint16 `json:"pointGroupCode"`
+	Points           uint8  `json:"points"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"github.com/astaxie/beego/orm"
+	"time"
+)
+
+const (
+	ReconciliationStatusMatched = "matched"
+	ReconciliationStatusFlagged = "flagged" // the payment is blocked
+)
+
+// RequestReconciliation is the latest cross-check of the point groups of a Request contract
+// with the calculation from the read model
+type RequestReconciliation struct {
+	Id            int64                        `json:"id"`
+	Request       *Request                     `orm:"rel(one)" json:"-"`
+	RequestId     int64                        `orm:"-" json:"requestId"`
+	Status        string                       `json:"status"`
+	Discrepancies []*ReconciliationDiscrepancy `orm:"-" json:"discrepancies"`
+	Checked       time.Time                    `orm:"type(datetime)" json:"checked"`
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(RequestReconciliation))
+}
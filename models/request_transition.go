@@ -0,0 +1,40 @@
+package models
+
+import (
+	"github.com/astaxie/beego/orm"
+	"time"
+)
+
+const (
+	RequestStatusDraft             = "draft"
+	RequestStatusSubmitted         = "submitted"
+	RequestStatusInspectorAssigned = "inspectorAssigned"
+	RequestStatusInspected         = "inspected"
+	RequestStatusFirstPaymentMade  = "firstPaymentMade"
+	RequestStatusFinalPaymentMade  = "finalPaymentMade"
+	RequestStatusClosed            = "closed"
+	RequestStatusWithdrawn         = "withdrawn"
+	RequestStatusRejected          = "rejected"
+)
+
+// RequestTransition is an entry of the lifecycle history of a request
+type RequestTransition struct {
+	Id      int64     `json:"id"`
+	Request *Request  `orm:"rel(fk)" json:"-"`
+	From    string    `json:"from"`
+	To      string    `json:"to"`
+	Actor   string    `json:"actor"` // address of the user, empty for the system
+	Reason  string    `json:"reason"`
+	Created time.Time `orm:"auto_now_add;type(datetime)" json:"created"`
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(RequestTransition))
+}
+
+// RequestDecision is the body of a withdrawal or a rejection
+type RequestDecision struct {
+	RequestId int64  `json:"requestId"`
+	Reason    string `json:"reason"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"github.com/astaxie/beego/orm"
+	"time"
+)
+
+const (
+	TransactionKindTopUp        = "topUp"
+	TransactionKindRole         = "role"
+	TransactionKindSetInspector = "setInspector"
+	TransactionKindAddLacks     = "addLacks"
+	TransactionKindTransfer     = "transfer"
+	TransactionKindKill         = "kill"
+	TransactionKindDeploy       = "deploy"
+	TransactionKindEvidence     = "evidence"
+	TransactionKindReverseLack  = "reverseLack"
+
+	TransactionStatusPending   = "pending"
+	TransactionStatusMined     = "mined"
+	TransactionStatusConfirmed = "confirmed"
+	TransactionStatusFailed    = "failed"
+)
+
+// EthereumTransaction is a transaction sent by the backend, tracked until it is confirmed
+type EthereumTransaction struct {
+	Id      int64     `json:"id"`
+	Hash    string    `orm:"unique" json:"hash"`
+	Kind    string    `json:"kind"`
+	From    string    `json:"from"`
+	To      string    `json:"to"`
+	Value   string    `json:"value"`  // wei
+	Amount  string    `json:"amount"` // aPayment token of transfers
+	Request *Request  `orm:"rel(fk);null" json:"-"`
+	Account string    `json:"account"` // address of the user the transaction is about
+	Detail  string    `json:"detail"`
+	Status  string    `json:"status"`
+	Block   uint64    `json:"block"` // block it was mined in, or the head when the receipt was first seen
+	Created time.Time `orm:"auto_now_add;type(datetime)" json:"created"`
+	Updated time.Time `orm:"auto_now;type(datetime)" json:"updated"`
+}
+
+func init() {
+	// Register model
+	orm.RegisterModel(new(EthereumTransaction))
+}
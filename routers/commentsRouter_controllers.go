@@ -23,6 +23,70 @@ func init() {
 			MethodParams: param.Make(),
 			Params: nil})
 
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:AppointmentController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:AppointmentController"],
+		beego.ControllerComments{
+			Method: "Post",
+			Router: `/`,
+			AllowHTTPMethods: []string{"post"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:AppointmentController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:AppointmentController"],
+		beego.ControllerComments{
+			Method: "Get",
+			Router: `/:appointmentId`,
+			AllowHTTPMethods: []string{"get"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:AppointmentController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:AppointmentController"],
+		beego.ControllerComments{
+			Method: "Cancel",
+			Router: `/:appointmentId/cancel`,
+			AllowHTTPMethods: []string{"post"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:AppointmentController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:AppointmentController"],
+		beego.ControllerComments{
+			Method: "Confirm",
+			Router: `/:appointmentId/confirm`,
+			AllowHTTPMethods: []string{"post"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:AppointmentController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:AppointmentController"],
+		beego.ControllerComments{
+			Method: "Reschedule",
+			Router: `/:appointmentId/reschedule`,
+			AllowHTTPMethods: []string{"put"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:AppointmentController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:AppointmentController"],
+		beego.ControllerComments{
+			Method: "Calendar",
+			Router: `/calendar/:inspectorId`,
+			AllowHTTPMethods: []string{"get"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:AppointmentController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:AppointmentController"],
+		beego.ControllerComments{
+			Method: "GetForRequest",
+			Router: `/request/:requestId`,
+			AllowHTTPMethods: []string{"get"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:AppointmentController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:AppointmentController"],
+		beego.ControllerComments{
+			Method: "GetUnannouncedShare",
+			Router: `/unannounced`,
+			AllowHTTPMethods: []string{"get"},
+			MethodParams: param.Make(),
+			Params: nil})
+
 	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:CategoryController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:CategoryController"],
 		beego.ControllerComments{
 			Method: "GetCategories",
@@ -119,6 +183,62 @@ func init() {
 			MethodParams: param.Make(),
 			Params: nil})
 
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:EventController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:EventController"],
+		beego.ControllerComments{
+			Method: "Stream",
+			Router: `/stream`,
+			AllowHTTPMethods: []string{"get"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:EventController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:EventController"],
+		beego.ControllerComments{
+			Method: "Ticket",
+			Router: `/ticket`,
+			AllowHTTPMethods: []string{"post"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:EvidenceController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:EvidenceController"],
+		beego.ControllerComments{
+			Method: "Post",
+			Router: `/`,
+			AllowHTTPMethods: []string{"post"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:EvidenceController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:EvidenceController"],
+		beego.ControllerComments{
+			Method: "Delete",
+			Router: `/:evidenceId`,
+			AllowHTTPMethods: []string{"delete"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:EvidenceController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:EvidenceController"],
+		beego.ControllerComments{
+			Method: "Download",
+			Router: `/:evidenceId/file`,
+			AllowHTTPMethods: []string{"get"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:EvidenceController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:EvidenceController"],
+		beego.ControllerComments{
+			Method: "Anchor",
+			Router: `/anchor/:requestId`,
+			AllowHTTPMethods: []string{"post"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:EvidenceController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:EvidenceController"],
+		beego.ControllerComments{
+			Method: "GetForRequest",
+			Router: `/request/:requestId`,
+			AllowHTTPMethods: []string{"get"},
+			MethodParams: param.Make(),
+			Params: nil})
+
 	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:JournalController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:JournalController"],
 		beego.ControllerComments{
 			Method: "AddJournalEntry",
@@ -159,6 +279,158 @@ func init() {
 			MethodParams: param.Make(),
 			Params: nil})
 
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:ObjectionController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:ObjectionController"],
+		beego.ControllerComments{
+			Method: "Post",
+			Router: `/`,
+			AllowHTTPMethods: []string{"post"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:ObjectionController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:ObjectionController"],
+		beego.ControllerComments{
+			Method: "Get",
+			Router: `/:objectionId`,
+			AllowHTTPMethods: []string{"get"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:ObjectionController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:ObjectionController"],
+		beego.ControllerComments{
+			Method: "Attach",
+			Router: `/:objectionId/attachment`,
+			AllowHTTPMethods: []string{"post"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:ObjectionController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:ObjectionController"],
+		beego.ControllerComments{
+			Method: "RetryReversals",
+			Router: `/:objectionId/reversals`,
+			AllowHTTPMethods: []string{"post"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:ObjectionController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:ObjectionController"],
+		beego.ControllerComments{
+			Method: "Review",
+			Router: `/:objectionId/review`,
+			AllowHTTPMethods: []string{"post"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:ObjectionController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:ObjectionController"],
+		beego.ControllerComments{
+			Method: "Download",
+			Router: `/attachment/:attachmentId/file`,
+			AllowHTTPMethods: []string{"get"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:ObjectionController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:ObjectionController"],
+		beego.ControllerComments{
+			Method: "GetForRequest",
+			Router: `/request/:requestId`,
+			AllowHTTPMethods: []string{"get"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PaymentRunController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PaymentRunController"],
+		beego.ControllerComments{
+			Method: "Post",
+			Router: `/`,
+			AllowHTTPMethods: []string{"post"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PaymentRunController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PaymentRunController"],
+		beego.ControllerComments{
+			Method: "GetAll",
+			Router: `/`,
+			AllowHTTPMethods: []string{"get"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PaymentRunController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PaymentRunController"],
+		beego.ControllerComments{
+			Method: "Get",
+			Router: `/:runId`,
+			AllowHTTPMethods: []string{"get"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PaymentRunController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PaymentRunController"],
+		beego.ControllerComments{
+			Method: "GetReport",
+			Router: `/:runId/report`,
+			AllowHTTPMethods: []string{"get"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PaymentRunController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PaymentRunController"],
+		beego.ControllerComments{
+			Method: "Submit",
+			Router: `/:runId/submit`,
+			AllowHTTPMethods: []string{"post"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PaymentScheduleController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PaymentScheduleController"],
+		beego.ControllerComments{
+			Method: "Put",
+			Router: `/`,
+			AllowHTTPMethods: []string{"put"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PaymentScheduleController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PaymentScheduleController"],
+		beego.ControllerComments{
+			Method: "Get",
+			Router: `/:canton/:year`,
+			AllowHTTPMethods: []string{"get"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PayoutProposalController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PayoutProposalController"],
+		beego.ControllerComments{
+			Method: "Post",
+			Router: `/`,
+			AllowHTTPMethods: []string{"post"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PayoutProposalController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PayoutProposalController"],
+		beego.ControllerComments{
+			Method: "GetAll",
+			Router: `/`,
+			AllowHTTPMethods: []string{"get"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PayoutProposalController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PayoutProposalController"],
+		beego.ControllerComments{
+			Method: "Get",
+			Router: `/:proposalId`,
+			AllowHTTPMethods: []string{"get"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PayoutProposalController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PayoutProposalController"],
+		beego.ControllerComments{
+			Method: "Approve",
+			Router: `/:proposalId/approve`,
+			AllowHTTPMethods: []string{"post"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PayoutProposalController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PayoutProposalController"],
+		beego.ControllerComments{
+			Method: "Reject",
+			Router: `/:proposalId/reject`,
+			AllowHTTPMethods: []string{"post"},
+			MethodParams: param.Make(),
+			Params: nil})
+
 	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PingController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:PingController"],
 		beego.ControllerComments{
 			Method: "Ping",
@@ -191,6 +463,30 @@ func init() {
 			MethodParams: param.Make(),
 			Params: nil})
 
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:ReconciliationController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:ReconciliationController"],
+		beego.ControllerComments{
+			Method: "GetAll",
+			Router: `/`,
+			AllowHTTPMethods: []string{"get"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:ReconciliationController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:ReconciliationController"],
+		beego.ControllerComments{
+			Method: "GetForRequest",
+			Router: `/request/:requestId`,
+			AllowHTTPMethods: []string{"get"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:ReconciliationController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:ReconciliationController"],
+		beego.ControllerComments{
+			Method: "Reconcile",
+			Router: `/request/:requestId`,
+			AllowHTTPMethods: []string{"post"},
+			MethodParams: param.Make(),
+			Params: nil})
+
 	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"],
 		beego.ControllerComments{
 			Method: "Post",
@@ -215,6 +511,54 @@ func init() {
 			MethodParams: param.Make(),
 			Params: nil})
 
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"],
+		beego.ControllerComments{
+			Method: "GetInspectorProposals",
+			Router: `/:requestId/inspectors`,
+			AllowHTTPMethods: []string{"get"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"],
+		beego.ControllerComments{
+			Method: "GetPlan",
+			Router: `/:requestId/plan`,
+			AllowHTTPMethods: []string{"get"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"],
+		beego.ControllerComments{
+			Method: "GetReport",
+			Router: `/:requestId/report.pdf`,
+			AllowHTTPMethods: []string{"get"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"],
+		beego.ControllerComments{
+			Method: "Amend",
+			Router: `/amend`,
+			AllowHTTPMethods: []string{"put"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"],
+		beego.ControllerComments{
+			Method: "PostDraft",
+			Router: `/draft`,
+			AllowHTTPMethods: []string{"post"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"],
+		beego.ControllerComments{
+			Method: "PutDraft",
+			Router: `/draft`,
+			AllowHTTPMethods: []string{"put"},
+			MethodParams: param.Make(),
+			Params: nil})
+
 	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"],
 		beego.ControllerComments{
 			Method: "UpdateGVE",
@@ -239,6 +583,38 @@ func init() {
 			MethodParams: param.Make(),
 			Params: nil})
 
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"],
+		beego.ControllerComments{
+			Method: "PostInspectionDraft",
+			Router: `/inspection/draft`,
+			AllowHTTPMethods: []string{"post"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"],
+		beego.ControllerComments{
+			Method: "PutInspectionDraft",
+			Router: `/inspection/draft`,
+			AllowHTTPMethods: []string{"put"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"],
+		beego.ControllerComments{
+			Method: "GetInspectionDraft",
+			Router: `/inspection/draft/:requestId`,
+			AllowHTTPMethods: []string{"get"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"],
+		beego.ControllerComments{
+			Method: "FinaliseInspection",
+			Router: `/inspection/finalise`,
+			AllowHTTPMethods: []string{"post"},
+			MethodParams: param.Make(),
+			Params: nil})
+
 	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"],
 		beego.ControllerComments{
 			Method: "AddInspector",
@@ -247,6 +623,14 @@ func init() {
 			MethodParams: param.Make(),
 			Params: nil})
 
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"],
+		beego.ControllerComments{
+			Method: "AssignInspectors",
+			Router: `/inspector/bulk`,
+			AllowHTTPMethods: []string{"put"},
+			MethodParams: param.Make(),
+			Params: nil})
+
 	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"],
 		beego.ControllerComments{
 			Method: "Pay",
@@ -255,6 +639,46 @@ func init() {
 			MethodParams: param.Make(),
 			Params: nil})
 
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"],
+		beego.ControllerComments{
+			Method: "Preview",
+			Router: `/preview`,
+			AllowHTTPMethods: []string{"post"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"],
+		beego.ControllerComments{
+			Method: "Reject",
+			Router: `/reject`,
+			AllowHTTPMethods: []string{"post"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"],
+		beego.ControllerComments{
+			Method: "Submit",
+			Router: `/submit`,
+			AllowHTTPMethods: []string{"post"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"],
+		beego.ControllerComments{
+			Method: "Validate",
+			Router: `/validate`,
+			AllowHTTPMethods: []string{"post"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:RequestController"],
+		beego.ControllerComments{
+			Method: "Withdraw",
+			Router: `/withdraw`,
+			AllowHTTPMethods: []string{"post"},
+			MethodParams: param.Make(),
+			Params: nil})
+
 	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:UserController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:UserController"],
 		beego.ControllerComments{
 			Method: "Post",
@@ -295,6 +719,30 @@ func init() {
 			MethodParams: param.Make(),
 			Params: nil})
 
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:UserController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:UserController"],
+		beego.ControllerComments{
+			Method: "PutInspectorProfile",
+			Router: `/:uid/inspectorprofile`,
+			AllowHTTPMethods: []string{"put"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:UserController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:UserController"],
+		beego.ControllerComments{
+			Method: "PutPlant",
+			Router: `/:uid/plant`,
+			AllowHTTPMethods: []string{"put"},
+			MethodParams: param.Make(),
+			Params: nil})
+
+	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:UserController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:UserController"],
+		beego.ControllerComments{
+			Method: "PutPreviousYearAmount",
+			Router: `/:uid/previousyearamount`,
+			AllowHTTPMethods: []string{"put"},
+			MethodParams: param.Make(),
+			Params: nil})
+
 	beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:UserController"] = append(beego.GlobalControllerRouter["github.com/scmo/apayment-backend/controllers:UserController"],
 		beego.ControllerComments{
 			Method: "Login",
@@ -83,6 +83,46 @@ func init() {
 				&controllers.CategoryController{},
 			),
 		),
+		beego.NSNamespace("/event",
+			beego.NSInclude(
+				&controllers.EventController{},
+			),
+		),
+		beego.NSNamespace("/appointment",
+			beego.NSInclude(
+				&controllers.AppointmentController{},
+			),
+		),
+		beego.NSNamespace("/evidence",
+			beego.NSInclude(
+				&controllers.EvidenceController{},
+			),
+		),
+		beego.NSNamespace("/objection",
+			beego.NSInclude(
+				&controllers.ObjectionController{},
+			),
+		),
+		beego.NSNamespace("/reconciliation",
+			beego.NSInclude(
+				&controllers.ReconciliationController{},
+			),
+		),
+		beego.NSNamespace("/payment-schedule",
+			beego.NSInclude(
+				&controllers.PaymentScheduleController{},
+			),
+		),
+		beego.NSNamespace("/payout-proposal",
+			beego.NSInclude(
+				&controllers.PayoutProposalController{},
+			),
+		),
+		beego.NSNamespace("/payment-run",
+			beego.NSInclude(
+				&controllers.PaymentRunController{},
+			),
+		),
 		beego.NSNamespace("/ping",
 			beego.NSInclude(
 				&controllers.PingController{},
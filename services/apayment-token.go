@@ -1,7 +1,12 @@
 package services
 
 import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"math/big"
 
 	"encoding/hex"
@@ -13,44 +18,118 @@ import (
 	"github.com/scmo/apayment-backend/services/tvd"
 	"github.com/scmo/apayment-backend/smart-contracts/apayment-token"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 )
 
 func Transfer(aPaymentTokenTransfer *models.APaymentTokenTransfer, requestAddress string) error {
+	_, err := sendTransfer(aPaymentTokenTransfer, requestAddress)
+	return err
+}
+
+// sendTransfer sends a transfer and returns the transaction it is tracked with. A transfer
+// which was sent but could not be stored is returned without id.
+func sendTransfer(aPaymentTokenTransfer *models.APaymentTokenTransfer, requestAddress string) (*models.EthereumTransaction, error) {
 	// check if sender has enough fund
 	balance, err := GetBalanceOf(common.HexToAddress(aPaymentTokenTransfer.From))
 	if err != nil {
 		beego.Error("Error while reading balance. ", err)
+		return nil, err
 	}
 	if aPaymentTokenTransfer.Amount.Cmp(balance) == 1 {
 		// +1 if x >  y
 		beego.Info("aPaymetToken balance is too small.")
-		return errors.New("aPaymetToken balance is too small.")
+		return nil, errors.New("aPaymetToken balance is too small.")
 	}
 
 	ethereumController := ethereum.GetEthereumController()
 	token, err := apaymenttoken.NewAPaymentTokenContract(common.HexToAddress(beego.AppConfig.String("apaymentTokenContract")), ethereumController.Client)
 	if err != nil {
 		beego.Critical("Failed to instantiate a APaymentTokenContract contract:", err)
-		return err
+		return nil, err
 
 	}
+	auth, err := ethereum.GetAuth(aPaymentTokenTransfer.From)
+	if err != nil {
+		return nil, err
+	}
+	transaction := &models.EthereumTransaction{
+		Kind:    models.TransactionKindTransfer,
+		From:    aPaymentTokenTransfer.From,
+		Account: aPaymentTokenTransfer.To,
+		Amount:  aPaymentTokenTransfer.Amount.String(),
+		Detail:  aPaymentTokenTransfer.Message,
+	}
+	var tx *types.Transaction
 	if len(requestAddress) == 0 {
-		tx, err := token.TransferWithMessage(ethereum.GetAuth(aPaymentTokenTransfer.From), common.HexToAddress(aPaymentTokenTransfer.To), aPaymentTokenTransfer.Amount, []byte(aPaymentTokenTransfer.Message))
+		tx, err = token.TransferWithMessage(auth, common.HexToAddress(aPaymentTokenTransfer.To), aPaymentTokenTransfer.Amount, []byte(aPaymentTokenTransfer.Message))
 		if err != nil {
 			beego.Error("Failed to send new transaction: ", err)
-			return err
+			return nil, err
 		}
 		beego.Info("Transaction waiting to be mined: ", tx.Hash().String())
 	} else {
-		tx, err := token.TransferWithMessageAndRequestAddress(ethereum.GetAuth(aPaymentTokenTransfer.From), common.HexToAddress(aPaymentTokenTransfer.To), aPaymentTokenTransfer.Amount, common.HexToAddress(requestAddress), []byte(aPaymentTokenTransfer.Message))
+		tx, err = token.TransferWithMessageAndRequestAddress(auth, common.HexToAddress(aPaymentTokenTransfer.To), aPaymentTokenTransfer.Amount, common.HexToAddress(requestAddress), []byte(aPaymentTokenTransfer.Message))
 		if err != nil {
 			beego.Error("Failed to send new transaction: ", err)
-			return err
+			return nil, err
 		}
 		beego.Info("Transaction waiting to be mined: ", tx.Hash().String())
+		if requestId := GetRequestIdByAddress(requestAddress); requestId != 0 {
+			transaction.Request = &models.Request{Id: requestId}
+		}
 	}
-	return err
+	if err := TrackTransaction(tx, transaction); err != nil {
+		// the tokens are sent already, the caller must not send them again
+		beego.Error("Transfer ", tx.Hash().String(), " was sent but is not tracked: ", err)
+	}
+	return transaction, nil
+}
+
+// filterRequestTransfers returns the transfer events of the token between the given accounts
+// whose transaction was sent with transferWithMessageAndRequestAddress for the request
+func filterRequestTransfers(opts *bind.FilterOpts, from []common.Address, to []common.Address, requestAddress string) ([]*apaymenttoken.APaymentTokenContractTransfer, error) {
+	ethereumController := ethereum.GetEthereumController()
+	token, err := apaymenttoken.NewAPaymentTokenContract(common.HexToAddress(beego.AppConfig.String("apaymentTokenContract")), ethereumController.Client)
+	if err != nil {
+		beego.Critical("Failed to instantiate a APaymentTokenContract contract:", err)
+		return nil, err
+	}
+	tokenABI, err := abi.JSON(strings.NewReader(apaymenttoken.APaymentTokenContractABI))
+	if err != nil {
+		return nil, err
+	}
+	iterator, err := token.FilterTransfer(opts, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer iterator.Close()
+	transfers := make([]*apaymenttoken.APaymentTokenContractTransfer, 0)
+	for iterator.Next() {
+		if iterator.Event.Raw.Removed {
+			continue
+		}
+		tx, _, err := ethereumController.Client.TransactionByHash(context.Background(), iterator.Event.Raw.TxHash)
+		if err != nil {
+			return nil, err
+		}
+		if len(tx.Data()) < 4 {
+			continue
+		}
+		method, err := tokenABI.MethodById(tx.Data()[:4])
+		if err != nil || method.Name != "transferWithMessageAndRequestAddress" {
+			continue
+		}
+		inputs, err := method.Inputs.UnpackValues(tx.Data()[4:])
+		if err != nil {
+			return nil, err
+		}
+		if address, ok := inputs[2].(common.Address); ok && address == common.HexToAddress(requestAddress) {
+			transfers = append(transfers, iterator.Event)
+		}
+	}
+	return transfers, iterator.Error()
 }
 
 func GetBalanceOf(address common.Address) (*big.Int, error) {
@@ -81,6 +160,21 @@ func GetTransactionForRequest(requestAddress string) []*models.APaymentTokenTran
 	return requestTransaction
 }
 
+// GetConfirmedTransactionsForRequest returns the payments of a request mined at least confirmationDepth blocks ago
+func GetConfirmedTransactionsForRequest(requestAddress string) ([]*models.APaymentTokenTransaction, error) {
+	confirmed, err := ethereum.ConfirmedBlockNumber()
+	if err != nil {
+		return nil, err
+	}
+	transactions := make([]*models.APaymentTokenTransaction, 0)
+	for _, tx := range GetTransactionForRequest(requestAddress) {
+		if tx.BlockNumber <= confirmed {
+			transactions = append(transactions, tx)
+		}
+	}
+	return transactions, nil
+}
+
 func GetTransactions() ([]*models.APaymentTokenTransaction, error) {
 	transactions := make([]*models.APaymentTokenTransaction, 0)
 	etherScanResult, err := fetchTransaction()
@@ -120,13 +214,14 @@ func GetTransactions() ([]*models.APaymentTokenTransaction, error) {
 			//beego.Error("Error while getting User by Address. ", err)
 			continue
 		}
+		blockNumber, _ := strconv.ParseUint(tx.BlockNumber, 10, 64)
 		if requestAddress == "" {
-			transactions = append(transactions, &models.APaymentTokenTransaction{From: from, To: to, Amount: &amount, Timestamp: tx.Timestamp, Message: msg})
+			transactions = append(transactions, &models.APaymentTokenTransaction{From: from, To: to, Amount: &amount, Timestamp: tx.Timestamp, BlockNumber: blockNumber, Message: msg})
 		} else {
 			requestId := GetRequestIdByAddress(requestAddress)
 			if requestId != 0 {
 				request := GetRequestById(requestId, false)
-				transactions = append(transactions, &models.APaymentTokenTransaction{From: from, To: to, Amount: &amount, Timestamp: tx.Timestamp, Message: msg, Request: request})
+				transactions = append(transactions, &models.APaymentTokenTransaction{From: from, To: to, Amount: &amount, Timestamp: tx.Timestamp, BlockNumber: blockNumber, Message: msg, Request: request})
 			}
 		}
 	}
@@ -215,3 +310,69 @@ func parseInput(input string) (string, big.Int, string, string, error) {
 	//beego.Info(requestAddress)
 	return dst, amount, msg, requestAddress, err
 }
+
+var transactionSortFields = map[string]func(a, b *models.APaymentTokenTransaction) bool{
+	"blockNumber": func(a, b *models.APaymentTokenTransaction) bool { return a.BlockNumber < b.BlockNumber },
+	"amount":      func(a, b *models.APaymentTokenTransaction) bool { return a.Amount.Cmp(b.Amount) < 0 },
+}
+
+// GetAllTransactions loads a page of the token transactions, filtered by request and
+// account (the id of the sending or receiving user). They are read from Etherscan, so
+// the page is cut from the whole list.
+func GetAllTransactions(query *ListQuery) (*Page, error) {
+	var requestId, accountId int64
+	for name, value := range query.Filters {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, &ListQueryError{Message: "Invalid number " + value}
+		}
+		switch name {
+		case "request":
+			requestId = id
+		case "account":
+			accountId = id
+		default:
+			return nil, &ListQueryError{Message: "Unknown filter " + name}
+		}
+	}
+	less, descending := transactionSortFields["blockNumber"], false
+	if query.Sort != "" {
+		var ok bool
+		if less, ok = transactionSortFields[strings.TrimPrefix(query.Sort, "-")]; !ok {
+			return nil, &ListQueryError{Message: "Unknown sort field " + query.Sort}
+		}
+		descending = strings.HasPrefix(query.Sort, "-")
+	}
+
+	transactions, err := GetTransactions()
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]*models.APaymentTokenTransaction, 0, len(transactions))
+	for _, tx := range transactions {
+		if requestId != 0 && (tx.Request == nil || tx.Request.Id != requestId) {
+			continue
+		}
+		if accountId != 0 && tx.From.Id != accountId && tx.To.Id != accountId {
+			continue
+		}
+		filtered = append(filtered, tx)
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		if descending {
+			return less(filtered[j], filtered[i])
+		}
+		return less(filtered[i], filtered[j])
+	})
+
+	offset, limit := pageBounds(query)
+	items := make([]*models.APaymentTokenTransaction, 0)
+	if offset < int64(len(filtered)) {
+		end := offset + limit
+		if end > int64(len(filtered)) {
+			end = int64(len(filtered))
+		}
+		items = filtered[offset:end]
+	}
+	return &Page{Items: items, Total: int64(len(filtered)), Offset: offset, Limit: limit}, nil
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/scmo/apayment-backend/models"
+)
+
+// defaultAppointmentDuration is used when an appointment is proposed without a duration
+const defaultAppointmentDuration = 120
+
+// AppointmentError is returned when an appointment cannot be changed in its status
+type AppointmentError struct {
+	Message string
+}
+
+func (e *AppointmentError) Error() string {
+	return e.Message
+}
+
+// openAppointmentStatuses are the states of an appointment which is still to be held
+var openAppointmentStatuses = []interface{}{models.AppointmentStatusProposed, models.AppointmentStatusConfirmed}
+
+// ProposeAppointment schedules the inspection of a request with its assigned
+// inspector. A request has at most one open appointment.
+func ProposeAppointment(appointment *models.InspectionAppointment) error {
+	if !appointment.Date.After(time.Now()) {
+		return &RequestValidationError{Message: "The date of the appointment must be in the future"}
+	}
+	o := orm.NewOrm()
+	var request models.Request
+	if err := o.QueryTable(new(models.Request)).Filter("Id", appointment.RequestId).RelatedSel().One(&request); err != nil {
+		beego.Error("Error while fetching Request by ID: ", err)
+		return err
+	}
+	if RequestStatus(&request) != models.RequestStatusInspectorAssigned {
+		return &AppointmentError{Message: "Request " + strconv.FormatInt(request.Id, 10) + " has no inspection to schedule"}
+	}
+	if o.QueryTable(new(models.InspectionAppointment)).Filter("Request", request.Id).Filter("Status__in", openAppointmentStatuses...).Exist() {
+		return &AppointmentError{Message: "Request " + strconv.FormatInt(request.Id, 10) + " already has an open appointment"}
+	}
+
+	appointment.Request = &request
+	appointment.Inspector = request.Inspector
+	appointment.Rescheduled = 0
+	if appointment.Duration <= 0 {
+		appointment.Duration = defaultAppointmentDuration
+	}
+	appointment.Status = models.AppointmentStatusProposed
+	if appointment.Unannounced {
+		// the farmer is not asked
+		appointment.Status = models.AppointmentStatusConfirmed
+	}
+	_, err := o.Insert(appointment)
+	return err
+}
+
+// ConfirmAppointment is called by the farmer to accept the proposed date
+func ConfirmAppointment(appointment *models.InspectionAppointment) error {
+	if appointment.Status != models.AppointmentStatusProposed {
+		return &AppointmentError{Message: "Only a proposed appointment can be confirmed"}
+	}
+	return updateAppointment(appointment, orm.Params{"Status": models.AppointmentStatusConfirmed})
+}
+
+// RescheduleAppointment moves an open appointment to another date. An announced
+// appointment has to be confirmed by the farmer again.
+func RescheduleAppointment(appointment *models.InspectionAppointment, date time.Time) error {
+	if appointment.Status != models.AppointmentStatusProposed && appointment.Status != models.AppointmentStatusConfirmed {
+		return &AppointmentError{Message: "Only an open appointment can be rescheduled"}
+	}
+	if !date.After(time.Now()) {
+		return &RequestValidationError{Message: "The date of the appointment must be in the future"}
+	}
+	status := models.AppointmentStatusProposed
+	if appointment.Unannounced {
+		status = models.AppointmentStatusConfirmed
+	}
+	return updateAppointment(appointment, orm.Params{"Status": status, "Date": date, "Rescheduled": appointment.Rescheduled + 1})
+}
+
+// CancelAppointment cancels an open appointment
+func CancelAppointment(appointment *models.InspectionAppointment) error {
+	if appointment.Status != models.AppointmentStatusProposed && appointment.Status != models.AppointmentStatusConfirmed {
+		return &AppointmentError{Message: "Only an open appointment can be cancelled"}
+	}
+	return updateAppointment(appointment, orm.Params{"Status": models.AppointmentStatusCancelled})
+}
+
+// updateAppointment changes the appointment unless it was changed in the meantime
+func updateAppointment(appointment *models.InspectionAppointment, params orm.Params) error {
+	params["Modified"] = time.Now()
+	o := orm.NewOrm()
+	num, err := o.QueryTable(new(models.InspectionAppointment)).Filter("Id", appointment.Id).Filter("Status", appointment.Status).Filter("Rescheduled", appointment.Rescheduled).Update(params)
+	if err != nil {
+		beego.Error("Failed to update appointment ", appointment.Id, ": ", err)
+		return err
+	}
+	if num == 0 {
+		return &AppointmentError{Message: "The appointment was changed in the meantime"}
+	}
+	return o.Read(appointment)
+}
+
+// closeRequestAppointments moves the open appointments of a request to the given
+// state, when it is inspected or its inspection is no longer due
+func closeRequestAppointments(requestId int64, status string) {
+	o := orm.NewOrm()
+	_, err := o.QueryTable(new(models.InspectionAppointment)).Filter("Request", requestId).Filter("Status__in", openAppointmentStatuses...).Update(orm.Params{"Status": status, "Modified": time.Now()})
+	if err != nil {
+		beego.Error("Failed to close appointments of request ", requestId, ": ", err)
+	}
+}
+
+func GetAppointmentById(appointmentId int64) (*models.InspectionAppointment, error) {
+	o := orm.NewOrm()
+	var appointment models.InspectionAppointment
+	if err := o.QueryTable(new(models.InspectionAppointment)).Filter("Id", appointmentId).RelatedSel().One(&appointment); err != nil {
+		return nil, err
+	}
+	appointment.RequestId = appointment.Request.Id
+	return &appointment, nil
+}
+
+// GetRequestAppointments returns the appointments of a request. The farmer does not
+// see unannounced appointments before they are held.
+func GetRequestAppointments(requestId int64, farmer bool) ([]*models.InspectionAppointment, error) {
+	cond := orm.NewCondition().And("Request", requestId)
+	if farmer {
+		cond = cond.AndCond(orm.NewCondition().And("Unannounced", false).Or("Status", models.AppointmentStatusHeld))
+	}
+	o := orm.NewOrm()
+	var appointments []*models.InspectionAppointment
+	if _, err := o.QueryTable(new(models.InspectionAppointment)).SetCond(cond).RelatedSel("Inspector").OrderBy("Date", "Id").All(&appointments); err != nil {
+		return nil, err
+	}
+	for _, appointment := range appointments {
+		appointment.RequestId = requestId
+	}
+	return appointments, nil
+}
+
+// GetInspectorCalendar returns the appointments of an inspector from the start of
+// from to the start of to, without cancelled ones
+func GetInspectorCalendar(inspectorId int64, from time.Time, to time.Time) ([]*models.InspectionAppointment, error) {
+	o := orm.NewOrm()
+	var appointments []*models.InspectionAppointment
+	_, err := o.QueryTable(new(models.InspectionAppointment)).Filter("Inspector", inspectorId).Filter("Date__gte", from).Filter("Date__lt", to).
+		Exclude("Status", models.AppointmentStatusCancelled).RelatedSel().OrderBy("Date", "Id").All(&appointments)
+	if err != nil {
+		return nil, err
+	}
+	for _, appointment := range appointments {
+		appointment.RequestId = appointment.Request.Id
+	}
+	return appointments, nil
+}
+
+// GetUnannouncedShare counts how many of the inspections of requests with a RAUS
+// contribution in the year are unannounced, next to the share the rules require
+func GetUnannouncedShare(year int) (*models.UnannouncedShare, error) {
+	o := orm.NewOrm()
+	qs := o.QueryTable(new(models.InspectionAppointment)).Filter("Request__Year", year).Filter("Request__ContributionCodes__contains", ",5417,").
+		Exclude("Status", models.AppointmentStatusCancelled)
+	share := &models.UnannouncedShare{Year: year, Required: beego.AppConfig.DefaultFloat("unannouncedRausShare", 0.4)}
+	var err error
+	if share.Inspections, err = qs.Count(); err != nil {
+		return nil, err
+	}
+	if share.Unannounced, err = qs.Filter("Unannounced", true).Count(); err != nil {
+		return nil, err
+	}
+	if share.Inspections > 0 {
+		share.Share = float64(share.Unannounced) / float64(share.Inspections)
+	}
+	return share, nil
+}
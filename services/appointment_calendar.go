@@ -0,0 +1,84 @@
+package services
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego"
+	"github.com/scmo/apayment-backend/models"
+)
+
+const iCalendarTime = "20060102T150405Z"
+
+var iCalendarEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// AppointmentsICalendar exports the appointments as an iCalendar (RFC 5545) calendar
+func AppointmentsICalendar(appointments []*models.InspectionAppointment) string {
+	var buf bytes.Buffer
+	writeICalendarLine(&buf, "BEGIN:VCALENDAR")
+	writeICalendarLine(&buf, "VERSION:2.0")
+	writeICalendarLine(&buf, "PRODID:-//aPayment//Inspections//EN")
+	writeICalendarLine(&buf, "CALSCALE:GREGORIAN")
+	for _, appointment := range appointments {
+		summary := "Inspection of request " + strconv.FormatInt(appointment.RequestId, 10)
+		if appointment.Unannounced {
+			summary += " (unannounced)"
+		}
+		status := "CONFIRMED"
+		if appointment.Status == models.AppointmentStatusProposed {
+			status = "TENTATIVE"
+		}
+		end := appointment.Date.Add(time.Duration(appointment.Duration) * time.Minute)
+
+		writeICalendarLine(&buf, "BEGIN:VEVENT")
+		writeICalendarLine(&buf, "UID:appointment-"+strconv.FormatInt(appointment.Id, 10)+"@"+beego.AppConfig.DefaultString("appname", "apayment-backend"))
+		writeICalendarLine(&buf, "DTSTAMP:"+appointment.Modified.UTC().Format(iCalendarTime))
+		writeICalendarLine(&buf, "DTSTART:"+appointment.Date.UTC().Format(iCalendarTime))
+		writeICalendarLine(&buf, "DTEND:"+end.UTC().Format(iCalendarTime))
+		writeICalendarLine(&buf, "SEQUENCE:"+strconv.Itoa(appointment.Rescheduled))
+		writeICalendarLine(&buf, "STATUS:"+status)
+		writeICalendarLine(&buf, "SUMMARY:"+iCalendarEscaper.Replace(summary))
+		if location := appointmentLocation(appointment); location != "" {
+			writeICalendarLine(&buf, "LOCATION:"+iCalendarEscaper.Replace(location))
+		}
+		if appointment.Remark != "" {
+			writeICalendarLine(&buf, "DESCRIPTION:"+iCalendarEscaper.Replace(appointment.Remark))
+		}
+		writeICalendarLine(&buf, "END:VEVENT")
+	}
+	writeICalendarLine(&buf, "END:VCALENDAR")
+	return buf.String()
+}
+
+// appointmentLocation names the farm of the appointment by its plant
+func appointmentLocation(appointment *models.InspectionAppointment) string {
+	if appointment.Request == nil {
+		return ""
+	}
+	plant := farmPlant(appointment.Request.User)
+	if plant == nil {
+		return ""
+	}
+	return strings.TrimSpace(plant.Name + " " + plant.Community)
+}
+
+// writeICalendarLine ends the line with CRLF and folds it after 75 octets, without
+// splitting a UTF-8 character
+func writeICalendarLine(buf *bytes.Buffer, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		// the leading space of a continuation line counts
+		limit = 74
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
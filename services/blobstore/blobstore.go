@@ -0,0 +1,42 @@
+// Package blobstore keeps files outside of the database. The store is chosen by name,
+// further stores are added with Register.
+package blobstore
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+// Store keeps files by key
+type Store interface {
+	Put(key string, content io.Reader) error
+	Get(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
+
+// Factory opens a store at the configured location
+type Factory func(location string) (Store, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]Factory{"local": NewLocalStore}
+)
+
+// Register makes a store available by name
+func Register(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	factories[name] = factory
+}
+
+// Open opens the store registered by the name
+func Open(name string, location string) (Store, error) {
+	factoriesMu.RLock()
+	factory, ok := factories[name]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, errors.New("Unknown blob store " + name)
+	}
+	return factory(location)
+}
@@ -0,0 +1,76 @@
+package blobstore
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// localStore keeps the files in a directory of the local filesystem
+type localStore struct {
+	root string
+}
+
+// NewLocalStore opens a store in the directory, it is created if missing
+func NewLocalStore(location string) (Store, error) {
+	if err := os.MkdirAll(location, 0750); err != nil {
+		return nil, err
+	}
+	return &localStore{root: location}, nil
+}
+
+// path spreads the files over subdirectories named by the first characters of the key
+func (s *localStore) path(key string) (string, error) {
+	if len(key) < 3 || strings.ContainsAny(key, `/\.`) {
+		return "", errors.New("Invalid blob key " + key)
+	}
+	return filepath.Join(s.root, key[:2], key), nil
+}
+
+func (s *localStore) Put(key string, content io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	// write to a temporary file first, so that a file is never read half written
+	tmp, err := ioutil.TempFile(filepath.Dir(path), key+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, content); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *localStore) Get(key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+func (s *localStore) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
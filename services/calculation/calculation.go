@@ -0,0 +1,220 @@
+// Package calculation computes the contributions of a request like the Request contract
+// does, without a blockchain. It follows the integer arithmetic of request.sol, including
+// the overflows of its unsigned types, so that a preview gives the amounts the contract
+// pays out.
+package calculation
+
+import (
+	"math/big"
+)
+
+const (
+	ContributionBTS  = 5416
+	ContributionRAUS = 5417
+)
+
+// PointGroupCodes in the order of the contract
+var PointGroupCodes = []uint16{1110, 1150, 1128, 1141, 1142, 1124, 1129, 1143, 1144}
+
+var (
+	uint256Modulus = new(big.Int).Lsh(big.NewInt(1), 256)
+	gveScale       = big.NewInt(10000) // GVE values are multiplied by 10'000 for 4 decimal places
+	roundingStep   = big.NewInt(5000)
+)
+
+// Lack is a lack as added to the contract
+type Lack struct {
+	ContributionCode uint16
+	PointGroupCode   uint16
+	Points           uint8
+}
+
+// Input holds what the contract is deployed with and the lacks added to it
+type Input struct {
+	GVE                map[uint16]uint32 // by point group code, multiplied by 10'000
+	AmountPreviousYear *big.Int
+	Lacks              []Lack
+}
+
+// PointGroup is the calculation of a point group, the amounts are multiplied by 10'000
+// like in the contract
+type PointGroup struct {
+	PointGroupCode uint16   `json:"pointGroupCode"`
+	GVE            uint32   `json:"gve"`
+	BtsPoints      uint16   `json:"btsPoints"`
+	BtsTotal       *big.Int `json:"btsTotal"`
+	BtsDeduction   *big.Int `json:"btsDeduction"`
+	RausPoints     uint16   `json:"rausPoints"`
+	RausTotal      *big.Int `json:"rausTotal"`
+	RausDeduction  *big.Int `json:"rausDeduction"`
+}
+
+// Result holds the point groups in the order of the contract and the payments
+type Result struct {
+	PointGroups  []*PointGroup `json:"pointGroups"`
+	FirstPayment *big.Int      `json:"firstPayment"`
+	FinalPayment *big.Int      `json:"finalPayment"`
+}
+
+// Calculate adds the lacks to the point groups and calculates BTS, RAUS and the payments
+// as the contract does after addLacks. Lacks of other contributions are stored by the
+// contract but do not count.
+func Calculate(input *Input) *Result {
+	pointGroups := make(map[uint16]*PointGroup)
+	result := &Result{}
+	for _, code := range PointGroupCodes {
+		pointGroup := &PointGroup{PointGroupCode: code, GVE: input.GVE[code], BtsTotal: new(big.Int), BtsDeduction: new(big.Int), RausTotal: new(big.Int), RausDeduction: new(big.Int)}
+		pointGroups[code] = pointGroup
+		result.PointGroups = append(result.PointGroups, pointGroup)
+	}
+	for _, lack := range input.Lacks {
+		pointGroup := pointGroups[lack.PointGroupCode]
+		if pointGroup == nil {
+			// the contract keeps the points in a point group nobody reads
+			continue
+		}
+		switch lack.ContributionCode {
+		case ContributionBTS:
+			pointGroup.BtsPoints += uint16(lack.Points)
+		case ContributionRAUS:
+			pointGroup.RausPoints += uint16(lack.Points)
+		}
+	}
+	for _, pointGroup := range result.PointGroups {
+		calculateBTS(pointGroup)
+		calculateRAUS(pointGroup)
+	}
+	result.FirstPayment = FirstPayment(input.AmountPreviousYear)
+	result.FinalPayment = finalPayment(result.PointGroups, result.FirstPayment)
+	return result
+}
+
+// FirstPayment is half the amount of the previous year
+func FirstPayment(amountPreviousYear *big.Int) *big.Int {
+	if amountPreviousYear == nil || amountPreviousYear.Sign() <= 0 {
+		return new(big.Int)
+	}
+	return new(big.Int).Quo(amountPreviousYear, big.NewInt(2))
+}
+
+// calculateBTS mirrors calculateBTS of the contract
+func calculateBTS(pointGroup *PointGroup) {
+	if !hasBTS(pointGroup.PointGroupCode) {
+		return
+	}
+	pointGroup.BtsTotal = new(big.Int).Mul(big.NewInt(int64(pointGroup.GVE)), big.NewInt(9000))
+	pointGroup.BtsDeduction = deduction(pointGroup.BtsPoints, pointGroup.BtsTotal, 9000)
+}
+
+// calculateRAUS mirrors calculateRAUS of the contract
+func calculateRAUS(pointGroup *PointGroup) {
+	multiplier := int64(19000)
+	if pointGroup.PointGroupCode == 1142 || pointGroup.PointGroupCode == 1144 {
+		multiplier = 37000
+	}
+	pointGroup.RausTotal = new(big.Int).Mul(big.NewInt(int64(pointGroup.GVE)), big.NewInt(multiplier))
+	pointGroup.RausDeduction = deduction(pointGroup.RausPoints, pointGroup.RausTotal, multiplier)
+}
+
+// deduction of the points, more than 110 points take the whole amount. The contract
+// subtracts 10 points as uint16, fewer points wrap around.
+func deduction(points uint16, total *big.Int, multiplier int64) *big.Int {
+	switch {
+	case points == 0:
+		return new(big.Int)
+	case points > 110:
+		return new(big.Int).Set(total)
+	}
+	deduction := new(big.Int).Mul(big.NewInt(int64(points-10)), big.NewInt(multiplier/100))
+	return deduction.Mul(deduction, gveScale)
+}
+
+// Entitlement is the amount of the contributions after the deductions, which the tranches
+// of a request pay out together. The contract splits it into the first and the final payment.
+func Entitlement(finalPayment *big.Int, firstPayment *big.Int) *big.Int {
+	amount := new(big.Int).Add(finalPayment, firstPayment)
+	return amount.Mod(amount, uint256Modulus)
+}
+
+// PreviousYearTranche is a percentage of the amount of the previous year
+func PreviousYearTranche(percentage int, amountPreviousYear *big.Int) *big.Int {
+	amount := new(big.Int).Mul(amountPreviousYear, big.NewInt(int64(percentage)))
+	return amount.Quo(amount, big.NewInt(100))
+}
+
+// EntitlementTranche pays up to a percentage of the entitlement, less what was paid before.
+// Nothing is paid back when more was paid before.
+func EntitlementTranche(percentage int, entitlement *big.Int, paid *big.Int) *big.Int {
+	amount := new(big.Int).Mul(entitlement, big.NewInt(int64(percentage)))
+	amount.Quo(amount, big.NewInt(100)).Sub(amount, paid)
+	if amount.Sign() < 0 {
+		return new(big.Int)
+	}
+	return amount
+}
+
+// Underflow is an operation of the contract which wraps around. The point group code
+// is 0 for the final payment.
+type Underflow struct {
+	PointGroupCode uint16
+	Field          string
+	Message        string
+}
+
+// Underflows lists the operations of the contract which wrap around in a result. Their
+// amounts are what the contract pays, but not what the farmer is entitled to.
+func Underflows(result *Result) []Underflow {
+	var underflows []Underflow
+	for _, pointGroup := range result.PointGroups {
+		if hasBTS(pointGroup.PointGroupCode) {
+			underflows = appendUnderflows(underflows, pointGroup.PointGroupCode, "bts", pointGroup.BtsPoints, pointGroup.BtsTotal, pointGroup.BtsDeduction)
+		}
+		underflows = appendUnderflows(underflows, pointGroup.PointGroupCode, "raus", pointGroup.RausPoints, pointGroup.RausTotal, pointGroup.RausDeduction)
+	}
+	if payable(result.PointGroups).Cmp(result.FirstPayment) < 0 {
+		underflows = append(underflows, Underflow{Field: "finalPayment", Message: "the first payment exceeds the amount of the contributions"})
+	}
+	return underflows
+}
+
+func appendUnderflows(underflows []Underflow, pointGroupCode uint16, contribution string, points uint16, total *big.Int, deduction *big.Int) []Underflow {
+	switch {
+	case points > 0 && points < 10:
+		return append(underflows, Underflow{PointGroupCode: pointGroupCode, Field: contribution + "Deduction", Message: contribution + "Points - 10 wraps around with fewer than 10 points"})
+	case deduction.Cmp(total) > 0:
+		return append(underflows, Underflow{PointGroupCode: pointGroupCode, Field: contribution + "Deduction", Message: "the deduction exceeds " + contribution + "Total"})
+	}
+	return underflows
+}
+
+// there is no BTS for the calves
+func hasBTS(pointGroupCode uint16) bool {
+	return pointGroupCode != 1142 && pointGroupCode != 1144
+}
+
+// finalPayment mirrors getFinalPaymentAmount of the contract, its subtractions are
+// calculated modulo 2^256
+func finalPayment(pointGroups []*PointGroup, firstPayment *big.Int) *big.Int {
+	return uint256Sub(payable(pointGroups), firstPayment)
+}
+
+// payable is the amount of the contributions after the deductions, before the first payment
+func payable(pointGroups []*PointGroup) *big.Int {
+	amount := new(big.Int)
+	for _, pointGroup := range pointGroups {
+		amount.Add(amount, round(uint256Sub(pointGroup.BtsTotal, pointGroup.BtsDeduction)))
+		amount.Add(amount, round(uint256Sub(pointGroup.RausTotal, pointGroup.RausDeduction)))
+		amount.Mod(amount, uint256Modulus)
+	}
+	return amount.Quo(amount, gveScale)
+}
+
+func round(amount *big.Int) *big.Int {
+	rounded := new(big.Int).Quo(amount, roundingStep)
+	return rounded.Mul(rounded, roundingStep)
+}
+
+func uint256Sub(a *big.Int, b *big.Int) *big.Int {
+	difference := new(big.Int).Sub(a, b)
+	return difference.Mod(difference, uint256Modulus)
+}
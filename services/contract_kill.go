@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/scmo/apayment-backend/ethereum"
+	"github.com/scmo/apayment-backend/models"
+)
+
+// scheduleContractKill stores the kill of a retired request contract and sends it. A kill
+// which cannot be sent is logged and tried again by RetryContractKills.
+func scheduleContractKill(address string, request *models.Request, actor string) {
+	kill := &models.ContractKill{Request: request, Address: address, Actor: actor, Status: models.ContractKillStatusPending}
+	o := orm.NewOrm()
+	if _, err := o.Insert(kill); err != nil {
+		beego.Error("Failed to store kill of contract ", address, " of request ", request.Id, ": ", err)
+		return
+	}
+	sendContractKill(kill)
+}
+
+// sendContractKill sends a pending kill signed by its actor
+func sendContractKill(kill *models.ContractKill) {
+	kill.Attempts++
+	var transaction *models.EthereumTransaction
+	auth, err := ethereum.GetAuth(kill.Actor)
+	if err == nil {
+		transaction, err = killRequestContract(kill.Address, kill.Request, auth)
+	}
+	if err != nil {
+		beego.Error("Failed to kill contract ", kill.Address, " of request ", kill.Request.Id, " (attempt ", kill.Attempts, "): ", err)
+		kill.Error = err.Error()
+		if kill.Attempts >= beego.AppConfig.DefaultInt("contractKillAttempts", 5) {
+			kill.Status = models.ContractKillStatusFailed
+		}
+	} else {
+		kill.Status = models.ContractKillStatusSent
+		kill.Error = ""
+		if transaction.Id != 0 {
+			kill.Transaction = transaction
+		}
+	}
+	o := orm.NewOrm()
+	if _, err := o.Update(kill, "Attempts", "Status", "Error", "Transaction", "Updated"); err != nil {
+		beego.Error("Failed to update kill of contract ", kill.Address, ": ", err)
+	}
+}
+
+// RetryContractKills sends the kills which could not be sent or whose transaction failed
+func RetryContractKills() error {
+	o := orm.NewOrm()
+	var kills []*models.ContractKill
+	_, err := o.QueryTable(new(models.ContractKill)).Filter("Status", models.ContractKillStatusPending).RelatedSel("Request").All(&kills)
+	if err != nil {
+		beego.Error("Failed to load pending contract kills: ", err)
+		return err
+	}
+	for _, kill := range kills {
+		sendContractKill(kill)
+	}
+	return nil
+}
+
+// followContractKill sends the kill of a failed kill transaction again. A mined kill which
+// left the code of the contract is failed: contracts deployed before admins and canton
+// employees could kill them ignore their kill, the farmer has to kill them.
+func followContractKill(transaction *models.EthereumTransaction) {
+	o := orm.NewOrm()
+	kill := models.ContractKill{Transaction: transaction}
+	if err := o.Read(&kill, "Transaction"); err != nil {
+		if err != orm.ErrNoRows {
+			beego.Error("Failed to load kill of transaction ", transaction.Hash, ": ", err)
+		}
+		return
+	}
+	switch transaction.Status {
+	case models.TransactionStatusFailed:
+		kill.Status = models.ContractKillStatusPending
+		kill.Error = "Transaction " + transaction.Hash + " failed"
+	case models.TransactionStatusMined:
+		code, err := ethereum.GetEthereumController().Client.CodeAt(context.Background(), common.HexToAddress(kill.Address), nil)
+		if err != nil {
+			beego.Error("Failed to read code of contract ", kill.Address, ": ", err)
+			return
+		}
+		if len(code) == 0 {
+			return
+		}
+		beego.Error("Contract ", kill.Address, " was not killed by ", kill.Actor, ", its farmer has to kill it")
+		kill.Status = models.ContractKillStatusFailed
+		kill.Error = "The contract ignored the kill of " + kill.Actor + ", its farmer has to kill it"
+	default:
+		return
+	}
+	if _, err := o.Update(&kill, "Status", "Error", "Updated"); err != nil {
+		beego.Error("Failed to update kill of contract ", kill.Address, ": ", err)
+	}
+}
@@ -1,6 +1,10 @@
 package services
 
 import (
+	"errors"
+	"strconv"
+	"strings"
+
 	"github.com/astaxie/beego"
 	"github.com/astaxie/beego/orm"
 	"github.com/scmo/apayment-backend/models"
@@ -12,14 +16,19 @@ func CreateContribution(c *models.Contribution) error {
 	return err
 }
 
-func GetAllContributions() []*models.Contribution {
+var contributionSortFields = listFields{"id": "Id", "code": "Code", "name": "Name"}
+
+func GetAllContributions(query *ListQuery) (*Page, error) {
 	o := orm.NewOrm()
 	var contributions []*models.Contribution
-	o.QueryTable(new(models.Contribution)).All(&contributions)
+	page, err := paginate(o.QueryTable(new(models.Contribution)), query, map[string]listFilter{"code": filterInt("Code")}, contributionSortFields, &contributions)
+	if err != nil {
+		return nil, err
+	}
 	for _, contribution := range contributions {
 		o.LoadRelated(contribution, "ControlCategories")
 	}
-	return contributions
+	return page, nil
 }
 
 func GetContributionById(_id int64) (*models.Contribution, error) {
@@ -61,6 +70,61 @@ func GetContributionByCode(_code uint16) (*models.Contribution, error) {
 	return &contribution, nil
 }
 
+// GetContributionByCodeForYear loads a contribution with the catalog version that applies to
+// the request year. Without a year every version is loaded.
+func GetContributionByCodeForYear(_code uint16, year int) (*models.Contribution, error) {
+	if year == 0 {
+		return GetContributionByCode(_code)
+	}
+	o := orm.NewOrm()
+	contribution := models.Contribution{Code: _code}
+	err := o.Read(&contribution, "Code")
+	if err != nil {
+		beego.Error("No result found.")
+		return nil, err
+	}
+	version, err := catalogVersion(contribution.Id, year)
+	if err != nil {
+		return nil, err
+	}
+	o.QueryTable(new(models.ControlCategory)).Filter("Contribution", contribution.Id).Filter("ControlCategoryId__endswith", "_"+strconv.Itoa(version)).All(&contribution.ControlCategories)
+	for _, controlCategory := range contribution.ControlCategories {
+		o.LoadRelated(controlCategory, "PointGroups")
+		for _, pointgroup := range controlCategory.PointGroups {
+			o.LoadRelated(pointgroup, "ControlPoints")
+			for _, controlPoint := range pointgroup.ControlPoints {
+				o.LoadRelated(controlPoint, "Lacks")
+			}
+		}
+	}
+	return &contribution, nil
+}
+
+// catalogVersion returns the latest catalog version of a contribution published up to
+// the given year. Control category ids carry their version, e.g. "12.01_2017".
+func catalogVersion(contributionId int64, year int) (int, error) {
+	o := orm.NewOrm()
+	var controlCategories []*models.ControlCategory
+	if _, err := o.QueryTable(new(models.ControlCategory)).Filter("Contribution", contributionId).All(&controlCategories, "ControlCategoryId"); err != nil {
+		return 0, err
+	}
+	version := 0
+	for _, controlCategory := range controlCategories {
+		i := strings.LastIndex(controlCategory.ControlCategoryId, "_")
+		v, err := strconv.Atoi(controlCategory.ControlCategoryId[i+1:])
+		if i < 0 || err != nil {
+			continue
+		}
+		if v <= year && v > version {
+			version = v
+		}
+	}
+	if version == 0 {
+		return 0, errors.New("No catalog published for " + strconv.Itoa(year))
+	}
+	return version, nil
+}
+
 func CountContributions() (int64, error) {
 	o := orm.NewOrm()
 	cnt, err := o.QueryTable(new(models.Contribution)).Count() // SELECT COUNT(*) FROM USE
@@ -12,10 +12,15 @@ func CreateControlCategory(cc *models.ControlCategory) error {
 	return err
 }
 
-func GetAllControlCategories() []*models.ControlCategory {
+var controlCategorySortFields = listFields{"id": "Id", "controlCategoryId": "ControlCategoryId"}
+
+func GetAllControlCategories(query *ListQuery) (*Page, error) {
 	o := orm.NewOrm()
 	var controlCategories []*models.ControlCategory
-	o.QueryTable(new(models.ControlCategory)).All(&controlCategories)
+	page, err := paginate(o.QueryTable(new(models.ControlCategory)), query, map[string]listFilter{"contribution": filterInt("Contribution")}, controlCategorySortFields, &controlCategories)
+	if err != nil {
+		return nil, err
+	}
 	for _, controlCategory := range controlCategories {
 		o.LoadRelated(controlCategory, "PointGroups")
 		for _, pointGroup := range controlCategory.PointGroups {
@@ -24,7 +29,7 @@ func GetAllControlCategories() []*models.ControlCategory {
 		}
 	}
 
-	return controlCategories
+	return page, nil
 }
 
 func CountControlCategories() (int64, error) {
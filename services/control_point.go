@@ -11,14 +11,12 @@ func CreateControlPoint(cp *models.ControlPoint) error {
 	return err
 }
 
-func GetAllControlPoints() []*models.ControlPoint {
+var controlPointSortFields = listFields{"id": "Id", "controlPointId": "ControlPointId"}
+
+func GetAllControlPoints(query *ListQuery) (*Page, error) {
 	o := orm.NewOrm()
 	var controlPoints []*models.ControlPoint
-	o.QueryTable(new(models.ControlPoint)).All(&controlPoints)
-	//for _, contribution := range contributions {
-	//	o.LoadRelated(contribution, "InspectionCriteria")
-	//}
-	return controlPoints
+	return paginate(o.QueryTable(new(models.ControlPoint)), query, map[string]listFilter{"pointGroup": filterInt("PointGroup")}, controlPointSortFields, &controlPoints)
 }
 
 func CountControlPoints() (int64, error) {
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/scmo/apayment-backend/models"
+)
+
+// EventSubscription receives the events the subscribed user may see
+type EventSubscription struct {
+	User   *models.User
+	Events chan *models.Event
+}
+
+var eventSubscriptions = struct {
+	sync.Mutex
+	subscriptions map[*EventSubscription]bool
+}{subscriptions: make(map[*EventSubscription]bool)}
+
+var eventTypes = map[string]string{
+	models.TransactionKindRole:         models.EventTypeRoleChanged,
+	models.TransactionKindSetInspector: models.EventTypeInspectorAssigned,
+	models.TransactionKindAddLacks:     models.EventTypeLacksAdded,
+	models.TransactionKindTransfer:     models.EventTypePaymentReceived,
+}
+
+var eventTickets = struct {
+	sync.Mutex
+	tickets map[string]*eventTicket
+}{tickets: make(map[string]*eventTicket)}
+
+type eventTicket struct {
+	username string
+	expires  time.Time
+}
+
+// IssueEventTicket creates a ticket to open the event stream of the user once within 'eventTicketTTL' seconds
+func IssueEventTicket(user *models.User) (*models.EventTicket, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		beego.Error("Failed to create event ticket: ", err)
+		return nil, err
+	}
+	ticket := &models.EventTicket{
+		Ticket:  hex.EncodeToString(random),
+		Expires: time.Now().Add(time.Duration(beego.AppConfig.DefaultInt("eventTicketTTL", 30)) * time.Second),
+	}
+	eventTickets.Lock()
+	defer eventTickets.Unlock()
+	for key, stored := range eventTickets.tickets {
+		if time.Now().After(stored.expires) {
+			delete(eventTickets.tickets, key)
+		}
+	}
+	eventTickets.tickets[ticket.Ticket] = &eventTicket{username: user.Username, expires: ticket.Expires}
+	return ticket, nil
+}
+
+// RedeemEventTicket returns the username a ticket was issued for. A ticket is valid once.
+func RedeemEventTicket(ticket string) (string, error) {
+	eventTickets.Lock()
+	defer eventTickets.Unlock()
+	stored, ok := eventTickets.tickets[ticket]
+	if !ok {
+		return "", errors.New("Unknown event ticket")
+	}
+	delete(eventTickets.tickets, ticket)
+	if time.Now().After(stored.expires) {
+		return "", errors.New("Event ticket expired")
+	}
+	return stored.username, nil
+}
+
+func SubscribeEvents(user *models.User) *EventSubscription {
+	subscription := &EventSubscription{User: user, Events: make(chan *models.Event, 16)}
+	eventSubscriptions.Lock()
+	eventSubscriptions.subscriptions[subscription] = true
+	eventSubscriptions.Unlock()
+	return subscription
+}
+
+func UnsubscribeEvents(subscription *EventSubscription) {
+	eventSubscriptions.Lock()
+	delete(eventSubscriptions.subscriptions, subscription)
+	eventSubscriptions.Unlock()
+}
+
+// PublishEvent sends the event to every subscriber allowed to see it. Slow
+// subscribers miss events instead of blocking the publisher.
+func PublishEvent(event *models.Event) {
+	eventSubscriptions.Lock()
+	defer eventSubscriptions.Unlock()
+	for subscription := range eventSubscriptions.subscriptions {
+		if !canSeeEvent(subscription.User, event) {
+			continue
+		}
+		select {
+		case subscription.Events <- event:
+		default:
+			beego.Warning("Event dropped for user: ", subscription.User.Username)
+		}
+	}
+}
+
+func canSeeEvent(user *models.User, event *models.Event) bool {
+	if user.HasRole("Admin") || user.HasRole("Canton") {
+		return true
+	}
+	if event.Account != "" && event.Account == user.EtherumAddress {
+		return true
+	}
+	return event.RequestId != 0 && (event.FarmerId == user.Id || event.InspectorId == user.Id)
+}
+
+// publishTransactionEvent turns a confirmed transaction into an event
+func publishTransactionEvent(transaction *models.EthereumTransaction) {
+	eventType, ok := eventTypes[transaction.Kind]
+	if !ok {
+		return
+	}
+	// only transfers belonging to a request are payments
+	if transaction.Kind == models.TransactionKindTransfer && transaction.Request == nil {
+		return
+	}
+	event := &models.Event{
+		Id:              transaction.Id,
+		Type:            eventType,
+		Account:         transaction.Account,
+		Detail:          transaction.Detail,
+		Amount:          transaction.Amount,
+		TransactionHash: transaction.Hash,
+		Created:         time.Now(),
+	}
+	if transaction.Request != nil {
+		request := models.Request{Id: transaction.Request.Id}
+		o := orm.NewOrm()
+		if err := o.Read(&request); err != nil {
+			beego.Error("Failed to load request of event: ", err)
+			return
+		}
+		event.RequestId = request.Id
+		event.FarmerId = request.User.Id
+		if request.Inspector != nil {
+			event.InspectorId = request.Inspector.Id
+		}
+	}
+	PublishEvent(event)
+}
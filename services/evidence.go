@@ -0,0 +1,239 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/scmo/apayment-backend/ethereum"
+	"github.com/scmo/apayment-backend/models"
+	"github.com/scmo/apayment-backend/services/blobstore"
+)
+
+// evidenceContentTypes are the files accepted as evidence, photos and PDFs
+var evidenceContentTypes = map[string]bool{"image/jpeg": true, "image/png": true, "application/pdf": true}
+
+var (
+	evidenceStoreOnce sync.Once
+	evidenceStore     blobstore.Store
+	evidenceStoreErr  error
+)
+
+// getEvidenceStore opens the blob store configured for the evidence files
+func getEvidenceStore() (blobstore.Store, error) {
+	evidenceStoreOnce.Do(func() {
+		evidenceStore, evidenceStoreErr = blobstore.Open(beego.AppConfig.DefaultString("evidenceStore", "local"), beego.AppConfig.DefaultString("evidenceStoreLocation", "evidence"))
+		if evidenceStoreErr != nil {
+			beego.Critical("Failed to open evidence store: ", evidenceStoreErr)
+		}
+	})
+	return evidenceStore, evidenceStoreErr
+}
+
+// AddEvidence stores a file for a lack of the inspection of a request. Evidence can
+// only be added until the lacks of the request are added.
+func AddEvidence(evidence *models.Evidence, content io.Reader) error {
+	o := orm.NewOrm()
+	request := models.Request{Id: evidence.RequestId}
+	if err := o.Read(&request); err != nil {
+		beego.Error("Error while fetching Request by ID: ", err)
+		return err
+	}
+	if err := CheckRequestTransition(&request, models.RequestStatusInspected); err != nil {
+		return err
+	}
+	lack := models.Lack{Id: evidence.LackId}
+	if err := o.Read(&lack); err != nil || lack.ControlPoint == nil || lack.ControlPoint.Id != evidence.ControlPointId {
+		return &RequestValidationError{Message: "Lack " + strconv.FormatInt(evidence.LackId, 10) + " is not part of control point " + strconv.FormatInt(evidence.ControlPointId, 10)}
+	}
+
+	hash, contentType, size, err := putEvidenceFile(content)
+	if err != nil {
+		return err
+	}
+	evidence.Request = &request
+	evidence.Inspector = request.Inspector
+	evidence.Hash = hash
+	evidence.ContentType = contentType
+	evidence.Size = size
+	evidence.Anchor = nil
+	_, err = o.Insert(evidence)
+	return err
+}
+
+// putEvidenceFile checks the size and type of a file and keeps it in the evidence
+// store under its SHA-256 hash
+func putEvidenceFile(content io.Reader) (string, string, int64, error) {
+	maxSize := beego.AppConfig.DefaultInt64("evidenceMaxSize", 10<<20)
+	data, err := ioutil.ReadAll(io.LimitReader(content, maxSize+1))
+	if err != nil {
+		return "", "", 0, err
+	}
+	if int64(len(data)) > maxSize {
+		return "", "", 0, &RequestValidationError{Message: "Evidence is larger than " + strconv.FormatInt(maxSize, 10) + " bytes"}
+	}
+	contentType := http.DetectContentType(data)
+	if !evidenceContentTypes[contentType] {
+		return "", "", 0, &RequestValidationError{Message: "Evidence of type " + contentType + " is not accepted"}
+	}
+	sum := sha256.Sum256(data)
+
+	store, err := getEvidenceStore()
+	if err != nil {
+		return "", "", 0, err
+	}
+	hash := hex.EncodeToString(sum[:])
+	if err := store.Put(hash, bytes.NewReader(data)); err != nil {
+		beego.Error("Failed to store evidence: ", err)
+		return "", "", 0, err
+	}
+	return hash, contentType, int64(len(data)), nil
+}
+
+// DeleteEvidence removes evidence which is not anchored yet
+func DeleteEvidence(evidence *models.Evidence) error {
+	if evidence.Anchor != nil {
+		return &RequestValidationError{Message: "Anchored evidence cannot be deleted"}
+	}
+	o := orm.NewOrm()
+	if _, err := o.QueryTable(new(models.Evidence)).Filter("Id", evidence.Id).Filter("Anchor__isnull", true).Delete(); err != nil {
+		return err
+	}
+	// the same file may be evidence of another lack or attached to an objection
+	if o.QueryTable(new(models.Evidence)).Filter("Hash", evidence.Hash).Exist() || o.QueryTable(new(models.ObjectionAttachment)).Filter("Hash", evidence.Hash).Exist() {
+		return nil
+	}
+	store, err := getEvidenceStore()
+	if err != nil {
+		return err
+	}
+	return store.Delete(evidence.Hash)
+}
+
+func GetEvidenceById(evidenceId int64) (*models.Evidence, error) {
+	o := orm.NewOrm()
+	var evidence models.Evidence
+	if err := o.QueryTable(new(models.Evidence)).Filter("Id", evidenceId).RelatedSel("Request", "Anchor").One(&evidence); err != nil {
+		return nil, err
+	}
+	evidence.RequestId = evidence.Request.Id
+	return &evidence, nil
+}
+
+// ReadEvidenceFile loads the file of the evidence and checks it against its hash
+func ReadEvidenceFile(evidence *models.Evidence) ([]byte, error) {
+	return readEvidenceFile(evidence.Hash)
+}
+
+func readEvidenceFile(hash string) ([]byte, error) {
+	store, err := getEvidenceStore()
+	if err != nil {
+		return nil, err
+	}
+	file, err := store.Get(hash)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != hash {
+		beego.Critical("Evidence file ", hash, " does not match its hash")
+		return nil, errors.New("Evidence file " + hash + " does not match its hash")
+	}
+	return data, nil
+}
+
+// GetEvidenceRecord returns the evidence of a request and the root hash of it
+func GetEvidenceRecord(requestId int64) (*models.EvidenceRecord, error) {
+	evidence, err := loadRequestEvidence(requestId)
+	if err != nil {
+		return nil, err
+	}
+	return &models.EvidenceRecord{Evidence: evidence, Root: evidenceRoot(evidence)}, nil
+}
+
+// AnchorEvidence records the root hash of the evidence of a request on chain, with a
+// transaction of the inspector to itself whose data is the request address followed
+// by the root hash. It is sent when evidence is not anchored yet.
+func AnchorEvidence(request *models.Request, auth *bind.TransactOpts) error {
+	evidence, err := loadRequestEvidence(request.Id)
+	if err != nil {
+		return err
+	}
+	pending := make([]interface{}, 0)
+	for _, e := range evidence {
+		if e.Anchor == nil {
+			pending = append(pending, e.Id)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	root := evidenceRoot(evidence)
+	rootBytes, _ := hex.DecodeString(root)
+	data := append(common.HexToAddress(request.Address).Bytes(), rootBytes...)
+
+	tx, err := ethereum.SendData(auth.From.String(), auth.From.String(), data)
+	if err != nil {
+		beego.Error("Failed to anchor evidence of request ", request.Id, ": ", err)
+		return err
+	}
+	transaction := &models.EthereumTransaction{Kind: models.TransactionKindEvidence, From: auth.From.String(), Request: request, Detail: root}
+	if err := TrackTransaction(tx, transaction); err != nil {
+		return err
+	}
+	o := orm.NewOrm()
+	_, err = o.QueryTable(new(models.Evidence)).Filter("Id__in", pending...).Update(orm.Params{"Anchor": transaction.Id})
+	return err
+}
+
+func loadRequestEvidence(requestId int64) ([]*models.Evidence, error) {
+	o := orm.NewOrm()
+	evidence := make([]*models.Evidence, 0)
+	if _, err := o.QueryTable(new(models.Evidence)).Filter("Request", requestId).RelatedSel("Anchor").OrderBy("Id").All(&evidence); err != nil {
+		beego.Error("Failed to load evidence of request ", requestId, ": ", err)
+		return nil, err
+	}
+	for _, e := range evidence {
+		e.RequestId = requestId
+	}
+	return evidence, nil
+}
+
+// evidenceRoot hashes the evidence of a request so that anybody can recompute it: the
+// SHA-256 of the lines "contributionCode/controlCategoryId/pointGroupCode/controlPointId/lackId:hash\n",
+// sorted. It is empty without evidence.
+func evidenceRoot(evidence []*models.Evidence) string {
+	if len(evidence) == 0 {
+		return ""
+	}
+	lines := make([]string, len(evidence))
+	for i, e := range evidence {
+		lines[i] = evidenceLackKey(e.ContributionCode, e.ControlCategoryId, e.PointGroupCode, e.ControlPointId, e.LackId) + ":" + e.Hash + "\n"
+	}
+	sort.Strings(lines)
+	hash := sha256.New()
+	for _, line := range lines {
+		io.WriteString(hash, line)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func evidenceLackKey(contributionCode uint16, controlCategoryId int64, pointGroupCode uint16, controlPointId int64, lackId int64) string {
+	return strconv.Itoa(int(contributionCode)) + "/" + strconv.FormatInt(controlCategoryId, 10) + "/" + strconv.Itoa(int(pointGroupCode)) + "/" +
+		strconv.FormatInt(controlPointId, 10) + "/" + strconv.FormatInt(lackId, 10)
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/scmo/apayment-backend/ethereum"
+	"github.com/scmo/apayment-backend/models"
+)
+
+// TopUpUserAccounts sends ether from the system account to every user account
+// whose balance dropped below 'gasTopUpThreshold'. A user gets at most
+// 'gasTopUpDailyCap' ether per day.
+func TopUpUserAccounts() error {
+	threshold := etherToWei(beego.AppConfig.DefaultFloat("gasTopUpThreshold", 0.1))
+	amount := etherToWei(beego.AppConfig.DefaultFloat("gasTopUpAmount", 0.5))
+	dailyCap := etherToWei(beego.AppConfig.DefaultFloat("gasTopUpDailyCap", 1))
+
+	o := orm.NewOrm()
+	var users []*models.User
+	_, err := o.QueryTable(new(models.User)).Exclude("EtherumAddress", "").All(&users)
+	if err != nil {
+		beego.Error("Failed to load users: ", err)
+		return err
+	}
+	ethereumController := ethereum.GetEthereumController()
+	oc, _ := time.LoadLocation("Europe/Zurich")
+	now := time.Now().In(oc)
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, oc)
+	for _, user := range users {
+		if user.EtherumAddress == beego.AppConfig.String("systemAccountAddress") {
+			continue
+		}
+		// pending balance includes top-ups which are not mined yet
+		balance, err := ethereumController.Client.PendingBalanceAt(context.Background(), common.HexToAddress(user.EtherumAddress))
+		if err != nil {
+			beego.Error("Failed to get balance of: ", user.EtherumAddress, err)
+			continue
+		}
+		if balance.Cmp(threshold) >= 0 {
+			continue
+		}
+		toppedUp, err := GetTransactionValueSince(models.TransactionKindTopUp, user.EtherumAddress, startOfDay)
+		if err != nil {
+			beego.Error("Failed to get top-ups of: ", user.EtherumAddress, err)
+			continue
+		}
+		if new(big.Int).Add(toppedUp, amount).Cmp(dailyCap) > 0 {
+			beego.Warning("Daily gas top-up cap reached for user: ", user.Username)
+			continue
+		}
+		if err := fundAccount(user.EtherumAddress, amount); err != nil {
+			beego.Error("Failed to top up account of user ", user.Username, " (", user.EtherumAddress, "): ", err)
+		}
+	}
+	return nil
+}
+
+// fundAccount sends ether from the system account and records it as top-up
+func fundAccount(address string, amount *big.Int) error {
+	systemAccountAddress := beego.AppConfig.String("systemAccountAddress")
+	tx, err := ethereum.SendWei(systemAccountAddress, address, amount)
+	if err != nil {
+		beego.Error("Failed to fund account: ", address, err)
+		return err
+	}
+	return TrackTransaction(tx, &models.EthereumTransaction{Kind: models.TransactionKindTopUp, From: systemAccountAddress, Account: address})
+}
+
+func etherToWei(amountEther float64) *big.Int {
+	amount := new(big.Float).Mul(big.NewFloat(amountEther), big.NewFloat(params.Ether))
+	amountWei := new(big.Int)
+	amount.Int(amountWei)
+	return amountWei
+}
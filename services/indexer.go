@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/scmo/apayment-backend/ethereum"
+	"github.com/scmo/apayment-backend/models"
+)
+
+// indexer serialises the read model updates of the event handlers and the rollbacks
+var indexer sync.Mutex
+
+const (
+	// number of blocks kept to find the common ancestor of a reorganisation
+	indexedBlocksKept = 256
+	// number of blocks indexed per run when catching up
+	indexedBlocksPerRun = 1000
+)
+
+// IndexBlocks follows the chain block by block. A block whose parent hash does not
+// match the indexed block before it reveals a reorganisation, the state indexed
+// from the orphaned blocks is rolled back.
+func IndexBlocks() error {
+	ethereumController := ethereum.GetEthereumController()
+	ctx := context.Background()
+	head, err := ethereumController.Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		beego.Error("Failed to read latest block header: ", err)
+		return err
+	}
+
+	indexer.Lock()
+	var orphaned map[int64]bool
+	defer func() {
+		indexer.Unlock()
+		// subscriptions of rolled back requests replay their events once renewed
+		for requestId := range orphaned {
+			unwatchRequest(requestId)
+		}
+	}()
+
+	o := orm.NewOrm()
+	var last models.IndexedBlock
+	err = o.QueryTable(new(models.IndexedBlock)).OrderBy("-Number").One(&last)
+	if err == orm.ErrNoRows {
+		return indexBlock(head)
+	} else if err != nil {
+		return err
+	}
+
+	// the indexed tip itself may have been replaced
+	tip, err := ethereumController.Client.HeaderByNumber(ctx, new(big.Int).SetUint64(last.Number))
+	if err != nil {
+		return err
+	}
+	if tip.Hash().Hex() != last.Hash {
+		orphaned, err = rollbackReorg(last.Number - 1)
+		return err
+	}
+
+	end := head.Number.Uint64()
+	if end > last.Number+indexedBlocksPerRun {
+		end = last.Number + indexedBlocksPerRun
+	}
+	parentHash := last.Hash
+	for number := last.Number + 1; number <= end; number++ {
+		header, err := ethereumController.Client.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return err
+		}
+		if header.ParentHash.Hex() != parentHash {
+			orphaned, err = rollbackReorg(number - 1)
+			return err
+		}
+		// events may have been applied from a block the indexer has not seen yet
+		if o.QueryTable(new(models.ContractEvent)).Filter("BlockNumber", number).Exclude("BlockHash", header.Hash().Hex()).Exist() {
+			orphaned, err = rollback(number - 1)
+			return err
+		}
+		if err := indexBlock(header); err != nil {
+			return err
+		}
+		parentHash = header.Hash().Hex()
+	}
+
+	if head.Number.Uint64() > indexedBlocksKept {
+		_, err = o.QueryTable(new(models.IndexedBlock)).Filter("Number__lt", head.Number.Uint64()-indexedBlocksKept).Delete()
+	}
+	return err
+}
+
+func indexBlock(header *types.Header) error {
+	o := orm.NewOrm()
+	block := models.IndexedBlock{Number: header.Number.Uint64(), Hash: header.Hash().Hex(), ParentHash: header.ParentHash.Hex()}
+	_, err := o.Insert(&block)
+	if err != nil {
+		beego.Error("Failed to index block: ", err)
+	}
+	return err
+}
+
+// rollbackReorg walks back from the given block to the last indexed block that is
+// still part of the chain and rolls back everything indexed after it.
+func rollbackReorg(from uint64) (map[int64]bool, error) {
+	ethereumController := ethereum.GetEthereumController()
+	o := orm.NewOrm()
+	ancestor := from
+	for ; ancestor > 0; ancestor-- {
+		var block models.IndexedBlock
+		if err := o.QueryTable(new(models.IndexedBlock)).Filter("Number", ancestor).One(&block); err != nil {
+			// older blocks are not indexed anymore, roll back everything kept
+			break
+		}
+		header, err := ethereumController.Client.HeaderByNumber(context.Background(), new(big.Int).SetUint64(ancestor))
+		if err != nil {
+			return nil, err
+		}
+		if header.Hash().Hex() == block.Hash {
+			break
+		}
+	}
+	return rollback(ancestor)
+}
+
+// rollback removes the state indexed from the blocks after ancestor. It returns the
+// requests whose read model has been rebuilt.
+func rollback(ancestor uint64) (map[int64]bool, error) {
+	beego.Warning("Chain reorganisation, roll back to block ", ancestor)
+	o := orm.NewOrm()
+	var events []*models.ContractEvent
+	if _, err := o.QueryTable(new(models.ContractEvent)).Filter("BlockNumber__gt", ancestor).All(&events); err != nil {
+		return nil, err
+	}
+	requests := make(map[int64]bool)
+	for _, event := range events {
+		requests[event.Request.Id] = true
+	}
+	if _, err := o.QueryTable(new(models.ContractEvent)).Filter("BlockNumber__gt", ancestor).Delete(); err != nil {
+		return nil, err
+	}
+	for requestId := range requests {
+		if err := rebuildRequestReadModel(requestId); err != nil {
+			beego.Error("Failed to roll back request ", requestId, ": ", err)
+			return requests, err
+		}
+	}
+	if _, err := o.QueryTable(new(models.Request)).Filter("SyncedBlock__gt", ancestor).Update(orm.Params{"SyncedBlock": ancestor}); err != nil {
+		return requests, err
+	}
+	if err := rollbackTransactions(ancestor); err != nil {
+		return requests, err
+	}
+	_, err := o.QueryTable(new(models.IndexedBlock)).Filter("Number__gt", ancestor).Delete()
+	return requests, err
+}
+
+// rebuildRequestReadModel derives the read model of a request from its remaining events
+func rebuildRequestReadModel(requestId int64) error {
+	o := orm.NewOrm()
+	request := models.Request{Id: requestId}
+	if err := o.Read(&request); err != nil {
+		return err
+	}
+	events := o.QueryTable(new(models.ContractEvent)).Filter("Request", requestId)
+
+	// the latest LacksAdded tells how many lacks are stored in the contract
+	var lacksAdded models.ContractEvent
+	request.NumLacks = 0
+	if err := events.Filter("Name", models.ContractEventLacksAdded).OrderBy("-NumLacks").One(&lacksAdded); err == nil {
+		request.NumLacks = lacksAdded.NumLacks
+	}
+	if _, err := o.QueryTable(new(models.RequestLack)).Filter("Request", requestId).Filter("Index__gte", request.NumLacks).Delete(); err != nil {
+		return err
+	}
+
+	// lacks whose LackReversed event was rolled back count again
+	var reversals []*models.ContractEvent
+	if _, err := events.Filter("Name", models.ContractEventLackReversed).All(&reversals); err != nil {
+		return err
+	}
+	reversed := o.QueryTable(new(models.RequestLack)).Filter("Request", requestId).Filter("Reversed", true)
+	for _, reversal := range reversals {
+		reversed = reversed.Exclude("Index", reversal.LackIndex)
+	}
+	if _, err := reversed.Update(orm.Params{"Reversed": false}); err != nil {
+		return err
+	}
+
+	if !events.Filter("Name", models.ContractEventGVESet).Exist() {
+		if _, err := o.QueryTable(new(models.RequestPointGroup)).Filter("Request", requestId).Delete(); err != nil {
+			return err
+		}
+	}
+
+	// Without a remaining InspectorAssigned the inspector set by the API is kept,
+	// its transaction is pending again.
+	var assigned models.ContractEvent
+	if err := events.Filter("Name", models.ContractEventInspectorAssigned).OrderBy("-BlockNumber", "-LogIndex").One(&assigned); err == nil {
+		inspector := models.User{EtherumAddress: assigned.Inspector}
+		if err := o.Read(&inspector, "EtherumAddress"); err == nil {
+			request.Inspector = &inspector
+		}
+	}
+	if _, err := o.Update(&request, "NumLacks", "Inspector"); err != nil {
+		return err
+	}
+	return dropReconciliation(o, requestId)
+}
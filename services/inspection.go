@@ -0,0 +1,247 @@
+package services
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/scmo/apayment-backend/models"
+	"github.com/scmo/apayment-backend/services/calculation"
+	"github.com/scmo/apayment-backend/services/tvd"
+)
+
+// maxLackPoints is the most points an inspector may give for a variable lack, 110
+// points and more withdraw the whole contribution of the point group
+const maxLackPoints = 110
+
+// minPointGroupPoints is the fewest points the contract deducts for a point group and a
+// contribution, it subtracts 10 from the points
+const minPointGroupPoints = 10
+
+// FieldError names the input field a validation failed on
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// InspectionValidationError lists the lacks of an inspection that do not match the catalog
+type InspectionValidationError struct {
+	Errors []*FieldError `json:"errors"`
+}
+
+func (e *InspectionValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, fieldError := range e.Errors {
+		messages[i] = fieldError.Field + ": " + fieldError.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// LacksSentError is returned when the lacks of an inspection were sent to the contract but
+// the request could not be updated after. The lacks must not be sent again.
+type LacksSentError struct {
+	Hash string
+	Err  error
+}
+
+func (e *LacksSentError) Error() string {
+	return "The lacks were sent with " + e.Hash + ", but the request was not updated: " + e.Err.Error()
+}
+
+// ValidateInspection checks each lack against the catalog of the request year, from
+// the contribution down to the lack, and against the animals the farm keeps. Lacks
+// without points get the points of the catalog, other points are only accepted for
+// variable lacks and, as a multiple of the catalog points, for lacks computed from the
+// RAUS journal. The points of a point group and a contribution, with the lacks of the
+// request, must not add up to fewer than 10. Evidence of the request must belong to one of
+// the lacks.
+func ValidateInspection(inspection *models.Inspection, request *models.Request) error {
+	year, err := referenceYear(request)
+	if err != nil {
+		return err
+	}
+	validation := &InspectionValidationError{Errors: make([]*FieldError, 0)}
+	fail := func(i int, field string, message string) {
+		validation.Errors = append(validation.Errors, &FieldError{Field: "lacks[" + strconv.Itoa(i) + "]." + field, Message: message})
+	}
+
+	contributions := make(map[uint16]*models.Contribution)
+	var animals map[uint16]models.EarTagNumbers
+	seen := make(map[string]bool)
+	totals, err := requestLackPoints(request.Id)
+	if err != nil {
+		return err
+	}
+	lastLacks := make(map[string]int) // index of the last lack of a point group and a contribution
+	for i, inspectionLack := range inspection.Lacks {
+		code := strconv.Itoa(int(inspectionLack.ContributionCode))
+		if request.ContributionCodes != "" && !strings.Contains(request.ContributionCodes, ","+code+",") {
+			fail(i, "contributionCode", "Contribution "+code+" is not requested")
+			continue
+		}
+		contribution, ok := contributions[inspectionLack.ContributionCode]
+		if !ok {
+			var err error
+			if contribution, err = GetContributionByCodeForYear(inspectionLack.ContributionCode, year); err != nil {
+				fail(i, "contributionCode", "Unknown contribution "+code)
+				continue
+			}
+			contributions[inspectionLack.ContributionCode] = contribution
+		}
+
+		controlCategory := findControlCategory(contribution, inspectionLack.ControlCategoryId)
+		if controlCategory == nil {
+			fail(i, "controlCategoryId", "Control category "+strconv.FormatInt(inspectionLack.ControlCategoryId, 10)+" is not part of contribution "+code+" in "+strconv.Itoa(year))
+			continue
+		}
+		pointGroup := findPointGroup(controlCategory, inspectionLack.PointGroupCode)
+		if pointGroup == nil {
+			fail(i, "pointGroupCode", "Point group "+strconv.Itoa(int(inspectionLack.PointGroupCode))+" is not part of control category "+controlCategory.ControlCategoryId)
+			continue
+		}
+		if isAnimalCategory(pointGroup.PointGroupCode) {
+			if animals == nil {
+				var err error
+				if animals, err = prepareTVDList(request.User.TVD, year); err != nil {
+					return err
+				}
+			}
+			if len(animals[pointGroup.PointGroupCode].EarTagNumbers) == 0 {
+				fail(i, "pointGroupCode", "The farm keeps no animals of point group "+strconv.Itoa(int(pointGroup.PointGroupCode))+" in "+strconv.Itoa(year))
+				continue
+			}
+		}
+		controlPoint := findControlPoint(pointGroup, inspectionLack.ControlPointId)
+		if controlPoint == nil {
+			fail(i, "controlPointId", "Control point "+strconv.FormatInt(inspectionLack.ControlPointId, 10)+" is not part of point group "+strconv.Itoa(int(pointGroup.PointGroupCode)))
+			continue
+		}
+		lack := findLack(controlPoint, inspectionLack.LackId)
+		if lack == nil {
+			fail(i, "lackId", "Lack "+strconv.FormatInt(inspectionLack.LackId, 10)+" is not part of control point "+controlPoint.ControlPointId)
+			continue
+		}
+
+		key := code + "/" + strconv.Itoa(int(pointGroup.PointGroupCode)) + "/" + strconv.FormatInt(lack.Id, 10)
+		if seen[key] {
+			fail(i, "lackId", "Lack "+strconv.FormatInt(lack.Id, 10)+" is reported twice for point group "+strconv.Itoa(int(pointGroup.PointGroupCode)))
+			continue
+		}
+		seen[key] = true
+
+		switch {
+		case inspectionLack.Points == 0:
+			inspectionLack.Points = uint8(lack.Points)
+		case lack.Computed:
+			// the points of the catalog are given per day missing in the RAUS journal
+			if lack.Points > 0 && int(inspectionLack.Points)%int(lack.Points) != 0 {
+				fail(i, "points", "Lack "+strconv.FormatInt(lack.Id, 10)+" gives "+strconv.Itoa(int(lack.Points))+" points per missing day")
+			}
+		case lack.Variable && inspectionLack.Points > maxLackPoints:
+			fail(i, "points", "At most "+strconv.Itoa(maxLackPoints)+" points can be given")
+		case !lack.Variable && int(inspectionLack.Points) != int(lack.Points):
+			fail(i, "points", "Lack "+strconv.FormatInt(lack.Id, 10)+" is fixed to "+strconv.Itoa(int(lack.Points))+" points")
+		}
+		if pointsKey := lackPointsKey(inspectionLack.ContributionCode, inspectionLack.PointGroupCode); pointsKey != "" {
+			totals[pointsKey] += int(inspectionLack.Points)
+			lastLacks[pointsKey] = i
+		}
+	}
+	for pointsKey, i := range lastLacks {
+		if total := totals[pointsKey]; total > 0 && total < minPointGroupPoints {
+			fail(i, "points", "The lacks of contribution and point group "+pointsKey+" add up to "+strconv.Itoa(total)+" points, the contract deducts no fewer than "+strconv.Itoa(minPointGroupPoints))
+		}
+	}
+
+	// evidence is anchored together with the lacks it belongs to
+	reported := make(map[string]bool)
+	for _, inspectionLack := range inspection.Lacks {
+		reported[evidenceLackKey(inspectionLack.ContributionCode, inspectionLack.ControlCategoryId, inspectionLack.PointGroupCode, inspectionLack.ControlPointId, inspectionLack.LackId)] = true
+	}
+	evidence, err := loadRequestEvidence(request.Id)
+	if err != nil {
+		return err
+	}
+	for _, e := range evidence {
+		if e.Anchor == nil && !reported[evidenceLackKey(e.ContributionCode, e.ControlCategoryId, e.PointGroupCode, e.ControlPointId, e.LackId)] {
+			validation.Errors = append(validation.Errors, &FieldError{Field: "evidence[" + strconv.FormatInt(e.Id, 10) + "]", Message: "Evidence " + e.FileName + " belongs to no reported lack"})
+		}
+	}
+
+	if len(validation.Errors) > 0 {
+		return validation
+	}
+	return nil
+}
+
+// requestLackPoints sums the points of the lacks of a request which are not reversed by
+// contribution and point group. Only the BTS and RAUS lacks count in the contract, the
+// key of the others is empty.
+func requestLackPoints(requestId int64) (map[string]int, error) {
+	o := orm.NewOrm()
+	var lacks []*models.RequestLack
+	if _, err := o.QueryTable(new(models.RequestLack)).Filter("Request", requestId).Filter("Reversed", false).All(&lacks); err != nil {
+		return nil, err
+	}
+	totals := make(map[string]int)
+	for _, lack := range lacks {
+		if pointsKey := lackPointsKey(lack.ContributionCode, lack.PointGroupCode); pointsKey != "" {
+			totals[pointsKey] += int(lack.Points)
+		}
+	}
+	return totals, nil
+}
+
+func lackPointsKey(contributionCode uint16, pointGroupCode uint16) string {
+	switch contributionCode {
+	case calculation.ContributionBTS, calculation.ContributionRAUS:
+		return strconv.Itoa(int(contributionCode)) + "/" + strconv.Itoa(int(pointGroupCode))
+	}
+	return ""
+}
+
+// isAnimalCategory tells whether the point group is an animal category of the TVD
+func isAnimalCategory(pointGroupCode uint16) bool {
+	for _, code := range tvd.GetPointGroupCodes() {
+		if code == pointGroupCode {
+			return true
+		}
+	}
+	return false
+}
+
+func findControlCategory(contribution *models.Contribution, id int64) *models.ControlCategory {
+	for _, controlCategory := range contribution.ControlCategories {
+		if controlCategory.Id == id {
+			return controlCategory
+		}
+	}
+	return nil
+}
+
+func findPointGroup(controlCategory *models.ControlCategory, code uint16) *models.PointGroup {
+	for _, pointGroup := range controlCategory.PointGroups {
+		if pointGroup.PointGroupCode == code {
+			return pointGroup
+		}
+	}
+	return nil
+}
+
+func findControlPoint(pointGroup *models.PointGroup, id int64) *models.ControlPoint {
+	for _, controlPoint := range pointGroup.ControlPoints {
+		if controlPoint.Id == id {
+			return controlPoint
+		}
+	}
+	return nil
+}
+
+func findLack(controlPoint *models.ControlPoint, id int64) *models.Lack {
+	for _, lack := range controlPoint.Lacks {
+		if lack.Id == id {
+			return lack
+		}
+	}
+	return nil
+}
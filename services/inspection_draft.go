@@ -0,0 +1,159 @@
+package services
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/scmo/apayment-backend/models"
+)
+
+// InspectionConflictError is returned when a draft was changed since the version the
+// change is based on, e.g. from another device. It carries the stored draft.
+type InspectionConflictError struct {
+	Current *models.InspectionDraft `json:"current"`
+}
+
+func (e *InspectionConflictError) Error() string {
+	return "Inspection draft was changed by device " + e.Current.DeviceId + " in the meantime"
+}
+
+// CreateInspectionDraft starts the draft of the inspection of a request by its inspector
+func CreateInspectionDraft(draft *models.InspectionDraft) error {
+	o := orm.NewOrm()
+	request := models.Request{Id: draft.RequestId}
+	if err := o.Read(&request); err != nil {
+		beego.Error("Error while fetching Request by ID: ", err)
+		return err
+	}
+	if err := CheckRequestTransition(&request, models.RequestStatusInspected); err != nil {
+		return err
+	}
+	if stored, err := GetInspectionDraft(request.Id); err == nil {
+		return &InspectionConflictError{Current: stored}
+	}
+
+	draft.Request = &request
+	draft.Inspector = request.Inspector
+	draft.Version = 1
+	draft.Status = models.InspectionDraftStatusOpen
+	if err := encodeInspectionLacks(draft); err != nil {
+		return err
+	}
+	_, err := o.Insert(draft)
+	return err
+}
+
+// UpdateInspectionDraft replaces the lacks and notes of an open draft, unless it was
+// changed since the version of the update
+func UpdateInspectionDraft(draft *models.InspectionDraft) error {
+	if err := encodeInspectionLacks(draft); err != nil {
+		return err
+	}
+	return changeInspectionDraft(draft, models.InspectionDraftStatusOpen, orm.Params{
+		"DeviceId": draft.DeviceId,
+		"LackList": draft.LackList,
+		"Notes":    draft.Notes,
+		"Started":  draft.Started,
+		"Recorded": draft.Recorded,
+	})
+}
+
+// FinaliseInspection adds the lacks of the draft to the request. The draft is
+// finalised first, so that a second device cannot add them again, and opened again
+// if the lacks are rejected before they are sent.
+func FinaliseInspection(draft *models.InspectionDraft, auth *bind.TransactOpts) (*models.InspectionDraft, error) {
+	stored, err := GetInspectionDraft(draft.RequestId)
+	if err != nil {
+		return nil, err
+	}
+	stored.Version = draft.Version
+	if err := changeInspectionDraft(stored, models.InspectionDraftStatusOpen, orm.Params{"Status": models.InspectionDraftStatusFinalised}); err != nil {
+		return nil, err
+	}
+
+	inspection := &models.Inspection{RequestId: stored.RequestId, InspectorId: stored.Inspector.Id, Lacks: stored.Lacks}
+	err = AddLacksToRequest(inspection, auth)
+	if _, sent := err.(*LacksSentError); sent {
+		// sending the lacks again would deduct them twice
+		storeFinalisedLacks(stored, inspection)
+		return nil, err
+	}
+	if err != nil {
+		if reopenErr := changeInspectionDraft(stored, models.InspectionDraftStatusFinalised, orm.Params{"Status": models.InspectionDraftStatusOpen}); reopenErr != nil {
+			beego.Error("Failed to reopen inspection draft of request ", stored.RequestId, ": ", reopenErr)
+		}
+		return nil, err
+	}
+	if err := storeFinalisedLacks(stored, inspection); err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+// storeFinalisedLacks keeps the lacks sent for a draft, their points were completed from the catalog
+func storeFinalisedLacks(draft *models.InspectionDraft, inspection *models.Inspection) error {
+	draft.Lacks = inspection.Lacks
+	if err := encodeInspectionLacks(draft); err != nil {
+		return err
+	}
+	o := orm.NewOrm()
+	if _, err := o.Update(draft, "LackList"); err != nil {
+		beego.Error("Failed to store finalised lacks of request ", draft.RequestId, ": ", err)
+	}
+	return nil
+}
+
+func GetInspectionDraft(requestId int64) (*models.InspectionDraft, error) {
+	o := orm.NewOrm()
+	var draft models.InspectionDraft
+	if err := o.QueryTable(new(models.InspectionDraft)).Filter("Request", requestId).RelatedSel("Inspector").One(&draft); err != nil {
+		return nil, err
+	}
+	draft.RequestId = requestId
+	if err := json.Unmarshal([]byte(draft.LackList), &draft.Lacks); err != nil {
+		beego.Error("Failed to decode lacks of inspection draft ", draft.Id, ": ", err)
+		return nil, err
+	}
+	return &draft, nil
+}
+
+// changeInspectionDraft updates a draft if it still has the version of the given one
+// and the status expected, and raises the version
+func changeInspectionDraft(draft *models.InspectionDraft, status string, params orm.Params) error {
+	params["Version"] = draft.Version + 1
+	params["Modified"] = time.Now()
+	o := orm.NewOrm()
+	num, err := o.QueryTable(new(models.InspectionDraft)).Filter("Request", draft.RequestId).Filter("Version", draft.Version).Filter("Status", status).Update(params)
+	if err != nil {
+		beego.Error("Failed to update inspection draft of request ", draft.RequestId, ": ", err)
+		return err
+	}
+	if num == 0 {
+		current, err := GetInspectionDraft(draft.RequestId)
+		if err != nil {
+			return err
+		}
+		return &InspectionConflictError{Current: current}
+	}
+	stored, err := GetInspectionDraft(draft.RequestId)
+	if err != nil {
+		return err
+	}
+	*draft = *stored
+	return nil
+}
+
+func encodeInspectionLacks(draft *models.InspectionDraft) error {
+	if draft.Lacks == nil {
+		draft.Lacks = make([]*models.InspectionLack, 0)
+	}
+	lacks, err := json.Marshal(draft.Lacks)
+	if err != nil {
+		return err
+	}
+	draft.LackList = string(lacks)
+	return nil
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/scmo/apayment-backend/models"
+)
+
+// SetInspectorProfile stores the capacity, certifications and base of an inspector
+func SetInspectorProfile(profile *models.InspectorProfile) error {
+	if profile.Capacity < 0 {
+		return errors.New("Invalid capacity " + strconv.Itoa(profile.Capacity))
+	}
+	for _, code := range profile.Certifications {
+		if _, err := GetContributionByCode(code); err != nil {
+			return errors.New("Unknown contribution " + strconv.Itoa(int(code)))
+		}
+	}
+	o := orm.NewOrm()
+	if exist := o.QueryTable(new(models.User)).Filter("Id", profile.User.Id).Filter("Roles__Role__Name", "Inspector").Exist(); !exist {
+		return errors.New("User " + strconv.FormatInt(profile.User.Id, 10) + " is no inspector")
+	}
+	profile.ContributionCodes = joinContributionCodes(profile.Certifications)
+
+	stored := models.InspectorProfile{User: profile.User}
+	if _, _, err := o.ReadOrCreate(&stored, "User"); err != nil {
+		beego.Error("Failed to store inspector profile: ", err)
+		return err
+	}
+	profile.Id = stored.Id
+	_, err := o.Update(profile, "Capacity", "ContributionCodes", "XCoordinate", "YCoordinate", "Modified")
+	return err
+}
+
+// ProposeInspectors returns the inspectors who can take the inspection of the
+// request, best first
+func ProposeInspectors(requestId int64) ([]*models.InspectorProposal, error) {
+	o := orm.NewOrm()
+	request := models.Request{Id: requestId}
+	if err := o.Read(&request); err != nil {
+		return nil, err
+	}
+	profiles, load, err := loadInspectorWorkload()
+	if err != nil {
+		return nil, err
+	}
+	return proposeInspectors(&request, profiles, load), nil
+}
+
+// AssignInspectors assigns the inspectors and submits a setInspectorId transaction for
+// each request. An assignment without an inspector takes the best proposed one, an
+// inspector given has to be one of the proposed. An empty list assigns all submitted
+// requests. Failures are reported per assignment.
+func AssignInspectors(assignments []*models.InspectorAssignment, auth *bind.TransactOpts) ([]*models.InspectorAssignment, error) {
+	o := orm.NewOrm()
+	if len(assignments) == 0 {
+		var requests []*models.Request
+		if _, err := o.QueryTable(new(models.Request)).Filter("Status", models.RequestStatusSubmitted).OrderBy("Id").All(&requests, "Id"); err != nil {
+			return nil, err
+		}
+		for _, request := range requests {
+			assignments = append(assignments, &models.InspectorAssignment{RequestId: request.Id})
+		}
+	}
+	profiles, load, err := loadInspectorWorkload()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, assignment := range assignments {
+		request := &models.Request{Id: assignment.RequestId}
+		if err := o.Read(request); err != nil {
+			assignment.Error = err.Error()
+			continue
+		}
+		proposals := proposeInspectors(request, profiles, load)
+		if assignment.InspectorId == 0 {
+			if len(proposals) == 0 {
+				assignment.Error = "No inspector available"
+				continue
+			}
+			assignment.InspectorId = proposals[0].Inspector.Id
+		} else if !isProposed(proposals, assignment.InspectorId) {
+			assignment.Error = "User " + strconv.FormatInt(assignment.InspectorId, 10) + " cannot inspect the request: an inspector certified for its contributions with free capacity, who is not the farmer, is required"
+			continue
+		}
+		if !o.QueryTable(new(models.User)).Filter("Id", assignment.InspectorId).Filter("Roles__Role__Name", "Inspector").Exist() {
+			assignment.Error = "User " + strconv.FormatInt(assignment.InspectorId, 10) + " is no inspector"
+			continue
+		}
+		request.Inspector = &models.User{Id: assignment.InspectorId}
+		if err := o.Read(request.Inspector); err != nil {
+			assignment.Error = err.Error()
+			continue
+		}
+		if err := AddInspectorToRequest(request, auth); err != nil {
+			beego.Error("Failed to assign inspector to request ", request.Id, ": ", err)
+			assignment.Error = err.Error()
+			continue
+		}
+		load[assignment.InspectorId]++
+	}
+	return assignments, nil
+}
+
+// loadInspectorWorkload loads the profiles of the inspectors and the number of open
+// inspections of each inspector
+func loadInspectorWorkload() ([]*models.InspectorProfile, map[int64]int, error) {
+	o := orm.NewOrm()
+	var profiles []*models.InspectorProfile
+	if _, err := o.QueryTable(new(models.InspectorProfile)).RelatedSel("User").OrderBy("Id").All(&profiles); err != nil {
+		beego.Error("Failed to load inspector profiles: ", err)
+		return nil, nil, err
+	}
+	var requests []*models.Request
+	if _, err := o.QueryTable(new(models.Request)).Filter("Status", models.RequestStatusInspectorAssigned).All(&requests, "Id", "Inspector"); err != nil {
+		beego.Error("Failed to load open inspections: ", err)
+		return nil, nil, err
+	}
+	load := make(map[int64]int)
+	for _, request := range requests {
+		if request.Inspector != nil {
+			load[request.Inspector.Id]++
+		}
+	}
+	return profiles, load, nil
+}
+
+// proposeInspectors ranks the inspectors with free capacity who are certified for all
+// contributions of the request. The score is the distance to the farm in km plus the
+// workload weight times the share of the capacity in use. An unknown distance counts
+// as 0, so only the workload decides.
+func proposeInspectors(request *models.Request, profiles []*models.InspectorProfile, load map[int64]int) []*models.InspectorProposal {
+	farm := farmPlant(request.User)
+	weight := beego.AppConfig.DefaultFloat("inspectorWorkloadWeight", 30)
+
+	proposals := make([]*models.InspectorProposal, 0)
+	for _, profile := range profiles {
+		open := load[profile.User.Id]
+		if open >= profile.Capacity || profile.User.Id == request.User.Id || !isCertified(profile, request.ContributionCodes) {
+			continue
+		}
+		distance := -1.0
+		if farm != nil && (profile.XCoordinate != 0 || profile.YCoordinate != 0) {
+			dx := float64(farm.XCoordinate) - float64(profile.XCoordinate)
+			dy := float64(farm.YCoordinate) - float64(profile.YCoordinate)
+			distance = math.Hypot(dx, dy) / 1000
+		}
+		score := math.Max(distance, 0) + weight*float64(open)/float64(profile.Capacity)
+		proposals = append(proposals, &models.InspectorProposal{
+			Inspector:       profile.User,
+			OpenInspections: open,
+			Capacity:        profile.Capacity,
+			Distance:        distance,
+			Score:           score,
+		})
+	}
+	sort.SliceStable(proposals, func(i, j int) bool {
+		if proposals[i].Score != proposals[j].Score {
+			return proposals[i].Score < proposals[j].Score
+		}
+		return proposals[i].Inspector.Id < proposals[j].Inspector.Id
+	})
+	return proposals
+}
+
+// isProposed tells whether the inspector is one of the proposed
+func isProposed(proposals []*models.InspectorProposal, inspectorId int64) bool {
+	for _, proposal := range proposals {
+		if proposal.Inspector.Id == inspectorId {
+			return true
+		}
+	}
+	return false
+}
+
+// isCertified tells whether the inspector is certified for all contribution codes of
+// the read model column
+func isCertified(profile *models.InspectorProfile, contributionCodes string) bool {
+	for _, code := range strings.Split(contributionCodes, ",") {
+		if code != "" && !strings.Contains(profile.ContributionCodes, ","+code+",") {
+			return false
+		}
+	}
+	return true
+}
+
+// joinContributionCodes encloses the codes in commas like the column of the requests
+func joinContributionCodes(codes []uint16) string {
+	joined := make([]string, len(codes))
+	for i, code := range codes {
+		joined[i] = strconv.Itoa(int(code))
+	}
+	return "," + strings.Join(joined, ",") + ","
+}
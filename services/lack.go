@@ -17,11 +17,12 @@ func CreateMultiLacks(lacks []models.Lack) error {
 	return err
 }
 
-func GetAllLacks() []*models.Lack {
+var lackSortFields = listFields{"id": "Id", "name": "Name", "points": "Points"}
+
+func GetAllLacks(query *ListQuery) (*Page, error) {
 	o := orm.NewOrm()
 	var lacks []*models.Lack
-	o.QueryTable(new(models.Lack)).All(&lacks)
-	return lacks
+	return paginate(o.QueryTable(new(models.Lack)), query, map[string]listFilter{"controlPoint": filterInt("ControlPoint")}, lackSortFields, &lacks)
 }
 
 func CountLacks() (int64, error) {
@@ -11,11 +11,13 @@ func CreateLegalForm(lf *models.LegalForm) error {
 	return err
 }
 
-func GetAllLegalForms() []*models.LegalForm {
+// codeSortFields are the sortable fields of the code lists
+var codeSortFields = listFields{"id": "Id", "code": "Code", "name": "Name"}
+
+func GetAllLegalForms(query *ListQuery) (*Page, error) {
 	o := orm.NewOrm()
 	var legalforms []*models.LegalForm
-	o.QueryTable(new(models.LegalForm)).All(&legalforms)
-	return legalforms
+	return paginate(o.QueryTable(new(models.LegalForm)), query, nil, codeSortFields, &legalforms)
 }
 
 func CountLegalForms() (int64, error) {
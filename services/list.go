@@ -0,0 +1,134 @@
+package services
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego/orm"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 500
+)
+
+// ListQuery holds the page, the sort order and the filters requested for a list
+type ListQuery struct {
+	Offset  int64
+	Limit   int64
+	Sort    string            // field, prefixed with "-" for descending order
+	Filters map[string]string // filter name -> value
+}
+
+// Page is the envelope of every list
+type Page struct {
+	Items  interface{} `json:"items"`
+	Total  int64       `json:"total"`
+	Offset int64       `json:"offset"`
+	Limit  int64       `json:"limit"`
+	Next   string      `json:"next,omitempty"` // link to the next page
+}
+
+// HasNext tells whether there are items after this page
+func (p *Page) HasNext() bool {
+	return p.Offset+p.Limit < p.Total
+}
+
+// ListQueryError is returned for an unknown sort field or filter, or an invalid filter value
+type ListQueryError struct {
+	Message string
+}
+
+func (e *ListQueryError) Error() string {
+	return e.Message
+}
+
+// listFilter restricts a query set by the value of a filter
+type listFilter func(qs orm.QuerySeter, value string) (orm.QuerySeter, error)
+
+// listFields maps the sortable fields of a list to their orm field
+type listFields map[string]string
+
+// paginate applies the filters, the sort order and the page of the query and loads
+// the page into container, a pointer to a slice
+func paginate(qs orm.QuerySeter, query *ListQuery, filters map[string]listFilter, sortFields listFields, container interface{}) (*Page, error) {
+	for name, value := range query.Filters {
+		filter, ok := filters[name]
+		if !ok {
+			return nil, &ListQueryError{Message: "Unknown filter " + name}
+		}
+		var err error
+		if qs, err = filter(qs, value); err != nil {
+			return nil, err
+		}
+	}
+	total, err := qs.Count()
+	if err != nil {
+		return nil, err
+	}
+
+	order := []string{"Id"}
+	if query.Sort != "" {
+		field, ok := sortFields[strings.TrimPrefix(query.Sort, "-")]
+		if !ok {
+			return nil, &ListQueryError{Message: "Unknown sort field " + query.Sort}
+		}
+		if strings.HasPrefix(query.Sort, "-") {
+			field = "-" + field
+		}
+		// the id keeps the order of equal values stable across pages
+		order = []string{field, "Id"}
+	}
+	offset, limit := pageBounds(query)
+	if _, err := qs.OrderBy(order...).Limit(limit, offset).All(container); err != nil {
+		return nil, err
+	}
+	return &Page{Items: container, Total: total, Offset: offset, Limit: limit}, nil
+}
+
+// pageBounds returns the offset and the limit of the query within the allowed range
+func pageBounds(query *ListQuery) (int64, int64) {
+	offset, limit := query.Offset, query.Limit
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 {
+		limit = defaultPageLimit
+	} else if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	return offset, limit
+}
+
+func filterString(field string) listFilter {
+	return func(qs orm.QuerySeter, value string) (orm.QuerySeter, error) {
+		return qs.Filter(field, value), nil
+	}
+}
+
+func filterInt(field string) listFilter {
+	return func(qs orm.QuerySeter, value string) (orm.QuerySeter, error) {
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, &ListQueryError{Message: "Invalid number " + value}
+		}
+		return qs.Filter(field, i), nil
+	}
+}
+
+// filterDate compares a unix timestamp field with a date (2006-01-02). A date ending
+// a range includes the whole day.
+func filterDate(field string, end bool) listFilter {
+	return func(qs orm.QuerySeter, value string) (orm.QuerySeter, error) {
+		oc, _ := time.LoadLocation("Europe/Zurich")
+		date, err := time.ParseInLocation("2006-01-02", value, oc)
+		if err != nil {
+			return nil, &ListQueryError{Message: "Invalid date " + value}
+		}
+		if end {
+			return qs.Filter(field+"__lt", date.AddDate(0, 0, 1).Unix()), nil
+		}
+		return qs.Filter(field+"__gte", date.Unix()), nil
+	}
+}
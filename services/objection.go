@@ -0,0 +1,407 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/scmo/apayment-backend/ethereum"
+	"github.com/scmo/apayment-backend/models"
+	"github.com/scmo/apayment-backend/smart-contracts/direct-payment-request"
+)
+
+// ObjectionPendingError is returned when the payment of a request is calculated while
+// an objection against its lacks is not settled
+type ObjectionPendingError struct {
+	RequestId int64
+}
+
+func (e *ObjectionPendingError) Error() string {
+	return "Objection against the lacks of request " + strconv.FormatInt(e.RequestId, 10) + " is not settled yet"
+}
+
+// ObjectionDecidedError is returned when an objection is changed after the canton decided on it
+type ObjectionDecidedError struct {
+	Status string
+}
+
+func (e *ObjectionDecidedError) Error() string {
+	return "Objection is " + e.Status + " already"
+}
+
+// ContractOutdatedError is returned when the contract of a request was deployed before
+// request.sol had the method called
+type ContractOutdatedError struct {
+	RequestId int64
+	Method    string
+}
+
+func (e *ContractOutdatedError) Error() string {
+	return "The contract of request " + strconv.FormatInt(e.RequestId, 10) + " was deployed before it had " + e.Method + ", the lacks have to be upheld or the request amended"
+}
+
+// FileObjection files an objection of the farmer against lacks of an inspected
+// request. The lacks are given by their index in the contract, each lack can be
+// objected to once.
+func FileObjection(objection *models.Objection) error {
+	if strings.TrimSpace(objection.Justification) == "" {
+		return &RequestValidationError{Message: "An objection needs a justification"}
+	}
+	if len(objection.LackIndexes) == 0 {
+		return &RequestValidationError{Message: "An objection needs at least one lack"}
+	}
+	o := orm.NewOrm()
+	request := models.Request{Id: objection.RequestId}
+	if err := o.Read(&request); err != nil {
+		beego.Error("Error while fetching Request by ID: ", err)
+		return err
+	}
+	if status := RequestStatus(&request); status != models.RequestStatusInspected && status != models.RequestStatusFirstPaymentMade {
+		return &RequestValidationError{Message: "Objections can only be filed against the lacks of an inspected request, not a request " + status}
+	}
+	if HasUnconfirmedPayment(request.Id) {
+		return &RequestValidationError{Message: "Objections cannot be filed while a payment is underway"}
+	}
+
+	indexes := make([]interface{}, 0, len(objection.LackIndexes))
+	for _, index := range objection.LackIndexes {
+		indexes = append(indexes, index)
+	}
+	var lacks []*models.RequestLack
+	if _, err := o.QueryTable(new(models.RequestLack)).Filter("Request", request.Id).Filter("Index__in", indexes...).All(&lacks); err != nil {
+		return err
+	}
+	found := make(map[int64]*models.RequestLack)
+	for _, lack := range lacks {
+		found[lack.Index] = lack
+	}
+	objected := make(map[int64]bool)
+	for _, index := range objection.LackIndexes {
+		lack, ok := found[index]
+		switch {
+		case !ok:
+			return &RequestValidationError{Message: "Lack " + strconv.FormatInt(index, 10) + " is not recorded for the request"}
+		case lack.Reversed || lack.Points == 0:
+			return &RequestValidationError{Message: "Lack " + strconv.FormatInt(index, 10) + " does not deduct any points"}
+		case objected[index]:
+			return &RequestValidationError{Message: "Lack " + strconv.FormatInt(index, 10) + " is listed twice"}
+		}
+		objected[index] = true
+	}
+	var previous models.ObjectionLack
+	if err := o.QueryTable(new(models.ObjectionLack)).Filter("Request", request.Id).Filter("Index__in", indexes...).One(&previous); err == nil {
+		return &RequestValidationError{Message: "Lack " + strconv.FormatInt(previous.Index, 10) + " has been objected to before"}
+	}
+
+	objection.Request = &request
+	objection.Farmer = request.User
+	objection.Status = models.ObjectionStatusOpen
+	objection.Reviewer = nil
+	objection.Reason = ""
+	if err := o.Begin(); err != nil {
+		return err
+	}
+	_, err := o.Insert(objection)
+	for _, index := range objection.LackIndexes {
+		if err != nil {
+			break
+		}
+		_, err = o.Insert(&models.ObjectionLack{Objection: objection, Request: &request, Index: index})
+	}
+	if err != nil {
+		o.Rollback()
+		beego.Error("Failed to file objection against request ", request.Id, ": ", err)
+		return err
+	}
+	if err := o.Commit(); err != nil {
+		return err
+	}
+	return loadObjection(objection)
+}
+
+// AddObjectionAttachment stores a photo or document supporting an open objection
+func AddObjectionAttachment(objection *models.Objection, fileName string, content io.Reader) (*models.ObjectionAttachment, error) {
+	if objection.Status != models.ObjectionStatusOpen {
+		return nil, &ObjectionDecidedError{Status: objection.Status}
+	}
+	hash, contentType, size, err := putEvidenceFile(content)
+	if err != nil {
+		return nil, err
+	}
+	attachment := &models.ObjectionAttachment{Objection: objection, FileName: fileName, ContentType: contentType, Size: size, Hash: hash}
+	o := orm.NewOrm()
+	if _, err := o.Insert(attachment); err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// ReadObjectionAttachment loads the file of an attachment and checks it against its hash
+func ReadObjectionAttachment(attachment *models.ObjectionAttachment) ([]byte, error) {
+	return readEvidenceFile(attachment.Hash)
+}
+
+// ReviewObjection records the decision of the canton on an open objection. Removed
+// lacks are reversed in the contract with the reason of the decision. When no
+// reversal could be sent, the objection is open again.
+func ReviewObjection(objection *models.Objection, review *models.ObjectionReview, reviewer *models.User, auth *bind.TransactOpts) error {
+	var status string
+	switch review.Decision {
+	case models.ObjectionDecisionUphold:
+		status = models.ObjectionStatusUpheld
+	case models.ObjectionDecisionRemove:
+		status = models.ObjectionStatusRemoved
+	default:
+		return &RequestValidationError{Message: "Unknown decision " + review.Decision}
+	}
+	if strings.TrimSpace(review.Reason) == "" {
+		return &RequestValidationError{Message: "A decision needs a reason"}
+	}
+	// without a selection all lacks of the objection are removed
+	removed := make([]interface{}, 0)
+	if len(review.LackIndexes) == 0 {
+		for _, lack := range objection.Lacks {
+			removed = append(removed, lack.Index)
+		}
+	}
+	for _, index := range review.LackIndexes {
+		if !objectionHasLack(objection, index) {
+			return &RequestValidationError{Message: "Lack " + strconv.FormatInt(index, 10) + " is not part of the objection"}
+		}
+		removed = append(removed, index)
+	}
+
+	o := orm.NewOrm()
+	if status == models.ObjectionStatusRemoved {
+		request := models.Request{Id: objection.RequestId}
+		if err := o.Read(&request); err != nil {
+			return err
+		}
+		reversible, err := contractHasMethod(request.Address, "reverseLack")
+		if err != nil {
+			return err
+		}
+		if !reversible {
+			return &ContractOutdatedError{RequestId: request.Id, Method: "reverseLack"}
+		}
+	}
+	num, err := o.QueryTable(new(models.Objection)).Filter("Id", objection.Id).Filter("Status", models.ObjectionStatusOpen).Update(orm.Params{
+		"Status":   status,
+		"Reviewer": reviewer.Id,
+		"Reason":   review.Reason,
+		"Decided":  time.Now(),
+	})
+	if err != nil {
+		beego.Error("Failed to decide objection ", objection.Id, ": ", err)
+		return err
+	}
+	if num == 0 {
+		if err := loadObjection(objection); err != nil {
+			return err
+		}
+		return &ObjectionDecidedError{Status: objection.Status}
+	}
+	if status == models.ObjectionStatusRemoved {
+		_, err = o.QueryTable(new(models.ObjectionLack)).Filter("Objection", objection.Id).Filter("Index__in", removed...).Update(orm.Params{"Removed": true})
+		if err == nil {
+			err = loadObjection(objection)
+		}
+		sent := 0
+		if err == nil {
+			sent, err = sendLackReversals(objection, auth)
+		}
+		if err != nil {
+			if sent == 0 {
+				reopenObjection(objection)
+			}
+			// otherwise the missing reversals are sent by RetryLackReversals
+			return err
+		}
+	}
+	return loadObjection(objection)
+}
+
+// RetryLackReversals sends the reversals of the removed lacks of an objection which
+// were not sent or failed. An objection whose contract cannot reverse lacks is open again.
+func RetryLackReversals(objection *models.Objection, auth *bind.TransactOpts) error {
+	if objection.Status != models.ObjectionStatusRemoved {
+		return &RequestValidationError{Message: "Only lacks of a removed objection are reversed"}
+	}
+	if _, err := sendLackReversals(objection, auth); err != nil {
+		if _, outdated := err.(*ContractOutdatedError); outdated {
+			reopenObjection(objection)
+		}
+		return err
+	}
+	return loadObjection(objection)
+}
+
+// HasOpenObjection tells whether an objection against the lacks of the request is
+// open or a removed lack is not reversed on chain yet
+func HasOpenObjection(requestId int64) bool {
+	o := orm.NewOrm()
+	if o.QueryTable(new(models.Objection)).Filter("Request", requestId).Filter("Status", models.ObjectionStatusOpen).Exist() {
+		return true
+	}
+	unconfirmed := orm.NewCondition().And("Reversal__isnull", true).Or("Reversal__Status__in", models.TransactionStatusPending, models.TransactionStatusMined, models.TransactionStatusFailed)
+	cond := orm.NewCondition().And("Request", requestId).And("Removed", true).AndCond(unconfirmed)
+	return o.QueryTable(new(models.ObjectionLack)).SetCond(cond).Exist()
+}
+
+func GetObjectionById(objectionId int64) (*models.Objection, error) {
+	o := orm.NewOrm()
+	objection := models.Objection{Id: objectionId}
+	if err := o.Read(&objection); err != nil {
+		return nil, err
+	}
+	if err := loadObjection(&objection); err != nil {
+		return nil, err
+	}
+	return &objection, nil
+}
+
+func GetObjectionAttachmentById(attachmentId int64) (*models.ObjectionAttachment, error) {
+	o := orm.NewOrm()
+	attachment := models.ObjectionAttachment{Id: attachmentId}
+	if err := o.Read(&attachment); err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// GetRequestObjections returns the objections against the lacks of a request, the latest first
+func GetRequestObjections(requestId int64) ([]*models.Objection, error) {
+	o := orm.NewOrm()
+	objections := make([]*models.Objection, 0)
+	if _, err := o.QueryTable(new(models.Objection)).Filter("Request", requestId).OrderBy("-Id").All(&objections); err != nil {
+		beego.Error("Failed to load objections of request ", requestId, ": ", err)
+		return nil, err
+	}
+	for _, objection := range objections {
+		if err := loadObjection(objection); err != nil {
+			return nil, err
+		}
+	}
+	return objections, nil
+}
+
+// sendLackReversals reverses the removed lacks of an objection in the contract and
+// returns the number of reversals sent
+func sendLackReversals(objection *models.Objection, auth *bind.TransactOpts) (int, error) {
+	o := orm.NewOrm()
+	request := models.Request{Id: objection.RequestId}
+	if err := o.Read(&request); err != nil {
+		return 0, err
+	}
+	reversible, err := contractHasMethod(request.Address, "reverseLack")
+	if err != nil {
+		return 0, err
+	}
+	if !reversible {
+		beego.Warning("Contract ", request.Address, " of request ", request.Id, " cannot reverse lacks")
+		return 0, &ContractOutdatedError{RequestId: request.Id, Method: "reverseLack"}
+	}
+	requestContract, err := getRequestContractByAddress(request.Address)
+	if err != nil {
+		beego.Error("Error while fetching RequestContract by Address: ", err)
+		return 0, err
+	}
+	session := getRequestContractSession(requestContract)
+	session.TransactOpts.From = auth.From
+	session.TransactOpts.Signer = auth.Signer
+
+	sent := 0
+	for _, lack := range objection.Lacks {
+		if !lack.Removed || (lack.Reversal != nil && lack.Reversal.Status != models.TransactionStatusFailed) {
+			continue
+		}
+		tx, err := session.ReverseLack(big.NewInt(lack.Index), objection.Reason)
+		if err != nil {
+			beego.Error("Failed to reverse lack ", lack.Index, " of request ", request.Id, ": ", err)
+			return sent, err
+		}
+		beego.Info("Transaction waiting to be mined: ", tx.Hash().String())
+		transaction := &models.EthereumTransaction{Kind: models.TransactionKindReverseLack, From: auth.From.String(), Request: &request, Detail: objection.Reason}
+		if err := TrackTransaction(tx, transaction); err != nil {
+			return sent, err
+		}
+		sent++
+		lack.Reversal = transaction
+		if _, err := o.Update(lack, "Reversal"); err != nil {
+			beego.Error("Failed to store reversal of lack ", lack.Index, " of request ", request.Id, ": ", err)
+			return sent, err
+		}
+	}
+	return sent, nil
+}
+
+// contractHasMethod tells whether the code of a request contract has the method. The
+// dispatcher of solc pushes the selector of every method the contract implements.
+func contractHasMethod(address string, method string) (bool, error) {
+	parsed, err := abi.JSON(strings.NewReader(directpaymentrequest.RequestContractABI))
+	if err != nil {
+		return false, err
+	}
+	m, ok := parsed.Methods[method]
+	if !ok {
+		return false, nil
+	}
+	code, err := ethereum.GetEthereumController().Client.CodeAt(context.Background(), common.HexToAddress(address), nil)
+	if err != nil {
+		beego.Error("Failed to read code of contract ", address, ": ", err)
+		return false, err
+	}
+	// PUSH4 selector
+	return bytes.Contains(code, append([]byte{0x63}, m.Id()...)), nil
+}
+
+// reopenObjection takes back a decision whose reversals could not be sent
+func reopenObjection(objection *models.Objection) {
+	o := orm.NewOrm()
+	_, err := o.QueryTable(new(models.ObjectionLack)).Filter("Objection", objection.Id).Update(orm.Params{"Removed": false})
+	if err == nil {
+		_, err = o.QueryTable(new(models.Objection)).Filter("Id", objection.Id).Update(orm.Params{"Status": models.ObjectionStatusOpen, "Reason": ""})
+	}
+	if err != nil {
+		beego.Error("Failed to reopen objection ", objection.Id, ": ", err)
+		return
+	}
+	if err := loadObjection(objection); err != nil {
+		beego.Error("Failed to load objection ", objection.Id, ": ", err)
+	}
+}
+
+// loadObjection reads the objection with its lacks and attachments
+func loadObjection(objection *models.Objection) error {
+	o := orm.NewOrm()
+	if err := o.Read(objection); err != nil {
+		return err
+	}
+	objection.RequestId = objection.Request.Id
+	objection.LackIndexes = nil
+	objection.Lacks = make([]*models.ObjectionLack, 0)
+	if _, err := o.QueryTable(new(models.ObjectionLack)).Filter("Objection", objection.Id).RelatedSel("Reversal").OrderBy("Index").All(&objection.Lacks); err != nil {
+		return err
+	}
+	objection.Attachments = make([]*models.ObjectionAttachment, 0)
+	_, err := o.QueryTable(new(models.ObjectionAttachment)).Filter("Objection", objection.Id).OrderBy("Id").All(&objection.Attachments)
+	return err
+}
+
+func objectionHasLack(objection *models.Objection, index int64) bool {
+	for _, lack := range objection.Lacks {
+		if lack.Index == index {
+			return true
+		}
+	}
+	return false
+}
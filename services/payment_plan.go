@@ -0,0 +1,315 @@
+package services
+
+import (
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/scmo/apayment-backend/ethereum"
+	"github.com/scmo/apayment-backend/models"
+	"github.com/scmo/apayment-backend/services/calculation"
+)
+
+// TrancheNotDueError is returned when a request is paid while none of its tranches is due
+type TrancheNotDueError struct {
+	Message string
+}
+
+func (e *TrancheNotDueError) Error() string {
+	return e.Message
+}
+
+// GetPaymentPlan lists the tranches of the schedule of a request. The tranches are paid in
+// order, a tranche is paid by its payout, transfers sent before payouts were stored pay the
+// first tranches. The amounts of the tranches not paid yet are calculated from the confirmed
+// state of the contract.
+func GetPaymentPlan(request *models.Request) (*models.PaymentPlan, error) {
+	if !hasRequestContract(request) {
+		return nil, &RequestValidationError{Message: "Request " + strconv.FormatInt(request.Id, 10) + " has no contract"}
+	}
+	year, err := referenceYear(request)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := GetPaymentSchedule(request.Canton, year)
+	if err != nil {
+		return nil, err
+	}
+	requestContract, err := getRequestContractByAddress(request.Address)
+	if err != nil {
+		beego.Error("Error while fetching RequestContract by Address: ", err)
+		return nil, err
+	}
+	// payments are based on confirmed state only
+	opts, err := ethereum.ConfirmedCallOpts()
+	if err != nil {
+		return nil, err
+	}
+	amountPreviousYear, err := requestContract.AmountPreviousYear(opts)
+	if err != nil {
+		return nil, err
+	}
+	firstPayment, err := requestContract.GetFirstPaymentAmount(opts)
+	if err != nil {
+		return nil, err
+	}
+	finalPayment, err := requestContract.GetFinalPaymentAmount(opts)
+	if err != nil {
+		return nil, err
+	}
+	payouts, err := getRequestPayouts(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	transfers, err := getUnpaidOutTransfers(request.Id, payouts)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &models.PaymentPlan{RequestId: request.Id, Canton: request.Canton, Year: year, Entitlement: calculation.Entitlement(finalPayment, firstPayment), Paid: new(big.Int)}
+	// payments made before transfers were tracked only show in the state
+	untracked := 0
+	switch RequestStatus(request) {
+	case models.RequestStatusFirstPaymentMade:
+		untracked = 1
+	case models.RequestStatusFinalPaymentMade, models.RequestStatusClosed:
+		untracked = len(schedule.Tranches)
+	}
+	planned := new(big.Int) // paid and planned before the tranche
+	now := time.Now()
+	for i, tranche := range schedule.Tranches {
+		planTranche := &models.PlannedTranche{Position: tranche.Position, Name: tranche.Name, Rule: tranche.Rule, Percentage: tranche.Percentage, Due: trancheDue(tranche, year)}
+		plan.Tranches = append(plan.Tranches, planTranche)
+		var transfer *models.EthereumTransaction
+		sent := "" // amount of the payout or the transfer paying the tranche
+		if payout, ok := payouts[tranche.Position]; ok {
+			transfer, sent = payout.Transaction, payout.Amount
+		} else if len(transfers) > 0 {
+			transfer, sent = transfers[0], transfers[0].Amount
+			transfers = transfers[1:]
+		}
+		if sent != "" {
+			// a reserved payout is pending until its transfer is confirmed
+			planTranche.Status = models.TrancheStatusPending
+			if transfer != nil {
+				planTranche.Transaction = transfer.Hash
+				if transfer.Status == models.TransactionStatusConfirmed {
+					planTranche.Status = models.TrancheStatusPaid
+				}
+			}
+			planTranche.Amount, _ = new(big.Int).SetString(sent, 10)
+			if planTranche.Amount != nil {
+				plan.Paid.Add(plan.Paid, planTranche.Amount)
+				planned.Add(planned, planTranche.Amount)
+			}
+			continue
+		}
+		var amount *big.Int
+		switch tranche.Rule {
+		case models.TrancheRulePreviousYear:
+			amount = calculation.PreviousYearTranche(tranche.Percentage, amountPreviousYear)
+		case models.TrancheRuleEntitlement:
+			amount = calculation.EntitlementTranche(tranche.Percentage, plan.Entitlement, planned)
+		default:
+			amount = calculation.EntitlementTranche(100, plan.Entitlement, planned)
+		}
+		planned.Add(planned, amount)
+		if i < untracked {
+			// the amount the rule gave is taken as paid
+			planTranche.Status = models.TrancheStatusPaid
+			continue
+		}
+		planTranche.Amount = amount
+		planTranche.Status = models.TrancheStatusPlanned
+		if mayTrancheBeDue(request, tranche.Rule, planTranche.Due) && (planTranche.Due == nil || !now.Before(*planTranche.Due)) {
+			planTranche.Status = models.TrancheStatusDue
+		}
+	}
+	return plan, nil
+}
+
+// loadPayableRequest loads a request with the state of its contract. The status of requests
+// without a stored status is inferred from their confirmed payments.
+func loadPayableRequest(requestId int64) (*models.Request, error) {
+	request := GetRequestById(requestId, true)
+	if request.Id == 0 {
+		return nil, orm.ErrNoRows
+	}
+	if request.Status == "" {
+		payments, err := getRequestPayments(request)
+		if err != nil {
+			return nil, err
+		}
+		request.Payments = payments
+	}
+	return request, nil
+}
+
+// getRequestPayments reads the confirmed transfers made for a request to its farmer from the
+// transfer events of the token, including those sent before transfers were tracked
+func getRequestPayments(request *models.Request) ([]*models.APaymentTokenTransaction, error) {
+	confirmed, err := ethereum.ConfirmedBlockNumber()
+	if err != nil {
+		return nil, err
+	}
+	events, err := filterRequestTransfers(&bind.FilterOpts{Start: 0, End: &confirmed}, nil, []common.Address{common.HexToAddress(request.User.EtherumAddress)}, request.Address)
+	if err != nil {
+		beego.Error("Failed to read transfers of request ", request.Id, ": ", err)
+		return nil, err
+	}
+	payments := make([]*models.APaymentTokenTransaction, len(events))
+	for i, event := range events {
+		payments[i] = &models.APaymentTokenTransaction{BlockNumber: event.Raw.BlockNumber, Amount: event.Value, Request: request}
+	}
+	return payments, nil
+}
+
+// nextDueTranche returns the payment plan of a request and its next tranche, which has to
+// be due. position is optional, when given it has to be the next tranche.
+func nextDueTranche(request *models.Request, position int) (*models.PaymentPlan, *models.PlannedTranche, error) {
+	// a payment is only taken into account once it is confirmed
+	if HasUnconfirmedPayment(request.Id) {
+		return nil, nil, &TrancheNotDueError{Message: "Previous payment of request " + strconv.FormatInt(request.Id, 10) + " is not confirmed yet"}
+	}
+	// the deductions may still change
+	if HasOpenObjection(request.Id) {
+		return nil, nil, &ObjectionPendingError{RequestId: request.Id}
+	}
+	if err := checkRequestReconciled(request); err != nil {
+		return nil, nil, err
+	}
+	plan, err := GetPaymentPlan(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	var next *models.PlannedTranche
+	for _, tranche := range plan.Tranches {
+		if tranche.Status != models.TrancheStatusPaid && tranche.Status != models.TrancheStatusPending {
+			next = tranche
+			break
+		}
+	}
+	switch {
+	case next == nil:
+		return nil, nil, &TrancheNotDueError{Message: "All tranches of request " + strconv.FormatInt(request.Id, 10) + " are paid"}
+	case position != 0 && position != next.Position:
+		return nil, nil, &TrancheNotDueError{Message: "The next tranche is " + strconv.Itoa(next.Position) + ", not " + strconv.Itoa(position)}
+	case next.Status != models.TrancheStatusDue && next.Due != nil && mayTrancheBeDue(request, next.Rule, next.Due):
+		return nil, nil, &TrancheNotDueError{Message: next.Name + " is due on " + next.Due.Format("2006-01-02")}
+	case next.Status != models.TrancheStatusDue:
+		return nil, nil, &TrancheNotDueError{Message: next.Name + " is due once the request is inspected"}
+	}
+	if _, err := trancheRequestStatus(request, plan, next); err != nil {
+		return nil, nil, err
+	}
+	return plan, next, nil
+}
+
+// payTranche transfers a tranche of the plan of a request from the payer to the farmer.
+// The payout of the tranche is stored with the key before its transfer is sent.
+func payTranche(request *models.Request, plan *models.PaymentPlan, next *models.PlannedTranche, payer *models.User, key string) error {
+	status, err := trancheRequestStatus(request, plan, next)
+	if err != nil {
+		return err
+	}
+	transfer := &models.APaymentTokenTransfer{
+		From:    payer.EtherumAddress,
+		To:      request.User.EtherumAddress,
+		Amount:  next.Amount,
+		Message: next.Name,
+	}
+	payout, err := reservePayout(request, next, payer.EtherumAddress, key)
+	if err != nil {
+		return err
+	}
+	transaction, err := sendTransfer(transfer, request.Address)
+	if err != nil {
+		failPayout(payout)
+		return err
+	}
+	// a transfer sent but not stored stays reserved, ReconcilePayouts matches it with its event
+	if transaction.Id != 0 {
+		linkPayout(payout, transaction)
+	}
+	next.Status = models.TrancheStatusPending
+	next.Transaction = transaction.Hash
+	if status == "" {
+		return nil
+	}
+	return TransitionRequest(request, status, payer.EtherumAddress, next.Name)
+}
+
+// trancheRequestStatus returns the state a request moves to once the tranche is paid. An
+// advance on the amount of the previous year paid before the inspection keeps the state
+// of the request, "" is returned.
+func trancheRequestStatus(request *models.Request, plan *models.PaymentPlan, next *models.PlannedTranche) (string, error) {
+	if !isRequestInspected(request) && mayTrancheBeDue(request, next.Rule, next.Due) {
+		return "", nil
+	}
+	status := models.RequestStatusFirstPaymentMade
+	if next.Position == len(plan.Tranches) {
+		status = models.RequestStatusFinalPaymentMade
+	}
+	return status, CheckRequestTransition(request, status)
+}
+
+// getRequestTransfers loads the transfers to the farmer of a request which did not fail, in the order they were sent
+func getRequestTransfers(requestId int64) ([]*models.EthereumTransaction, error) {
+	o := orm.NewOrm()
+	var transfers []*models.EthereumTransaction
+	_, err := o.QueryTable(new(models.EthereumTransaction)).Filter("Kind", models.TransactionKindTransfer).Filter("Request", requestId).Exclude("Status", models.TransactionStatusFailed).OrderBy("Id").All(&transfers)
+	return transfers, err
+}
+
+// getUnpaidOutTransfers loads the transfers of a request which did not fail and were not
+// sent for a payout, in the order they were sent
+func getUnpaidOutTransfers(requestId int64, payouts map[int]*models.Payout) ([]*models.EthereumTransaction, error) {
+	transfers, err := getRequestTransfers(requestId)
+	if err != nil {
+		return nil, err
+	}
+	paidOut := make(map[int64]bool)
+	for _, payout := range payouts {
+		if payout.Transaction != nil {
+			paidOut[payout.Transaction.Id] = true
+		}
+	}
+	unpaidOut := make([]*models.EthereumTransaction, 0)
+	for _, transfer := range transfers {
+		if !paidOut[transfer.Id] {
+			unpaidOut = append(unpaidOut, transfer)
+		}
+	}
+	return unpaidOut, nil
+}
+
+// isRequestInspected tells whether the lacks of a request are known, its tranches may be paid
+func isRequestInspected(request *models.Request) bool {
+	switch RequestStatus(request) {
+	case models.RequestStatusInspected, models.RequestStatusFirstPaymentMade, models.RequestStatusFinalPaymentMade:
+		return true
+	}
+	return false
+}
+
+// mayTrancheBeDue tells whether a tranche of the rule may be paid in the state of the request.
+// Tranches on the amount of the previous year with a date do not depend on the lacks, they
+// are due by their date once the request is submitted. Tranches without a date wait for the
+// inspection.
+func mayTrancheBeDue(request *models.Request, rule string, due *time.Time) bool {
+	if isRequestInspected(request) {
+		return true
+	}
+	if rule != models.TrancheRulePreviousYear || due == nil {
+		return false
+	}
+	switch RequestStatus(request) {
+	case models.RequestStatusSubmitted, models.RequestStatusInspectorAssigned:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,452 @@
+package services
+
+import (
+	"math/big"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/scmo/apayment-backend/models"
+)
+
+// PaymentRunSubmittedError is returned when a payment run is submitted a second time
+type PaymentRunSubmittedError struct {
+	RunId int64
+}
+
+func (e *PaymentRunSubmittedError) Error() string {
+	return "Payment run " + strconv.FormatInt(e.RunId, 10) + " is submitted already"
+}
+
+// PaymentRunPlanningError is returned when a payment run is submitted before its dry run finished
+type PaymentRunPlanningError struct {
+	RunId int64
+}
+
+func (e *PaymentRunPlanningError) Error() string {
+	return "The dry run of payment run " + strconv.FormatInt(e.RunId, 10) + " is not finished yet"
+}
+
+// PlanPaymentRun stores a payment run, its dry run is planned in the background by
+// PlanPaymentRuns. The run counts the requests checked so far.
+func PlanPaymentRun(run *models.PaymentRun, preparer *models.User) error {
+	if run.Year == 0 {
+		return &RequestValidationError{Message: "A payment run needs a year"}
+	}
+	requests, err := paymentRunRequests(run)
+	if err != nil {
+		beego.Error("Failed to load requests of payment run: ", err)
+		return err
+	}
+	run.Preparer = preparer
+	run.Proposal = nil
+	run.Requests, run.Checked = len(requests), 0
+	run.Transfers, run.Skipped, run.NotDue, run.Errors = 0, 0, 0, 0
+	run.Total = "0"
+	run.Planned = time.Time{}
+	run.Submitted = time.Time{}
+	o := orm.NewOrm()
+	if _, err := o.Insert(run); err != nil {
+		beego.Error("Failed to store payment run: ", err)
+		return err
+	}
+	go PlanPaymentRuns()
+	return loadPaymentRun(run, false)
+}
+
+// planning is set while the dry runs are planned
+var planning int32
+
+// PlanPaymentRuns lists the transfers of the payment runs whose dry run is not finished. A
+// dry run interrupted by a restart is planned again.
+func PlanPaymentRuns() error {
+	if !atomic.CompareAndSwapInt32(&planning, 0, 1) {
+		// the dry runs are planned already
+		return nil
+	}
+	defer atomic.StoreInt32(&planning, 0)
+	o := orm.NewOrm()
+	var runs []*models.PaymentRun
+	if _, err := o.QueryTable(new(models.PaymentRun)).Filter("Planned__isnull", true).OrderBy("Id").All(&runs); err != nil {
+		beego.Error("Failed to load payment runs to plan: ", err)
+		return err
+	}
+	for _, run := range runs {
+		if err := planPaymentRun(run); err != nil {
+			beego.Error("Failed to plan payment run ", run.Id, ": ", err)
+		}
+	}
+	return nil
+}
+
+// planPaymentRun stores the dry run of a payment run: the next due tranche of every request
+// of the year, and of the canton when given, with its amount. Blocked requests are listed
+// as skipped with the reason, requests without a due tranche are counted. The counts are
+// stored after every request.
+func planPaymentRun(run *models.PaymentRun) error {
+	o := orm.NewOrm()
+	if _, err := o.QueryTable(new(models.PaymentRunItem)).Filter("Run", run.Id).Delete(); err != nil {
+		return err
+	}
+	requests, err := paymentRunRequests(run)
+	if err != nil {
+		return err
+	}
+	run.Requests, run.Checked = len(requests), 0
+	run.Transfers, run.Skipped, run.NotDue, run.Errors = 0, 0, 0, 0
+	total := new(big.Int)
+	for _, request := range requests {
+		item, err := planPaymentRunItem(run, request)
+		switch err.(type) {
+		case nil:
+			if item != nil {
+				run.Transfers++
+				amount, _ := new(big.Int).SetString(item.Amount, 10)
+				total.Add(total, amount)
+			}
+		case *TrancheNotDueError, *RequestTransitionError:
+			run.NotDue++
+		case *RequestValidationError, *ObjectionPendingError, *ReconciliationFlaggedError, *ReconciliationMissingError, *PayoutPendingError:
+			run.Skipped++
+			item = &models.PaymentRunItem{Status: models.PaymentRunItemStatusSkipped, Reason: err.Error()}
+		default:
+			// the request is not blocked, the node or the database failed
+			beego.Error("Failed to check request ", request.Id, " for payment run ", run.Id, ": ", err)
+			run.Errors++
+			item = &models.PaymentRunItem{Status: models.PaymentRunItemStatusError, Reason: err.Error()}
+		}
+		if item != nil {
+			item.Run = run
+			item.Request = request
+			if _, err := o.Insert(item); err != nil {
+				return err
+			}
+		}
+		run.Checked++
+		run.Total = total.String()
+		if _, err := o.Update(run, "Requests", "Checked", "Transfers", "Total", "Skipped", "NotDue", "Errors"); err != nil {
+			return err
+		}
+	}
+	run.Planned = time.Now()
+	if _, err := o.Update(run, "Planned"); err != nil {
+		return err
+	}
+	beego.Info("Payment run ", run.Id, " for ", run.Year, " lists ", run.Transfers, " transfers of ", run.Total, ", ", run.Skipped, " requests are blocked, ", run.Errors, " could not be checked")
+	return nil
+}
+
+// paymentRunRequests loads the requests of the year and the canton of a payment run from the
+// read model, with the requests made before the year and the canton were kept
+func paymentRunRequests(run *models.PaymentRun) ([]*models.Request, error) {
+	cond := orm.NewCondition()
+	cond = cond.AndCond(orm.NewCondition().And("Year", run.Year).Or("Year", 0))
+	if run.Canton != "" {
+		cond = cond.AndCond(orm.NewCondition().And("Canton", run.Canton).Or("Canton", ""))
+	}
+	o := orm.NewOrm()
+	var requests []*models.Request
+	_, err := o.QueryTable(new(models.Request)).SetCond(cond).RelatedSel("User").OrderBy("Id").All(&requests)
+	return requests, err
+}
+
+// planPaymentRunItem plans the next due tranche of a request, the errors tell why it is not
+// paid. Nothing is returned for a request made before the year and the canton were kept
+// which turns out to be of another year or canton.
+func planPaymentRunItem(run *models.PaymentRun, request *models.Request) (*models.PaymentRunItem, error) {
+	if !hasRequestContract(request) {
+		return nil, &TrancheNotDueError{Message: "Request " + strconv.FormatInt(request.Id, 10) + " has no contract"}
+	}
+	year, err := referenceYear(request)
+	if err != nil {
+		return nil, err
+	}
+	if year != run.Year {
+		return nil, nil
+	}
+	if request.Canton == "" {
+		if request.Canton = requestFarmCanton(request); request.Canton != "" {
+			o := orm.NewOrm()
+			if _, err := o.Update(request, "Canton"); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if run.Canton != "" && request.Canton != run.Canton {
+		return nil, nil
+	}
+	if isRequestProposed(request.Id) {
+		return nil, &RequestValidationError{Message: "Request is proposed for payment already"}
+	}
+	if request.Status == "" {
+		// the status is inferred from the payments, as when the tranche is paid
+		if request.Payments, err = getRequestPayments(request); err != nil {
+			return nil, err
+		}
+	}
+	_, next, err := nextDueTranche(request, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &models.PaymentRunItem{Tranche: next.Position, Name: next.Name, Amount: next.Amount.String(), Status: models.PaymentRunItemStatusPlanned}, nil
+}
+
+// SubmitPaymentRun proposes the transfers listed by the dry run of a payment run, they are
+// sent once the proposal is approved. A transfer which is not due anymore, or whose amount
+// changed since the dry run, is dropped.
+func SubmitPaymentRun(run *models.PaymentRun, submitter *models.User) error {
+	if run.Planned.IsZero() {
+		return &PaymentRunPlanningError{RunId: run.Id}
+	}
+	o := orm.NewOrm()
+	// only one submission proposes the transfers
+	num, err := o.QueryTable(new(models.PaymentRun)).Filter("Id", run.Id).Filter("Submitted__isnull", true).Update(orm.Params{"Submitted": time.Now()})
+	if err != nil {
+		return err
+	}
+	if num == 0 {
+		return &PaymentRunSubmittedError{RunId: run.Id}
+	}
+	if err := submitPaymentRun(run, submitter); err != nil {
+		if _, resetErr := o.QueryTable(new(models.PaymentRun)).Filter("Id", run.Id).Update(orm.Params{"Submitted": nil}); resetErr != nil {
+			beego.Error("Failed to reset submission of payment run ", run.Id, ": ", resetErr)
+		}
+		return err
+	}
+	return loadPaymentRun(run, true)
+}
+
+func submitPaymentRun(run *models.PaymentRun, submitter *models.User) error {
+	proposal := &models.PayoutProposal{Note: "Payment run " + strconv.FormatInt(run.Id, 10) + " for " + strconv.Itoa(run.Year)}
+	if run.Canton != "" {
+		proposal.Note += " in " + run.Canton
+	}
+	proposed := make(map[*models.PayoutProposalItem]*models.PaymentRunItem)
+	dropped := make([]*models.PaymentRunItem, 0)
+	for _, item := range run.Items {
+		if item.Status != models.PaymentRunItemStatusPlanned {
+			continue
+		}
+		proposalItem := &models.PayoutProposalItem{RequestId: item.RequestId, Tranche: item.Tranche}
+		err := proposeItem(proposalItem)
+		switch {
+		case err != nil:
+			item.Reason = err.Error()
+		case proposalItem.Amount != item.Amount:
+			item.Reason = item.Name + " changed from " + item.Amount + " to " + proposalItem.Amount + " since the dry run"
+		default:
+			proposal.Items = append(proposal.Items, proposalItem)
+			proposed[proposalItem] = item
+			continue
+		}
+		item.Status = models.PaymentRunItemStatusDropped
+		dropped = append(dropped, item)
+	}
+	if len(proposal.Items) == 0 {
+		return &RequestValidationError{Message: "None of the transfers of the payment run is due anymore"}
+	}
+	if err := storeProposal(proposal, submitter); err != nil {
+		return err
+	}
+
+	o := orm.NewOrm()
+	run.Proposal = proposal
+	if _, err := o.Update(run, "Proposal"); err != nil {
+		beego.Error("Failed to link payment run ", run.Id, " with payout proposal ", proposal.Id, ": ", err)
+		return err
+	}
+	for _, item := range dropped {
+		if _, err := o.Update(item, "Status", "Reason"); err != nil {
+			beego.Error("Failed to update item ", item.Id, " of payment run ", run.Id, ": ", err)
+		}
+	}
+	for proposalItem, item := range proposed {
+		item.ProposalItem = proposalItem
+		if _, err := o.Update(item, "ProposalItem"); err != nil {
+			beego.Error("Failed to update item ", item.Id, " of payment run ", run.Id, ": ", err)
+		}
+	}
+	beego.Info("Payment run ", run.Id, " proposed ", len(proposal.Items), " transfers of ", proposal.Total, " with payout proposal ", proposal.Id, ", ", len(dropped), " were dropped")
+	return nil
+}
+
+// GetPaymentRunReport sums up a payment run
+func GetPaymentRunReport(runId int64) (*models.PaymentRunReport, error) {
+	run, err := GetPaymentRunById(runId)
+	if err != nil {
+		return nil, err
+	}
+	report := &models.PaymentRunReport{
+		RunId:      run.Id,
+		Year:       run.Year,
+		Canton:     run.Canton,
+		Status:     run.Status,
+		PreparedBy: run.PreparedBy,
+		Decisions:  make([]*models.PayoutDecision, 0),
+		Created:    run.Created,
+		Transfers:  run.Transfers,
+		Total:      run.Total,
+		Skipped:    run.Skipped,
+		NotDue:     run.NotDue,
+		Progress:   run.Progress,
+		Failures:   make([]*models.PaymentRunItem, 0),
+		Blocked:    make([]*models.PaymentRunItem, 0),
+		Errors:     make([]*models.PaymentRunItem, 0),
+	}
+	report.Submitted = optionalTime(run.Submitted)
+	if run.Proposal != nil {
+		proposal, err := GetPayoutProposalById(run.Proposal.Id)
+		if err != nil {
+			return nil, err
+		}
+		report.Decisions = proposal.Decisions
+		report.Decided = optionalTime(proposal.Decided)
+		report.Finished = optionalTime(proposal.Executed)
+	}
+	for _, item := range run.Items {
+		switch item.Status {
+		case models.PaymentRunItemStatusSkipped:
+			report.Blocked = append(report.Blocked, item)
+		case models.PaymentRunItemStatusError:
+			report.Errors = append(report.Errors, item)
+		case models.PaymentRunItemStatusDropped, models.PayoutItemStatusFailed:
+			report.Failures = append(report.Failures, item)
+		}
+	}
+	return report, nil
+}
+
+// finishPaymentRun logs the summary of the payment run paid by an executed proposal
+func finishPaymentRun(proposal *models.PayoutProposal) {
+	o := orm.NewOrm()
+	var run models.PaymentRun
+	if err := o.QueryTable(new(models.PaymentRun)).Filter("Proposal", proposal.Id).One(&run); err != nil {
+		return
+	}
+	report, err := GetPaymentRunReport(run.Id)
+	if err != nil {
+		beego.Error("Failed to sum up payment run ", run.Id, ": ", err)
+		return
+	}
+	beego.Info("Payment run ", report.RunId, " completed: ", report.Progress.Sent, " transfers of ", report.Progress.SentTotal, " sent, ", report.Progress.Failed, " failed, ", len(report.Failures)-report.Progress.Failed, " dropped, ", report.Skipped, " requests blocked")
+}
+
+var paymentRunSortFields = listFields{"created": "Created", "year": "Year", "canton": "Canton"}
+
+// GetAllPaymentRuns loads a page of the payment runs without their items, filtered by year and canton
+func GetAllPaymentRuns(query *ListQuery) (*Page, error) {
+	o := orm.NewOrm()
+	var runs []*models.PaymentRun
+	page, err := paginate(o.QueryTable(new(models.PaymentRun)), query, map[string]listFilter{"year": filterInt("Year"), "canton": filterString("Canton")}, paymentRunSortFields, &runs)
+	if err != nil {
+		return nil, err
+	}
+	for _, run := range runs {
+		if err := loadPaymentRun(run, false); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+func GetPaymentRunById(runId int64) (*models.PaymentRun, error) {
+	run := models.PaymentRun{Id: runId}
+	if err := loadPaymentRun(&run, true); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// loadPaymentRun reads the payment run with its status and progress, which follow its
+// proposal, and optionally its items
+func loadPaymentRun(run *models.PaymentRun, withItems bool) error {
+	o := orm.NewOrm()
+	if err := o.Read(run); err != nil {
+		return err
+	}
+	if err := o.Read(run.Preparer); err != nil {
+		return err
+	}
+	run.PreparedBy = run.Preparer.Username
+	run.Status = models.PaymentRunStatusDryRun
+	if run.Planned.IsZero() {
+		run.Status = models.PaymentRunStatusPlanning
+	}
+	run.ProposalId = 0
+	run.Progress = nil
+	var proposalItems []*models.PayoutProposalItem
+	if run.Proposal != nil {
+		if err := o.Read(run.Proposal); err != nil {
+			return err
+		}
+		run.ProposalId = run.Proposal.Id
+		switch run.Proposal.Status {
+		case models.PayoutProposalStatusProposed:
+			run.Status = models.PaymentRunStatusProposed
+		case models.PayoutProposalStatusApproved:
+			run.Status = models.PaymentRunStatusRunning
+		case models.PayoutProposalStatusExecuted:
+			run.Status = models.PaymentRunStatusCompleted
+		case models.PayoutProposalStatusRejected:
+			run.Status = models.PaymentRunStatusRejected
+		}
+		if _, err := o.QueryTable(new(models.PayoutProposalItem)).Filter("Proposal", run.Proposal.Id).All(&proposalItems); err != nil {
+			return err
+		}
+		run.Progress = paymentRunProgress(proposalItems)
+	}
+	run.Items = nil
+	if !withItems {
+		return nil
+	}
+	run.Items = make([]*models.PaymentRunItem, 0)
+	if _, err := o.QueryTable(new(models.PaymentRunItem)).Filter("Run", run.Id).OrderBy("Id").All(&run.Items); err != nil {
+		return err
+	}
+	byId := make(map[int64]*models.PayoutProposalItem)
+	for _, proposalItem := range proposalItems {
+		byId[proposalItem.Id] = proposalItem
+	}
+	for _, item := range run.Items {
+		item.RequestId = item.Request.Id
+		if item.ProposalItem == nil {
+			continue
+		}
+		if proposalItem, ok := byId[item.ProposalItem.Id]; ok {
+			item.Status = proposalItem.Status
+			item.Attempts = proposalItem.Attempts
+			item.Transaction = proposalItem.Transaction
+			item.Reason = proposalItem.Message
+		}
+	}
+	return nil
+}
+
+func paymentRunProgress(items []*models.PayoutProposalItem) *models.PaymentRunProgress {
+	progress := &models.PaymentRunProgress{}
+	sent := new(big.Int)
+	for _, item := range items {
+		switch item.Status {
+		case models.PayoutItemStatusProposed:
+			progress.Proposed++
+		case models.PayoutItemStatusSent:
+			progress.Sent++
+			if amount, ok := new(big.Int).SetString(item.Amount, 10); ok {
+				sent.Add(sent, amount)
+			}
+		case models.PayoutItemStatusFailed:
+			progress.Failed++
+		}
+	}
+	progress.SentTotal = sent.String()
+	return progress
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/scmo/apayment-backend/models"
+)
+
+// defaultTranches pay a request like the contract: half the amount of the previous year
+// and the rest, both once the request is inspected
+var defaultTranches = []models.PaymentTranche{
+	{Position: 1, Name: "First Payment", Rule: models.TrancheRulePreviousYear, Percentage: 50},
+	{Position: 2, Name: "Second Payment", Rule: models.TrancheRuleRemainder},
+}
+
+// SetPaymentSchedule stores the tranches of a canton for a year, replacing the ones stored
+// before. It cannot be changed once requests were paid with it.
+func SetPaymentSchedule(schedule *models.PaymentSchedule, actor string) error {
+	if err := validatePaymentSchedule(schedule); err != nil {
+		return err
+	}
+	if hasScheduledPayments(schedule.Canton, schedule.Year) {
+		return &RequestValidationError{Message: "Requests of " + schedule.Canton + " were paid for " + strconv.Itoa(schedule.Year) + " already"}
+	}
+	schedule.Actor = actor
+	o := orm.NewOrm()
+	if err := o.Begin(); err != nil {
+		return err
+	}
+	stored := models.PaymentSchedule{Canton: schedule.Canton, Year: schedule.Year}
+	err := o.Read(&stored, "Canton", "Year")
+	switch err {
+	case nil:
+		schedule.Id = stored.Id
+		if _, err = o.Update(schedule, "Actor", "Modified"); err == nil {
+			_, err = o.QueryTable(new(models.PaymentTranche)).Filter("Schedule", stored.Id).Delete()
+		}
+	case orm.ErrNoRows:
+		_, err = o.Insert(schedule)
+	}
+	for _, tranche := range schedule.Tranches {
+		if err != nil {
+			break
+		}
+		tranche.Id = 0
+		tranche.Schedule = schedule
+		_, err = o.Insert(tranche)
+	}
+	if err != nil {
+		o.Rollback()
+		return err
+	}
+	return o.Commit()
+}
+
+// GetPaymentSchedule loads the schedule of a canton for a year, or the default schedule
+// with the two tranches of the contract
+func GetPaymentSchedule(canton string, year int) (*models.PaymentSchedule, error) {
+	o := orm.NewOrm()
+	schedule := models.PaymentSchedule{Canton: canton, Year: year}
+	err := o.Read(&schedule, "Canton", "Year")
+	if err == orm.ErrNoRows {
+		for i := range defaultTranches {
+			tranche := defaultTranches[i]
+			schedule.Tranches = append(schedule.Tranches, &tranche)
+		}
+		return &schedule, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if _, err := o.QueryTable(new(models.PaymentTranche)).Filter("Schedule", schedule.Id).OrderBy("Position").All(&schedule.Tranches); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// validatePaymentSchedule checks the rules and the dates of the tranches and numbers them
+// in the order given. The last tranche pays the remainder so that the whole amount is paid.
+func validatePaymentSchedule(schedule *models.PaymentSchedule) error {
+	if schedule.Canton == "" || schedule.Year == 0 {
+		return &RequestValidationError{Message: "A schedule needs a canton and a year"}
+	}
+	if len(schedule.Tranches) == 0 {
+		return &RequestValidationError{Message: "A schedule needs tranches"}
+	}
+	var previous *time.Time
+	for i, tranche := range schedule.Tranches {
+		tranche.Position = i + 1
+		name := "Tranche " + strconv.Itoa(tranche.Position)
+		if tranche.Name == "" {
+			tranche.Name = name
+		}
+		switch tranche.Rule {
+		case models.TrancheRulePreviousYear, models.TrancheRuleEntitlement:
+			if tranche.Percentage <= 0 || tranche.Percentage > 100 {
+				return &RequestValidationError{Message: name + " needs a percentage between 1 and 100"}
+			}
+		case models.TrancheRuleRemainder:
+			tranche.Percentage = 0
+		default:
+			return &RequestValidationError{Message: name + " has an unknown rule " + tranche.Rule}
+		}
+		if (i == len(schedule.Tranches)-1) != (tranche.Rule == models.TrancheRuleRemainder) {
+			return &RequestValidationError{Message: "The last tranche, and only the last, pays the remainder"}
+		}
+		if tranche.YearOffset < 0 || tranche.YearOffset > 1 {
+			return &RequestValidationError{Message: name + " is due in the year of the request or the following year"}
+		}
+		if tranche.DueMonth == 0 && tranche.DueDay == 0 && tranche.YearOffset == 0 {
+			// due once inspected
+			continue
+		}
+		due := trancheDue(tranche, schedule.Year)
+		if due == nil || due.Day() != tranche.DueDay {
+			return &RequestValidationError{Message: name + " has an invalid due date"}
+		}
+		if previous != nil && due.Before(*previous) {
+			return &RequestValidationError{Message: name + " is due before the tranche before"}
+		}
+		previous = due
+	}
+	return nil
+}
+
+// trancheDue returns the date a tranche is due in the year of a request, nil when it is
+// due once the request is inspected
+func trancheDue(tranche *models.PaymentTranche, year int) *time.Time {
+	if tranche.DueMonth < 1 || tranche.DueMonth > 12 {
+		return nil
+	}
+	oc, _ := time.LoadLocation("Europe/Zurich")
+	due := time.Date(year+tranche.YearOffset, time.Month(tranche.DueMonth), tranche.DueDay, 0, 0, 0, 0, oc)
+	return &due
+}
+
+// hasScheduledPayments tells whether a request of the canton and year was paid
+func hasScheduledPayments(canton string, year int) bool {
+	o := orm.NewOrm()
+	if o.QueryTable(new(models.Payout)).Filter("Request__Canton", canton).Filter("Request__Year", year).Exclude("Status", models.PayoutStatusFailed).Exist() {
+		return true
+	}
+	return o.QueryTable(new(models.EthereumTransaction)).Filter("Kind", models.TransactionKindTransfer).Filter("Request__Canton", canton).Filter("Request__Year", year).Exclude("Status", models.TransactionStatusFailed).Exist()
+}
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/scmo/apayment-backend/ethereum"
+	"github.com/scmo/apayment-backend/models"
+)
+
+// PayoutPendingError is returned when a tranche is paid while its payout is not settled yet
+type PayoutPendingError struct {
+	RequestId int64
+	Tranche   int
+}
+
+func (e *PayoutPendingError) Error() string {
+	return "Tranche " + strconv.Itoa(e.Tranche) + " of request " + strconv.FormatInt(e.RequestId, 10) + " is being paid already"
+}
+
+// IdempotencyKeyError is returned when an idempotency key is repeated for another payment
+type IdempotencyKeyError struct {
+	Message string
+}
+
+func (e *IdempotencyKeyError) Error() string {
+	return e.Message
+}
+
+// reservePayout stores the payout of a tranche before its transfer is sent. A tranche is
+// reserved once, it can be reserved again when its payout failed.
+func reservePayout(request *models.Request, tranche *models.PlannedTranche, actor string, key string) (*models.Payout, error) {
+	head, err := ethereum.HeadBlockNumber()
+	if err != nil {
+		return nil, err
+	}
+	payout := &models.Payout{
+		Request:        request,
+		Tranche:        tranche.Position,
+		Name:           tranche.Name,
+		Amount:         tranche.Amount.String(),
+		Status:         models.PayoutStatusReserved,
+		IdempotencyKey: key,
+		Block:          head,
+		Actor:          actor,
+	}
+	o := orm.NewOrm()
+	_, insertErr := o.Insert(payout)
+	if insertErr == nil {
+		return payout, nil
+	}
+	stored := models.Payout{Request: request, Tranche: tranche.Position}
+	if err := o.Read(&stored, "Request", "Tranche"); err != nil {
+		beego.Error("Failed to reserve payout: ", insertErr)
+		return nil, insertErr
+	}
+	if stored.Status != models.PayoutStatusFailed {
+		return nil, &PayoutPendingError{RequestId: request.Id, Tranche: tranche.Position}
+	}
+	// only one of concurrent payments takes over the failed payout
+	num, err := o.QueryTable(new(models.Payout)).Filter("Id", stored.Id).Filter("Status", models.PayoutStatusFailed).Update(orm.Params{
+		"Name":           payout.Name,
+		"Amount":         payout.Amount,
+		"Status":         payout.Status,
+		"IdempotencyKey": payout.IdempotencyKey,
+		"Transaction":    nil,
+		"Block":          payout.Block,
+		"Actor":          payout.Actor,
+		"Updated":        time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if num == 0 {
+		return nil, &PayoutPendingError{RequestId: request.Id, Tranche: tranche.Position}
+	}
+	payout.Id = stored.Id
+	return payout, nil
+}
+
+// linkPayout sets the transaction the tranche of a payout was sent with
+func linkPayout(payout *models.Payout, transaction *models.EthereumTransaction) error {
+	payout.Transaction = transaction
+	payout.Status = payoutStatus(transaction)
+	o := orm.NewOrm()
+	_, err := o.Update(payout, "Transaction", "Status", "Updated")
+	if err != nil {
+		beego.Error("Failed to link payout ", payout.Id, " with transaction ", transaction.Hash, ": ", err)
+	}
+	return err
+}
+
+// failPayout frees the tranche of a payout whose transfer was not sent
+func failPayout(payout *models.Payout) {
+	payout.Status = models.PayoutStatusFailed
+	o := orm.NewOrm()
+	if _, err := o.Update(payout, "Status", "Updated"); err != nil {
+		beego.Error("Failed to update payout ", payout.Id, ": ", err)
+	}
+}
+
+// followPayout updates the payout a transfer was sent for with the status of the transfer
+func followPayout(transaction *models.EthereumTransaction) {
+	o := orm.NewOrm()
+	_, err := o.QueryTable(new(models.Payout)).Filter("Transaction", transaction.Id).Update(orm.Params{"Status": payoutStatus(transaction), "Updated": time.Now()})
+	if err != nil {
+		beego.Error("Failed to follow payout of transaction ", transaction.Hash, ": ", err)
+	}
+}
+
+func payoutStatus(transaction *models.EthereumTransaction) string {
+	switch transaction.Status {
+	case models.TransactionStatusConfirmed:
+		return models.PayoutStatusConfirmed
+	case models.TransactionStatusFailed:
+		return models.PayoutStatusFailed
+	}
+	return models.PayoutStatusSent
+}
+
+// ReconcilePayouts matches the payouts whose transfer was sent but not stored with the
+// transfer events of the token. Payouts without a transfer after payoutReservationTimeout
+// minutes are failed, their tranches can be paid again, unless their actor still has
+// transactions pending.
+func ReconcilePayouts() error {
+	o := orm.NewOrm()
+	var payouts []*models.Payout
+	_, err := o.QueryTable(new(models.Payout)).Filter("Status", models.PayoutStatusReserved).RelatedSel("Request__User").All(&payouts)
+	if err != nil {
+		beego.Error("Failed to load reserved payouts: ", err)
+		return err
+	}
+	if len(payouts) == 0 {
+		return nil
+	}
+	timeout := time.Duration(beego.AppConfig.DefaultInt("payoutReservationTimeout", 60)) * time.Minute
+	for _, payout := range payouts {
+		// the transfer of a payout reserved just now may still be sent
+		if time.Since(payout.Updated) < time.Minute {
+			continue
+		}
+		transaction, err := findPayoutTransfer(payout)
+		switch {
+		case err != nil:
+			beego.Error("Failed to look for the transfer of payout ", payout.Id, ": ", err)
+		case transaction != nil:
+			beego.Info("Transfer ", transaction.Hash, " matched with payout ", payout.Id)
+			linkPayout(payout, transaction)
+		case time.Since(payout.Updated) > timeout:
+			pending, err := hasPendingTransactions(payout.Actor)
+			switch {
+			case err != nil:
+				beego.Error("Failed to read the pending transactions of ", payout.Actor, ": ", err)
+			case pending:
+				// the transfer may still be mined, paying the tranche again would pay it twice
+				beego.Warning("No transfer found for payout ", payout.Id, ", ", payout.Actor, " has transactions pending")
+			default:
+				beego.Warning("No transfer found for payout ", payout.Id, ", the tranche can be paid again")
+				failPayout(payout)
+			}
+		}
+	}
+	return nil
+}
+
+// hasPendingTransactions tells whether transactions of the account are waiting in the
+// transaction pool
+func hasPendingTransactions(account string) (bool, error) {
+	client := ethereum.GetEthereumController().Client
+	address := common.HexToAddress(account)
+	pending, err := client.PendingNonceAt(context.Background(), address)
+	if err != nil {
+		return false, err
+	}
+	mined, err := client.NonceAt(context.Background(), address, nil)
+	if err != nil {
+		return false, err
+	}
+	return pending > mined, nil
+}
+
+// findPayoutTransfer looks for a transfer of the amount of a payout from its actor to the
+// farmer for the contract of its request, mined since the payout was reserved and not paying
+// another payout. A transfer which is not tracked yet is stored as mined.
+func findPayoutTransfer(payout *models.Payout) (*models.EthereumTransaction, error) {
+	amount, ok := new(big.Int).SetString(payout.Amount, 10)
+	if !ok {
+		return nil, errors.New("Invalid amount " + payout.Amount)
+	}
+	from := common.HexToAddress(payout.Actor)
+	to := common.HexToAddress(payout.Request.User.EtherumAddress)
+	transfers, err := filterRequestTransfers(&bind.FilterOpts{Start: payout.Block}, []common.Address{from}, []common.Address{to}, payout.Request.Address)
+	if err != nil {
+		return nil, err
+	}
+	o := orm.NewOrm()
+	for _, transfer := range transfers {
+		if transfer.Value.Cmp(amount) != 0 {
+			continue
+		}
+		transaction := models.EthereumTransaction{Hash: transfer.Raw.TxHash.String()}
+		err := o.Read(&transaction, "Hash")
+		if err == nil {
+			if o.QueryTable(new(models.Payout)).Filter("Transaction", transaction.Id).Exist() {
+				continue
+			}
+			return &transaction, nil
+		} else if err != orm.ErrNoRows {
+			return nil, err
+		}
+		transaction = models.EthereumTransaction{
+			Hash:    transfer.Raw.TxHash.String(),
+			Kind:    models.TransactionKindTransfer,
+			From:    payout.Actor,
+			To:      transfer.Raw.Address.String(),
+			Value:   "0",
+			Amount:  payout.Amount,
+			Request: payout.Request,
+			Account: payout.Request.User.EtherumAddress,
+			Detail:  payout.Name,
+			Status:  models.TransactionStatusMined,
+			Block:   transfer.Raw.BlockNumber,
+		}
+		if _, err := o.Insert(&transaction); err != nil {
+			return nil, err
+		}
+		return &transaction, nil
+	}
+	return nil, nil
+}
+
+// getRequestPayouts loads the payouts of a request which did not fail with their transfers
+// by the position of their tranche
+func getRequestPayouts(requestId int64) (map[int]*models.Payout, error) {
+	o := orm.NewOrm()
+	var payouts []*models.Payout
+	_, err := o.QueryTable(new(models.Payout)).Filter("Request", requestId).Exclude("Status", models.PayoutStatusFailed).All(&payouts)
+	if err != nil {
+		return nil, err
+	}
+	byTranche := make(map[int]*models.Payout)
+	for _, payout := range payouts {
+		if payout.Transaction != nil {
+			if err := o.Read(payout.Transaction); err != nil {
+				return nil, err
+			}
+		}
+		byTranche[payout.Tranche] = payout
+	}
+	return byTranche, nil
+}
@@ -0,0 +1,398 @@
+package services
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/scmo/apayment-backend/models"
+)
+
+// FourEyesError is returned when an employee decides on a payout proposal they prepared or
+// decided on before
+type FourEyesError struct {
+	Message string
+}
+
+func (e *FourEyesError) Error() string {
+	return e.Message
+}
+
+// PayoutProposalDecidedError is returned when a proposal is decided on after it was approved or rejected
+type PayoutProposalDecidedError struct {
+	Status string
+}
+
+func (e *PayoutProposalDecidedError) Error() string {
+	return "Payout proposal is " + e.Status + " already"
+}
+
+// PrepareProposal stores the tranches of a batch of requests to be paid once the proposal
+// is approved. Every request is paid its next due tranche with the amount planned now.
+// A proposal stored before with the idempotency key of the preparer is returned as it is.
+func PrepareProposal(proposal *models.PayoutProposal, preparer *models.User) error {
+	o := orm.NewOrm()
+	if proposal.IdempotencyKey != "" {
+		var stored models.PayoutProposal
+		err := o.QueryTable(new(models.PayoutProposal)).Filter("IdempotencyKey", proposal.IdempotencyKey).Filter("Preparer", preparer.Id).One(&stored)
+		if err == nil {
+			if err := loadPayoutProposal(&stored); err != nil {
+				return err
+			}
+			if !sameProposalItems(&stored, proposal) {
+				return &IdempotencyKeyError{Message: "Idempotency-Key " + proposal.IdempotencyKey + " was used for another payment"}
+			}
+			*proposal = stored
+			return nil
+		} else if err != orm.ErrNoRows {
+			return err
+		}
+	}
+	if len(proposal.Items) == 0 {
+		return &RequestValidationError{Message: "A payout proposal needs at least one request"}
+	}
+	proposed := make(map[int64]bool)
+	for _, item := range proposal.Items {
+		if proposed[item.RequestId] {
+			return &RequestValidationError{Message: "Request " + strconv.FormatInt(item.RequestId, 10) + " is listed twice"}
+		}
+		proposed[item.RequestId] = true
+		if err := proposeItem(item); err != nil {
+			return err
+		}
+	}
+	return storeProposal(proposal, preparer)
+}
+
+// proposeItem plans the next due tranche of the request of an item
+func proposeItem(item *models.PayoutProposalItem) error {
+	name := "Request " + strconv.FormatInt(item.RequestId, 10)
+	request, err := loadPayableRequest(item.RequestId)
+	if err == orm.ErrNoRows {
+		return &RequestValidationError{Message: name + " not found"}
+	} else if err != nil {
+		return err
+	}
+	if isRequestProposed(request.Id) {
+		return &TrancheNotDueError{Message: name + " is proposed for payment already"}
+	}
+	_, next, err := nextDueTranche(request, item.Tranche)
+	if err != nil {
+		return err
+	}
+	item.Request = request
+	item.Tranche = next.Position
+	item.Name = next.Name
+	item.Amount = next.Amount.String()
+	item.Status = models.PayoutItemStatusProposed
+	item.Attempts = 0
+	item.Transaction = ""
+	item.Message = ""
+	return nil
+}
+
+// storeProposal stores a proposal with its planned items, the approvals needed follow from their amounts
+func storeProposal(proposal *models.PayoutProposal, preparer *models.User) error {
+	total := new(big.Int)
+	for _, item := range proposal.Items {
+		if amount, ok := new(big.Int).SetString(item.Amount, 10); ok {
+			total.Add(total, amount)
+		}
+	}
+	proposal.Total = total.String()
+	proposal.Required = requiredApprovals(proposal.Items, total)
+	proposal.Status = models.PayoutProposalStatusProposed
+	proposal.Preparer = preparer
+	proposal.Decided = time.Time{}
+	proposal.Executed = time.Time{}
+
+	o := orm.NewOrm()
+	if err := o.Begin(); err != nil {
+		return err
+	}
+	_, err := o.Insert(proposal)
+	for _, item := range proposal.Items {
+		if err != nil {
+			break
+		}
+		item.Proposal = proposal
+		_, err = o.Insert(item)
+	}
+	if err != nil {
+		o.Rollback()
+		beego.Error("Failed to prepare payout proposal: ", err)
+		return err
+	}
+	if err := o.Commit(); err != nil {
+		return err
+	}
+	return loadPayoutProposal(proposal)
+}
+
+// DecideProposal records the approval or rejection of a proposal by an employee other than
+// the one who prepared it. The transfers are sent in the background after the last approval
+// needed.
+func DecideProposal(proposal *models.PayoutProposal, decision *models.PayoutDecision, user *models.User) error {
+	switch decision.Decision {
+	case models.PayoutDecisionApprove:
+	case models.PayoutDecisionReject:
+		if strings.TrimSpace(decision.Reason) == "" {
+			return &RequestValidationError{Message: "A rejection needs a reason"}
+		}
+	default:
+		return &RequestValidationError{Message: "Unknown decision " + decision.Decision}
+	}
+	if proposal.Status != models.PayoutProposalStatusProposed {
+		return &PayoutProposalDecidedError{Status: proposal.Status}
+	}
+	if proposal.Preparer.Id == user.Id {
+		return &FourEyesError{Message: "A payout proposal is decided on by another employee than the one who prepared it"}
+	}
+	o := orm.NewOrm()
+	decision.Id = 0
+	decision.Proposal = proposal
+	decision.User = user
+	if _, err := o.Insert(decision); err != nil {
+		if o.QueryTable(new(models.PayoutDecision)).Filter("Proposal", proposal.Id).Filter("User", user.Id).Exist() {
+			return &FourEyesError{Message: "You decided on the payout proposal already"}
+		}
+		beego.Error("Failed to store decision on payout proposal ", proposal.Id, ": ", err)
+		return err
+	}
+
+	status := models.PayoutProposalStatusRejected
+	if decision.Decision == models.PayoutDecisionApprove {
+		approvals, err := o.QueryTable(new(models.PayoutDecision)).Filter("Proposal", proposal.Id).Filter("Decision", models.PayoutDecisionApprove).Count()
+		if err != nil {
+			return err
+		}
+		if int(approvals) < proposal.Required {
+			return loadPayoutProposal(proposal)
+		}
+		status = models.PayoutProposalStatusApproved
+	}
+	num, err := o.QueryTable(new(models.PayoutProposal)).Filter("Id", proposal.Id).Filter("Status", models.PayoutProposalStatusProposed).Update(orm.Params{
+		"Status":  status,
+		"Decided": time.Now(),
+	})
+	if err != nil {
+		beego.Error("Failed to decide payout proposal ", proposal.Id, ": ", err)
+		return err
+	}
+	if err := loadPayoutProposal(proposal); err != nil {
+		return err
+	}
+	if num == 0 {
+		// decided by another employee at the same time
+		if proposal.Status == status {
+			return nil
+		}
+		o.Delete(decision)
+		return &PayoutProposalDecidedError{Status: proposal.Status}
+	}
+	if status == models.PayoutProposalStatusApproved {
+		go ExecuteApprovedProposals()
+	}
+	return nil
+}
+
+// executing is set while the approved proposals are executed
+var executing int32
+
+// ExecuteApprovedProposals sends the transfers of the approved proposals, at most one every
+// payoutThrottle milliseconds. A transfer which could not be sent is retried until
+// payoutAttempts attempts failed, a tranche which cannot be paid is not retried.
+func ExecuteApprovedProposals() error {
+	if !atomic.CompareAndSwapInt32(&executing, 0, 1) {
+		// the proposals are executed already
+		return nil
+	}
+	defer atomic.StoreInt32(&executing, 0)
+	o := orm.NewOrm()
+	var proposals []*models.PayoutProposal
+	if _, err := o.QueryTable(new(models.PayoutProposal)).Filter("Status", models.PayoutProposalStatusApproved).OrderBy("Id").All(&proposals); err != nil {
+		beego.Error("Failed to load approved payout proposals: ", err)
+		return err
+	}
+	for _, proposal := range proposals {
+		if err := loadPayoutProposal(proposal); err != nil {
+			beego.Error("Failed to load payout proposal ", proposal.Id, ": ", err)
+			continue
+		}
+		executeProposal(proposal)
+	}
+	return nil
+}
+
+// executeProposal sends the transfers of an approved proposal from the account of the
+// employee who prepared it. A tranche which is not due anymore or whose amount changed
+// since it was proposed is not sent. The proposal is executed once no item is left to send.
+func executeProposal(proposal *models.PayoutProposal) {
+	o := orm.NewOrm()
+	throttle := time.Duration(beego.AppConfig.DefaultInt("payoutThrottle", 200)) * time.Millisecond
+	attempts := beego.AppConfig.DefaultInt("payoutAttempts", 3)
+	left := 0
+	for _, item := range proposal.Items {
+		if item.Status != models.PayoutItemStatusProposed {
+			continue
+		}
+		err := executeProposalItem(proposal, item)
+		item.Attempts++
+		switch {
+		case item.Transaction != "":
+			item.Status = models.PayoutItemStatusSent
+		case isPermanentPayoutError(err) || item.Attempts >= attempts:
+			item.Status = models.PayoutItemStatusFailed
+		default:
+			// retried with the next execution
+			left++
+		}
+		if err != nil {
+			beego.Error("Failed to pay tranche ", item.Tranche, " of request ", item.RequestId, " (attempt ", item.Attempts, "): ", err)
+			item.Message = err.Error()
+		}
+		if _, err := o.Update(item, "Status", "Attempts", "Transaction", "Message"); err != nil {
+			beego.Error("Failed to update item ", item.Id, " of payout proposal ", proposal.Id, ": ", err)
+		}
+		time.Sleep(throttle)
+	}
+	if left > 0 {
+		return
+	}
+	proposal.Status = models.PayoutProposalStatusExecuted
+	proposal.Executed = time.Now()
+	if _, err := o.Update(proposal, "Status", "Executed"); err != nil {
+		beego.Error("Failed to update payout proposal ", proposal.Id, ": ", err)
+	}
+	finishPaymentRun(proposal)
+}
+
+// isPermanentPayoutError tells whether a tranche cannot be paid, so that retrying it is pointless
+func isPermanentPayoutError(err error) bool {
+	switch err.(type) {
+	case *RequestValidationError, *TrancheNotDueError, *ObjectionPendingError, *ReconciliationFlaggedError, *ReconciliationMissingError, *RequestTransitionError, *PayoutPendingError:
+		return true
+	}
+	return false
+}
+
+func executeProposalItem(proposal *models.PayoutProposal, item *models.PayoutProposalItem) error {
+	request, err := loadPayableRequest(item.RequestId)
+	if err != nil {
+		return err
+	}
+	plan, next, err := nextDueTranche(request, item.Tranche)
+	if err != nil {
+		return err
+	}
+	if next.Amount.String() != item.Amount {
+		return &RequestValidationError{Message: next.Name + " changed from " + item.Amount + " to " + next.Amount.String() + " since it was proposed"}
+	}
+	err = payTranche(request, plan, next, proposal.Preparer, "proposal-"+strconv.FormatInt(proposal.Id, 10)+"-"+strconv.FormatInt(item.Id, 10))
+	item.Transaction = next.Transaction
+	return err
+}
+
+// requiredApprovals is two when a tranche is above payoutApprovalThreshold or the total
+// above payoutBatchApprovalThreshold, one otherwise
+func requiredApprovals(items []*models.PayoutProposalItem, total *big.Int) int {
+	if total.Cmp(approvalThreshold("payoutBatchApprovalThreshold", "1000000")) > 0 {
+		return 2
+	}
+	threshold := approvalThreshold("payoutApprovalThreshold", "100000")
+	for _, item := range items {
+		amount, _ := new(big.Int).SetString(item.Amount, 10)
+		if amount == nil || amount.Cmp(threshold) > 0 {
+			return 2
+		}
+	}
+	return 1
+}
+
+func approvalThreshold(name string, fallback string) *big.Int {
+	threshold, ok := new(big.Int).SetString(beego.AppConfig.DefaultString(name, fallback), 10)
+	if !ok {
+		// every payout is approved twice
+		beego.Error("Invalid ", name, ": ", beego.AppConfig.String(name))
+		return new(big.Int)
+	}
+	return threshold
+}
+
+// isRequestProposed tells whether a tranche of the request is part of a proposal which is
+// not decided or being paid
+func isRequestProposed(requestId int64) bool {
+	o := orm.NewOrm()
+	return o.QueryTable(new(models.PayoutProposalItem)).Filter("Request", requestId).Filter("Status", models.PayoutItemStatusProposed).Filter("Proposal__Status__in", models.PayoutProposalStatusProposed, models.PayoutProposalStatusApproved).Exist()
+}
+
+// sameProposalItems tells whether a proposal was prepared for the requests and tranches given
+func sameProposalItems(stored *models.PayoutProposal, proposal *models.PayoutProposal) bool {
+	if len(stored.Items) != len(proposal.Items) {
+		return false
+	}
+	for i, item := range proposal.Items {
+		if item.RequestId != stored.Items[i].RequestId || (item.Tranche != 0 && item.Tranche != stored.Items[i].Tranche) {
+			return false
+		}
+	}
+	return true
+}
+
+var payoutProposalSortFields = listFields{"created": "Created", "status": "Status", "total": "Total"}
+
+// GetAllPayoutProposals loads a page of the proposals with their items and decisions,
+// filtered by status
+func GetAllPayoutProposals(query *ListQuery) (*Page, error) {
+	o := orm.NewOrm()
+	var proposals []*models.PayoutProposal
+	page, err := paginate(o.QueryTable(new(models.PayoutProposal)), query, map[string]listFilter{"status": filterString("Status")}, payoutProposalSortFields, &proposals)
+	if err != nil {
+		return nil, err
+	}
+	for _, proposal := range proposals {
+		if err := loadPayoutProposal(proposal); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+func GetPayoutProposalById(proposalId int64) (*models.PayoutProposal, error) {
+	proposal := models.PayoutProposal{Id: proposalId}
+	if err := loadPayoutProposal(&proposal); err != nil {
+		return nil, err
+	}
+	return &proposal, nil
+}
+
+// loadPayoutProposal reads the proposal with its items and who prepared and decided on it
+func loadPayoutProposal(proposal *models.PayoutProposal) error {
+	o := orm.NewOrm()
+	if err := o.Read(proposal); err != nil {
+		return err
+	}
+	if err := o.Read(proposal.Preparer); err != nil {
+		return err
+	}
+	proposal.PreparedBy = proposal.Preparer.Username
+	proposal.Items = make([]*models.PayoutProposalItem, 0)
+	if _, err := o.QueryTable(new(models.PayoutProposalItem)).Filter("Proposal", proposal.Id).OrderBy("Id").All(&proposal.Items); err != nil {
+		return err
+	}
+	for _, item := range proposal.Items {
+		item.RequestId = item.Request.Id
+	}
+	proposal.Decisions = make([]*models.PayoutDecision, 0)
+	if _, err := o.QueryTable(new(models.PayoutDecision)).Filter("Proposal", proposal.Id).RelatedSel("User").OrderBy("Id").All(&proposal.Decisions); err != nil {
+		return err
+	}
+	for _, decision := range proposal.Decisions {
+		decision.DecidedBy = decision.User.Username
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+// Package pdf writes simple text documents as PDF. It uses the standard Helvetica
+// fonts, which every viewer has, and writes no timestamps: the same content always
+// gives the same bytes.
+package pdf
+
+import (
+	"bytes"
+	"crypto/md5"
+	"fmt"
+	"strings"
+)
+
+// A4 in points, with the margins of the text
+const (
+	pageWidth    = 595.28
+	pageHeight   = 841.89
+	margin       = 56.0
+	footerHeight = 24.0
+	valueColumn  = 200.0 // of the fields, from the margin
+)
+
+type font struct {
+	name   string // resource name in the page
+	widths *[95]int
+}
+
+var (
+	regular = font{name: "F1", widths: &helveticaWidths}
+	bold    = font{name: "F2", widths: &helveticaBoldWidths}
+)
+
+// Document is a PDF document written top to bottom, a new page is started when the
+// current one is full
+type Document struct {
+	title string
+	pages []*bytes.Buffer
+	y     float64 // baseline of the next line, from the bottom of the page
+}
+
+func New(title string) *Document {
+	d := &Document{title: title}
+	d.newPage()
+	return d
+}
+
+// Heading writes a title in bold
+func (d *Document) Heading(text string) {
+	d.Space(6)
+	d.wrapped(bold, 14, 0, pageWidth-2*margin, text, false)
+	d.Space(4)
+}
+
+// Subheading writes the title of a section in bold
+func (d *Document) Subheading(text string) {
+	d.Space(8)
+	d.wrapped(bold, 11, 0, pageWidth-2*margin, text, false)
+	d.Space(2)
+}
+
+// Paragraph writes text, wrapped at the margin
+func (d *Document) Paragraph(text string) {
+	d.wrapped(regular, 10, 0, pageWidth-2*margin, text, false)
+}
+
+// Field writes a label and its value next to it, the value is wrapped
+func (d *Document) Field(label string, value string) {
+	d.ensure(14)
+	d.text(bold, 10, margin, label)
+	d.wrapped(regular, 10, valueColumn, pageWidth-2*margin-valueColumn, value, true)
+}
+
+// Row writes text indented from the margin and a value aligned to the right margin.
+// The text is wrapped before the value.
+func (d *Document) Row(indent float64, text string, value string, strong bool) {
+	f := regular
+	if strong {
+		f = bold
+	}
+	d.ensure(14)
+	valueWidth := width(f, 10, value)
+	d.text(f, 10, pageWidth-margin-valueWidth, value)
+	d.wrapped(f, 10, indent, pageWidth-2*margin-indent-valueWidth-12, text, true)
+}
+
+// Space leaves vertical space
+func (d *Document) Space(height float64) {
+	d.y -= height
+}
+
+// Bytes returns the document with the footer and the page number on every page
+func (d *Document) Bytes(footer string) []byte {
+	var out bytes.Buffer
+	offsets := make([]int, 0)
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 1 catalog, 2 pages, 3 and 4 fonts, 5 info, then a page and its content each
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title (%s) /Producer (aPayment) >>", escape(d.title)))
+	for i, page := range d.pages {
+		content := bytes.NewBuffer(page.Bytes())
+		number := fmt.Sprintf("Page %d of %d", i+1, len(d.pages))
+		writeText(content, regular, 8, margin, footerHeight, footer)
+		writeText(content, regular, 8, pageWidth-margin-width(regular, 8, number), footerHeight, number)
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, 7+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	// the id is derived from the content instead of the time
+	id := fmt.Sprintf("%x", md5.Sum(out.Bytes()))
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R /ID [<%s> <%s>] >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, id, id, xref)
+	return out.Bytes()
+}
+
+func (d *Document) newPage() {
+	d.pages = append(d.pages, new(bytes.Buffer))
+	d.y = pageHeight - margin
+}
+
+// ensure starts a new page unless there is room for a line of the given height
+func (d *Document) ensure(height float64) {
+	if d.y-height < margin+footerHeight {
+		d.newPage()
+	}
+	d.y -= height
+}
+
+func (d *Document) text(f font, size float64, x float64, text string) {
+	writeText(d.pages[len(d.pages)-1], f, size, x, d.y, text)
+}
+
+// wrapped writes text on new lines, breaking it at spaces to fit the width. With
+// sameLine the first line goes next to what was written on the current line.
+func (d *Document) wrapped(f font, size float64, indent float64, maxWidth float64, text string, sameLine bool) {
+	lineHeight := size * 1.4
+	line := ""
+	flush := func() {
+		if !sameLine {
+			d.ensure(lineHeight)
+		}
+		sameLine = false
+		d.text(f, size, margin+indent, line)
+	}
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if line != "" && width(f, size, candidate) > maxWidth {
+			flush()
+			line = word
+			continue
+		}
+		line = candidate
+	}
+	if line != "" {
+		flush()
+	}
+}
+
+func writeText(buffer *bytes.Buffer, f font, size float64, x float64, y float64, text string) {
+	if text == "" {
+		return
+	}
+	fmt.Fprintf(buffer, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", f.name, size, x, y, escape(text))
+}
+
+// width measures text in the given font and size
+func width(f font, size float64, text string) float64 {
+	total := 0
+	for _, c := range encode(text) {
+		switch {
+		case c >= 32 && c <= 126:
+			total += f.widths[c-32]
+		case c >= 0xc0 && c < 0xe0:
+			total += 722 // capital letters with accents
+		default:
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// escape encodes text as WinAnsi in a PDF string
+func escape(text string) string {
+	var out strings.Builder
+	for _, c := range encode(text) {
+		switch c {
+		case '(', ')', '\\':
+			out.WriteByte('\\')
+			out.WriteByte(c)
+		default:
+			if c < 32 || c > 126 {
+				fmt.Fprintf(&out, "\\%03o", c)
+			} else {
+				out.WriteByte(c)
+			}
+		}
+	}
+	return out.String()
+}
+
+// winAnsi maps the characters of Windows-1252 outside of Latin-1
+var winAnsi = map[rune]byte{'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97}
+
+func encode(text string) []byte {
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r == '\t' || r == '\n' || r == '\r':
+			out = append(out, ' ')
+		case r < 0x80 || (r >= 0xa0 && r <= 0xff):
+			out = append(out, byte(r))
+		case winAnsi[r] != 0:
+			out = append(out, winAnsi[r])
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+// widths of the characters 32 to 126, from the font metrics of Helvetica
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
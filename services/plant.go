@@ -1,6 +1,7 @@
 package services
 
 import (
+	"github.com/astaxie/beego"
 	"github.com/astaxie/beego/orm"
 	"github.com/scmo/apayment-backend/models"
 )
@@ -10,3 +11,31 @@ func CreatePlant(r *models.Plant) error {
 	_, err := o.Insert(r)
 	return err
 }
+
+// SetFarmPlant stores the plant of a farmer, replacing the one stored before
+func SetFarmPlant(plant *models.Plant) error {
+	o := orm.NewOrm()
+	if stored := farmPlant(plant.User); stored != nil {
+		plant.Id = stored.Id
+		_, err := o.Update(plant)
+		return err
+	}
+	return CreatePlant(plant)
+}
+
+// farmPlant returns the plant of a farmer, nil if none is known
+func farmPlant(user *models.User) *models.Plant {
+	if user == nil {
+		return nil
+	}
+	o := orm.NewOrm()
+	var plant models.Plant
+	err := o.QueryTable(new(models.Plant)).Filter("User", user.Id).OrderBy("Id").One(&plant)
+	if err != nil {
+		if err != orm.ErrNoRows {
+			beego.Error("Failed to load plant of user ", user.Id, ": ", err)
+		}
+		return nil
+	}
+	return &plant
+}
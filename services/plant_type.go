@@ -11,11 +11,10 @@ func CreatePlantType(pt *models.PlantType) error {
 	return err
 }
 
-func GetAllPlantTypes() []*models.PlantType {
+func GetAllPlantTypes(query *ListQuery) (*Page, error) {
 	o := orm.NewOrm()
 	var plantTypes []*models.PlantType
-	o.QueryTable(new(models.PlantType)).All(&plantTypes)
-	return plantTypes
+	return paginate(o.QueryTable(new(models.PlantType)), query, nil, codeSortFields, &plantTypes)
 }
 
 func CountPlantTypes() (int64, error) {
@@ -12,15 +12,25 @@ func CreatePointGroup(pc *models.PointGroup) error {
 	return err
 }
 
-func GetAllPointGroups() []*models.PointGroup {
+var pointGroupSortFields = listFields{"id": "Id", "pointGroupCode": "PointGroupCode"}
+
+var pointGroupFilters = map[string]listFilter{
+	"controlCategory": filterInt("ControlCategory"),
+	"pointGroupCode":  filterInt("PointGroupCode"),
+}
+
+func GetAllPointGroups(query *ListQuery) (*Page, error) {
 	o := orm.NewOrm()
 	var pointGroups []*models.PointGroup
-	o.QueryTable(new(models.PointGroup)).All(&pointGroups)
+	page, err := paginate(o.QueryTable(new(models.PointGroup)), query, pointGroupFilters, pointGroupSortFields, &pointGroups)
+	if err != nil {
+		return nil, err
+	}
 	for _, pointGroup := range pointGroups {
 		o.LoadRelated(pointGroup, "ControlPoints")
 	}
 
-	return pointGroups
+	return page, nil
 }
 
 func GetAllPointGroupById(_id int64) (*models.PointGroup, error) {
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/scmo/apayment-backend/ethereum"
+	"github.com/scmo/apayment-backend/models"
+)
+
+// SetPreviousYearAmount stores the amount an admin sets for the previous year of a farmer
+func SetPreviousYearAmount(amount *models.PreviousYearAmount) error {
+	if _, ok := new(big.Int).SetString(amount.Amount, 10); !ok {
+		return errors.New("Invalid amount " + amount.Amount)
+	}
+	// requests are made for the current year at the latest, their previous year is past
+	if amount.Year <= 0 || amount.Year >= currentYear() {
+		return errors.New("Invalid year " + strconv.Itoa(amount.Year) + ", the amount is set for a past year")
+	}
+	o := orm.NewOrm()
+	stored := models.PreviousYearAmount{User: amount.User, Year: amount.Year}
+	if _, _, err := o.ReadOrCreate(&stored, "User", "Year"); err != nil {
+		beego.Error("Failed to store previous year amount: ", err)
+		return err
+	}
+	amount.Id = stored.Id
+	_, err := o.Update(amount, "Amount", "Actor", "Modified")
+	return err
+}
+
+// getRequestAmountFromPreviousYear returns the tokens paid for the farmer's request of
+// the year before the request year. An amount set by an admin takes precedence.
+func getRequestAmountFromPreviousYear(user *models.User, requestYear int) (*big.Int, error) {
+	year := requestYear - 1
+
+	o := orm.NewOrm()
+	override := models.PreviousYearAmount{User: user, Year: year}
+	err := o.Read(&override, "User", "Year")
+	if err == nil {
+		amount, ok := new(big.Int).SetString(override.Amount, 10)
+		if !ok {
+			return nil, errors.New("Invalid previous year amount of user " + user.Username)
+		}
+		return amount, nil
+	} else if err != orm.ErrNoRows {
+		return nil, err
+	}
+
+	request, err := getRequestOfYear(user, year)
+	if err == orm.ErrNoRows {
+		return big.NewInt(0), nil
+	} else if err != nil {
+		return nil, err
+	}
+	return getPaidAmount(request, user)
+}
+
+// getRequestOfYear finds the request of the farmer for the given year
+func getRequestOfYear(user *models.User, year int) (*models.Request, error) {
+	o := orm.NewOrm()
+	requests := o.QueryTable(new(models.Request)).Filter("User", user.Id).Exclude("Status__in", models.RequestStatusDraft, models.RequestStatusWithdrawn, models.RequestStatusRejected)
+	var request models.Request
+	err := requests.Filter("Year", year).One(&request)
+	if err != orm.ErrNoRows {
+		return &request, err
+	}
+
+	// requests created before the year was stored and not synced yet are dated by their contract
+	var legacy []*models.Request
+	if _, err := requests.Filter("Year", 0).All(&legacy); err != nil {
+		return nil, err
+	}
+	for _, request := range legacy {
+		if err := backfillRequestYear(request); err != nil {
+			return nil, err
+		}
+		if request.Year == year {
+			return request, nil
+		}
+	}
+	return nil, orm.ErrNoRows
+}
+
+// getPaidAmount sums the confirmed token transfers made for a request to the farmer.
+// Transfers which were not tracked, like those sent before transfers were stored, are
+// read from the transfer events of the token.
+func getPaidAmount(request *models.Request, farmer *models.User) (*big.Int, error) {
+	o := orm.NewOrm()
+	var transfers []*models.EthereumTransaction
+	_, err := o.QueryTable(new(models.EthereumTransaction)).Filter("Kind", models.TransactionKindTransfer).Filter("Request", request.Id).Filter("Status", models.TransactionStatusConfirmed).All(&transfers)
+	if err != nil {
+		return nil, err
+	}
+	sum := big.NewInt(0)
+	counted := make(map[string]bool)
+	for _, transfer := range transfers {
+		amount, ok := new(big.Int).SetString(transfer.Amount, 10)
+		if !ok {
+			return nil, errors.New("Invalid amount of transaction " + transfer.Hash)
+		}
+		sum.Add(sum, amount)
+		counted[transfer.Hash] = true
+	}
+
+	confirmed, err := ethereum.ConfirmedBlockNumber()
+	if err != nil {
+		return nil, err
+	}
+	events, err := filterRequestTransfers(&bind.FilterOpts{Start: 0, End: &confirmed}, nil, []common.Address{common.HexToAddress(farmer.EtherumAddress)}, request.Address)
+	if err != nil {
+		beego.Error("Failed to read transfers of request ", request.Id, ": ", err)
+		return nil, err
+	}
+	for _, event := range events {
+		if !counted[event.Raw.TxHash.String()] {
+			sum.Add(sum, event.Value)
+		}
+	}
+	return sum, nil
+}
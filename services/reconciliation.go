@@ -0,0 +1,286 @@
+package services
+
+import (
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/scmo/apayment-backend/models"
+	"github.com/scmo/apayment-backend/services/calculation"
+	"github.com/scmo/apayment-backend/smart-contracts/direct-payment-request"
+)
+
+// ReconciliationFlaggedError is returned when the payment of a request is calculated while
+// its contract differs from the off-chain calculation
+type ReconciliationFlaggedError struct {
+	RequestId int64
+}
+
+func (e *ReconciliationFlaggedError) Error() string {
+	return "Payout of request " + strconv.FormatInt(e.RequestId, 10) + " is flagged by the reconciliation"
+}
+
+// ReconciliationMissingError is returned when the payment of a request is calculated while
+// it cannot be reconciled, e.g. because its contract is not indexed yet
+type ReconciliationMissingError struct {
+	RequestId int64
+	Reason    string
+}
+
+func (e *ReconciliationMissingError) Error() string {
+	return "Payout of request " + strconv.FormatInt(e.RequestId, 10) + " is blocked until it is reconciled: " + e.Reason
+}
+
+// ReconcileRequests cross-checks the point groups of every request contract with the
+// calculation from the read model
+func ReconcileRequests() error {
+	o := orm.NewOrm()
+	var requests []*models.Request
+	_, err := o.QueryTable(new(models.Request)).All(&requests)
+	if err != nil {
+		beego.Error("Failed to load requests to reconcile: ", err)
+		return err
+	}
+	for _, request := range requests {
+		if !hasRequestContract(request) || !hasRequestPointGroups(request.Id) {
+			// the contract is not indexed yet
+			continue
+		}
+		if _, err := ReconcileRequest(request); err != nil {
+			beego.Error("Failed to reconcile request ", request.Id, ": ", err)
+		}
+	}
+	return nil
+}
+
+// ReconcileRequest calculates the point groups from the GVE and the lacks of the read
+// model and compares them with the contract. Mismatches and the operations of the contract
+// which wrap around flag the request, its payment is blocked until a later reconciliation
+// matches.
+func ReconcileRequest(request *models.Request) (*models.RequestReconciliation, error) {
+	if !hasRequestContract(request) {
+		return nil, &RequestValidationError{Message: "Request " + strconv.FormatInt(request.Id, 10) + " has no contract"}
+	}
+	if !hasRequestPointGroups(request.Id) {
+		return nil, &RequestValidationError{Message: "Contract of request " + strconv.FormatInt(request.Id, 10) + " is not indexed yet"}
+	}
+	requestContract, err := getRequestContractByAddress(request.Address)
+	if err != nil {
+		beego.Error("Error while fetching RequestContract by Address: ", err)
+		return nil, err
+	}
+	// the read model follows the latest block, not the confirmed one
+	session := &directpaymentrequest.RequestContractSession{Contract: requestContract}
+	input, err := reconciliationInput(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if input.AmountPreviousYear, err = session.AmountPreviousYear(); err != nil {
+		return nil, err
+	}
+	expected := calculation.Calculate(input)
+
+	var discrepancies []*models.ReconciliationDiscrepancy
+	actuals := make(map[string]string)
+	for _, pointGroup := range expected.PointGroups {
+		actual, err := session.PointGroups(pointGroup.PointGroupCode)
+		if err != nil {
+			beego.Error("Error while reading point group ", pointGroup.PointGroupCode, ": ", err)
+			return nil, err
+		}
+		fields := []struct {
+			name             string
+			expected, actual string
+		}{
+			{"gve", strconv.FormatUint(uint64(pointGroup.GVE), 10), strconv.FormatUint(uint64(actual.Gve), 10)},
+			{"btsPoints", strconv.Itoa(int(pointGroup.BtsPoints)), strconv.Itoa(int(actual.BtsPoints))},
+			{"btsTotal", pointGroup.BtsTotal.String(), actual.BtsTotal.String()},
+			{"btsDeduction", pointGroup.BtsDeduction.String(), actual.BtsDeduction.String()},
+			{"rausPoints", strconv.Itoa(int(pointGroup.RausPoints)), strconv.Itoa(int(actual.RausPoints))},
+			{"rausTotal", pointGroup.RausTotal.String(), actual.RausTotal.String()},
+			{"rausDeduction", pointGroup.RausDeduction.String(), actual.RausDeduction.String()},
+		}
+		for _, field := range fields {
+			actuals[reconciliationKey(pointGroup.PointGroupCode, field.name)] = field.actual
+			if field.expected != field.actual {
+				discrepancies = append(discrepancies, &models.ReconciliationDiscrepancy{Kind: models.DiscrepancyKindMismatch, PointGroupCode: pointGroup.PointGroupCode, Field: field.name, Expected: field.expected, Actual: field.actual})
+			}
+		}
+	}
+	finalPayment, err := session.GetFinalPaymentAmount()
+	if err != nil {
+		return nil, err
+	}
+	actuals[reconciliationKey(0, "finalPayment")] = finalPayment.String()
+	if finalPayment.Cmp(expected.FinalPayment) != 0 {
+		discrepancies = append(discrepancies, &models.ReconciliationDiscrepancy{Kind: models.DiscrepancyKindMismatch, Field: "finalPayment", Expected: expected.FinalPayment.String(), Actual: finalPayment.String()})
+	}
+	for _, underflow := range calculation.Underflows(expected) {
+		discrepancies = append(discrepancies, &models.ReconciliationDiscrepancy{Kind: models.DiscrepancyKindUnderflow, PointGroupCode: underflow.PointGroupCode, Field: underflow.Field, Actual: actuals[reconciliationKey(underflow.PointGroupCode, underflow.Field)], Message: underflow.Message})
+	}
+
+	reconciliation := &models.RequestReconciliation{Request: request, RequestId: request.Id, Status: models.ReconciliationStatusMatched, Discrepancies: discrepancies, Checked: time.Now()}
+	if len(discrepancies) > 0 {
+		reconciliation.Status = models.ReconciliationStatusFlagged
+		beego.Warning("Reconciliation of request ", request.Id, " found ", len(discrepancies), " discrepancies")
+	}
+	if err := storeReconciliation(reconciliation); err != nil {
+		beego.Error("Failed to store reconciliation of request ", request.Id, ": ", err)
+		return nil, err
+	}
+	return reconciliation, nil
+}
+
+// reconciliationInput reads the GVE and the lacks of a request from the read model,
+// reversed lacks have no points left in the contract
+func reconciliationInput(requestId int64) (*calculation.Input, error) {
+	o := orm.NewOrm()
+	var pointGroups []*models.RequestPointGroup
+	if _, err := o.QueryTable(new(models.RequestPointGroup)).Filter("Request", requestId).All(&pointGroups); err != nil {
+		return nil, err
+	}
+	var lacks []*models.RequestLack
+	if _, err := o.QueryTable(new(models.RequestLack)).Filter("Request", requestId).OrderBy("Index").All(&lacks); err != nil {
+		return nil, err
+	}
+	input := &calculation.Input{GVE: make(map[uint16]uint32), AmountPreviousYear: new(big.Int)}
+	for _, pointGroup := range pointGroups {
+		input.GVE[pointGroup.PointGroupCode] = pointGroup.Gve
+	}
+	for _, lack := range lacks {
+		points := lack.Points
+		if lack.Reversed {
+			points = 0
+		}
+		input.Lacks = append(input.Lacks, calculation.Lack{ContributionCode: lack.ContributionCode, PointGroupCode: lack.PointGroupCode, Points: points})
+	}
+	return input, nil
+}
+
+// storeReconciliation replaces the previous reconciliation of the request
+func storeReconciliation(reconciliation *models.RequestReconciliation) error {
+	o := orm.NewOrm()
+	if err := o.Begin(); err != nil {
+		return err
+	}
+	stored := models.RequestReconciliation{Request: reconciliation.Request}
+	err := o.Read(&stored, "Request")
+	switch err {
+	case nil:
+		reconciliation.Id = stored.Id
+		if _, err = o.Update(reconciliation, "Status", "Checked"); err == nil {
+			_, err = o.QueryTable(new(models.ReconciliationDiscrepancy)).Filter("Reconciliation", stored.Id).Delete()
+		}
+	case orm.ErrNoRows:
+		_, err = o.Insert(reconciliation)
+	}
+	for _, discrepancy := range reconciliation.Discrepancies {
+		if err != nil {
+			break
+		}
+		discrepancy.Reconciliation = reconciliation
+		_, err = o.Insert(discrepancy)
+	}
+	if err != nil {
+		o.Rollback()
+		return err
+	}
+	return o.Commit()
+}
+
+// dropReconciliation deletes the reconciliation of a request with its discrepancies once
+// the read model it was checked against changed
+func dropReconciliation(o orm.Ormer, requestId int64) error {
+	reconciliation := models.RequestReconciliation{Request: &models.Request{Id: requestId}}
+	switch err := o.Read(&reconciliation, "Request"); err {
+	case nil:
+	case orm.ErrNoRows:
+		return nil
+	default:
+		return err
+	}
+	if _, err := o.QueryTable(new(models.ReconciliationDiscrepancy)).Filter("Reconciliation", reconciliation.Id).Delete(); err != nil {
+		return err
+	}
+	_, err := o.Delete(&reconciliation)
+	return err
+}
+
+// checkRequestReconciled blocks the payment of a request whose latest reconciliation found
+// discrepancies. A request which was not reconciled since its lacks, point groups or
+// reversals were indexed, or since it was amended, is reconciled first; it stays blocked
+// while its contract is not indexed.
+func checkRequestReconciled(request *models.Request) error {
+	o := orm.NewOrm()
+	reconciliation := models.RequestReconciliation{Request: request}
+	err := o.Read(&reconciliation, "Request")
+	if err == orm.ErrNoRows {
+		var reconciled *models.RequestReconciliation
+		if reconciled, err = ReconcileRequest(request); err != nil {
+			beego.Error("Failed to reconcile request ", request.Id, " before its payment: ", err)
+			if validation, ok := err.(*RequestValidationError); ok {
+				return &ReconciliationMissingError{RequestId: request.Id, Reason: validation.Message}
+			}
+			return err
+		}
+		reconciliation = *reconciled
+	}
+	if err != nil {
+		return err
+	}
+	if reconciliation.Status == models.ReconciliationStatusFlagged {
+		return &ReconciliationFlaggedError{RequestId: request.Id}
+	}
+	return nil
+}
+
+var reconciliationSortFields = listFields{"checked": "Checked", "status": "Status"}
+
+// GetAllReconciliations loads a page of the reconciliations with their discrepancies,
+// filtered by status
+func GetAllReconciliations(query *ListQuery) (*Page, error) {
+	o := orm.NewOrm()
+	var reconciliations []*models.RequestReconciliation
+	page, err := paginate(o.QueryTable(new(models.RequestReconciliation)), query, map[string]listFilter{"status": filterString("Status"), "request": filterInt("Request")}, reconciliationSortFields, &reconciliations)
+	if err != nil {
+		return nil, err
+	}
+	for _, reconciliation := range reconciliations {
+		if err := loadDiscrepancies(reconciliation); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+// GetRequestReconciliation loads the latest reconciliation of a request
+func GetRequestReconciliation(requestId int64) (*models.RequestReconciliation, error) {
+	o := orm.NewOrm()
+	var reconciliation models.RequestReconciliation
+	if err := o.QueryTable(new(models.RequestReconciliation)).Filter("Request", requestId).One(&reconciliation); err != nil {
+		return nil, err
+	}
+	if err := loadDiscrepancies(&reconciliation); err != nil {
+		return nil, err
+	}
+	return &reconciliation, nil
+}
+
+func loadDiscrepancies(reconciliation *models.RequestReconciliation) error {
+	o := orm.NewOrm()
+	reconciliation.RequestId = reconciliation.Request.Id
+	reconciliation.Discrepancies = make([]*models.ReconciliationDiscrepancy, 0)
+	_, err := o.QueryTable(new(models.ReconciliationDiscrepancy)).Filter("Reconciliation", reconciliation.Id).OrderBy("Id").All(&reconciliation.Discrepancies)
+	return err
+}
+
+func hasRequestPointGroups(requestId int64) bool {
+	o := orm.NewOrm()
+	return o.QueryTable(new(models.RequestPointGroup)).Filter("Request", requestId).Exist()
+}
+
+func reconciliationKey(pointGroupCode uint16, field string) string {
+	return strconv.Itoa(int(pointGroupCode)) + "/" + field
+}
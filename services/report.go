@@ -0,0 +1,255 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/scmo/apayment-backend/ethereum"
+	"github.com/scmo/apayment-backend/models"
+	"github.com/scmo/apayment-backend/services/pdf"
+	"github.com/scmo/apayment-backend/smart-contracts/direct-payment-request"
+)
+
+// reportLack is a lack as recorded in the contract
+type reportLack struct {
+	points   int
+	reversed bool
+}
+
+// RequestReport renders the inspection report of a request as PDF. It only contains
+// data of the request and its contract, no time of rendering, so that the same data
+// always gives the same document. The whole contract is read as of one confirmed block.
+func RequestReport(requestId int64) ([]byte, error) {
+	request := GetRequestById(requestId, false)
+	if request.Id == 0 {
+		return nil, orm.ErrNoRows
+	}
+	if !hasRequestContract(request) {
+		return nil, &RequestValidationError{Message: "Request " + strconv.FormatInt(requestId, 10) + " has not been inspected"}
+	}
+	session, err := getReportSession(request)
+	if err != nil {
+		return nil, err
+	}
+	assignRequestFromSession(request, session, true)
+	switch RequestStatus(request) {
+	case models.RequestStatusInspected, models.RequestStatusFirstPaymentMade, models.RequestStatusFinalPaymentMade, models.RequestStatusClosed:
+	default:
+		return nil, &RequestValidationError{Message: "Request " + strconv.FormatInt(requestId, 10) + " has not been inspected"}
+	}
+	lacks, err := loadReportLacks(session, request)
+	if err != nil {
+		return nil, err
+	}
+	lacksTransaction := reportTransactions(request.Id, models.TransactionKindAddLacks)
+
+	doc := pdf.New("Inspection report, request " + strconv.FormatInt(request.Id, 10))
+	doc.Heading("Inspection report")
+	doc.Field("Request", strconv.FormatInt(request.Id, 10))
+	doc.Field("Year", strconv.Itoa(request.Year))
+	doc.Field("Status", RequestStatus(request))
+	doc.Field("Submitted", reportDate(request.Created))
+	doc.Field("Last change of the contract", reportDate(request.Modified))
+
+	doc.Subheading("Farm")
+	if farmer := request.User; farmer != nil {
+		doc.Field("Farmer", strings.TrimSpace(farmer.Firstname+" "+farmer.Lastname))
+		doc.Field("TVD number", strconv.Itoa(int(farmer.TVD)))
+		if detail := farmer.AnimalHusbandryDetailResult; detail != nil {
+			if detail.PostData != nil {
+				doc.Field("Name", detail.PostData.Name)
+				doc.Field("Address", strings.TrimSpace(detail.PostData.Street+", "+detail.PostData.PostCode+" "+detail.PostData.City))
+			}
+			doc.Field("Municipality", detail.MunicipalityName)
+			doc.Field("Canton", detail.CantonShortname)
+			doc.Field("BUR number", detail.BurNumber)
+		}
+	}
+
+	doc.Subheading("Inspector")
+	if inspector := request.Inspector; inspector != nil {
+		doc.Field("Name", strings.TrimSpace(inspector.Firstname+" "+inspector.Lastname))
+		doc.Field("Email", inspector.Email)
+		doc.Field("Ethereum address", inspector.EtherumAddress)
+	}
+
+	doc.Subheading("Contributions")
+	for _, contribution := range request.Contributions {
+		doc.Row(0, strconv.Itoa(int(contribution.Code))+" "+contribution.Name, "", false)
+	}
+
+	doc.Subheading("Lacks")
+	if len(request.ContributionsWithLacks) == 0 {
+		doc.Paragraph("No lacks were found in the inspection.")
+	}
+	for _, contribution := range request.ContributionsWithLacks {
+		doc.Row(0, strconv.Itoa(int(contribution.Code))+" "+contribution.Name, "Points", true)
+		for _, controlCategory := range contribution.ControlCategories {
+			doc.Row(12, controlCategory.ControlCategoryId+" "+controlCategory.ControlCategory, "", false)
+			for _, pointGroup := range controlCategory.PointGroups {
+				doc.Row(24, strconv.Itoa(int(pointGroup.PointGroupCode))+" "+pointGroup.PointGroup, "", false)
+				for _, controlPoint := range pointGroup.ControlPoints {
+					doc.Row(36, controlPoint.ControlPointId+" "+controlPoint.ControlPoint, "", false)
+					for _, lack := range controlPoint.Lacks {
+						key := evidenceLackKey(contribution.Code, controlCategory.Id, pointGroup.PointGroupCode, controlPoint.Id, lack.Id)
+						doc.Row(48, lack.Name, reportPoints(lacks[key]), false)
+					}
+				}
+			}
+		}
+	}
+
+	doc.Subheading("Deductions per point group")
+	if err := writeReportDeductions(doc, session, request); err != nil {
+		return nil, err
+	}
+
+	doc.Subheading("Blockchain")
+	doc.Field("Contract address", request.Address)
+	footer := "Request " + strconv.FormatInt(request.Id, 10) + ", contract " + request.Address
+	if len(lacksTransaction) > 0 {
+		doc.Field("Lacks transaction", lacksTransaction[0])
+		footer += ", transaction " + lacksTransaction[0]
+	}
+	for _, hash := range reportTransactions(request.Id, models.TransactionKindReverseLack) {
+		doc.Field("Reversal transaction", hash)
+	}
+	return doc.Bytes(footer), nil
+}
+
+// ReportSignature lets a reader check that a report was issued by the backend: the system
+// account signs the SHA-256 digest of the document as an Ethereum signed message, which
+// ecrecover turns back into the address of the signer
+type ReportSignature struct {
+	Digest    string `json:"digest"`
+	Signature string `json:"signature"`
+	Signer    string `json:"signer"`
+}
+
+// SignReport signs a report with the system account
+func SignReport(report []byte) (*ReportSignature, error) {
+	digest := sha256.Sum256(report)
+	signer := beego.AppConfig.String("systemAccountAddress")
+	signature, err := ethereum.SignMessage(signer, digest[:])
+	if err != nil {
+		beego.Error("Failed to sign report: ", err)
+		return nil, err
+	}
+	return &ReportSignature{Digest: hex.EncodeToString(digest[:]), Signature: hexutil.Encode(signature), Signer: common.HexToAddress(signer).String()}, nil
+}
+
+// writeReportDeductions lists the amounts and deductions of the point groups with animals
+func writeReportDeductions(doc *pdf.Document, session *directpaymentrequest.RequestContractSession, request *models.Request) error {
+	for _, gve := range request.GVE {
+		if gve.PointGroup == nil {
+			continue
+		}
+		pointGroup, err := session.PointGroups(gve.PointGroup.PointGroupCode)
+		if err != nil {
+			beego.Error("Error while reading point group ", gve.PointGroup.PointGroupCode, ": ", err)
+			return err
+		}
+		if pointGroup.BtsTotal.Sign() == 0 && pointGroup.RausTotal.Sign() == 0 {
+			continue
+		}
+		doc.Row(0, strconv.Itoa(int(gve.PointGroup.PointGroupCode))+" "+gve.PointGroup.PointGroup, "Deduction", true)
+		if pointGroup.BtsTotal.Sign() != 0 {
+			doc.Row(12, "BTS: "+strconv.Itoa(int(pointGroup.BtsPoints))+" points of an amount of "+pointGroup.BtsTotal.String(), pointGroup.BtsDeduction.String(), false)
+		}
+		if pointGroup.RausTotal.Sign() != 0 {
+			doc.Row(12, "RAUS: "+strconv.Itoa(int(pointGroup.RausPoints))+" points of an amount of "+pointGroup.RausTotal.String(), pointGroup.RausDeduction.String(), false)
+		}
+	}
+	return nil
+}
+
+// getReportSession reads the contract as of the latest confirmed block, so that the
+// report does not change with pending transactions
+func getReportSession(request *models.Request) (*directpaymentrequest.RequestContractSession, error) {
+	requestContract, err := getRequestContractByAddress(request.Address)
+	if err != nil {
+		beego.Error("Error while fetching RequestContract by Address: ", err)
+		return nil, err
+	}
+	opts, err := ethereum.ConfirmedCallOpts()
+	if err != nil {
+		return nil, err
+	}
+	return &directpaymentrequest.RequestContractSession{Contract: requestContract, CallOpts: *opts}, nil
+}
+
+// loadReportLacks reads the points of the lacks from the contract, by lack. The contract
+// has no points left for lacks reversed after an objection, their points are taken
+// from the read model.
+func loadReportLacks(session *directpaymentrequest.RequestContractSession, request *models.Request) (map[string]*reportLack, error) {
+	numLacks, err := session.NumLacks()
+	if err != nil {
+		return nil, err
+	}
+	o := orm.NewOrm()
+	var reversed []*models.RequestLack
+	if _, err := o.QueryTable(new(models.RequestLack)).Filter("Request", request.Id).Filter("Reversed", true).All(&reversed); err != nil {
+		return nil, err
+	}
+	lacks := make(map[string]*reportLack)
+	for i := int64(0); i < numLacks.Int64(); i++ {
+		lack, err := session.Lacks(big.NewInt(i))
+		if err != nil {
+			return nil, err
+		}
+		key := evidenceLackKey(lack.ContributionCode, lack.ControlCategoryId, lack.PointGroupCode, lack.ControlPointId, lack.LackId)
+		if lacks[key] == nil {
+			lacks[key] = &reportLack{}
+		}
+		lacks[key].points += int(lack.Points)
+		for _, r := range reversed {
+			if r.Index == i {
+				lacks[key].points += int(r.Points)
+				lacks[key].reversed = true
+			}
+		}
+	}
+	return lacks, nil
+}
+
+// reportTransactions returns the hashes of the transactions of a kind sent for the
+// request which did not fail, the latest first
+func reportTransactions(requestId int64, kind string) []string {
+	o := orm.NewOrm()
+	var transactions []*models.EthereumTransaction
+	_, err := o.QueryTable(new(models.EthereumTransaction)).Filter("Request", requestId).Filter("Kind", kind).Exclude("Status", models.TransactionStatusFailed).OrderBy("-Id").All(&transactions)
+	if err != nil {
+		beego.Error("Error while reading the transactions of request ", requestId, ": ", err)
+	}
+	hashes := make([]string, len(transactions))
+	for i, transaction := range transactions {
+		hashes[i] = transaction.Hash
+	}
+	return hashes
+}
+
+func reportPoints(lack *reportLack) string {
+	switch {
+	case lack == nil:
+		return "-"
+	case lack.reversed:
+		return strconv.Itoa(lack.points) + " (reversed)"
+	}
+	return strconv.Itoa(lack.points)
+}
+
+// reportDate formats a timestamp of the contract, in UTC
+func reportDate(timestamp *big.Int) string {
+	if timestamp == nil || timestamp.Sign() == 0 {
+		return "-"
+	}
+	return time.Unix(timestamp.Int64(), 0).UTC().Format("02.01.2006 15:04 UTC")
+}
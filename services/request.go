@@ -5,6 +5,7 @@ import (
 	"github.com/astaxie/beego/orm"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/scmo/apayment-backend/ethereum"
 	"github.com/scmo/apayment-backend/models"
 	"github.com/scmo/apayment-backend/services/tvd"
@@ -18,69 +19,86 @@ import (
 
 // CreateRequest deploys a new Request Contract in the blockchain.
 func CreateRequest(request *models.Request, auth *bind.TransactOpts) error {
+	if err := ValidateRequest(request); err != nil {
+		return err
+	}
+	if err := checkRequestYear(request); err != nil {
+		return err
+	}
+	address, tx, err := deployRequestContract(request, auth)
+	if err != nil {
+		return err
+	}
+
+	request.Address = address.String()
+	request.Status = models.RequestStatusSubmitted
+	request.OpenYear = &request.Year
+	request.ContributionCodes = contributionCodesColumn(request)
+	request.Canton = farmCanton(request.User)
+
+	o := orm.NewOrm()
+	_, err = o.Insert(request)
+	if err != nil {
+		beego.Error("Failed to insert new Request: ", err)
+		return err
+	}
+	if err := startRequestLifecycle(request, auth.From.String()); err != nil {
+		beego.Error("Failed to store status of new Request: ", err)
+	}
+	watchRequest(request)
+	return trackDeployment(tx, request, auth)
+}
+
+// deployRequestContract deploys a contract holding the contributions and the remark of the request
+func deployRequestContract(request *models.Request, auth *bind.TransactOpts) (common.Address, *types.Transaction, error) {
 	ethereumController := ethereum.GetEthereumController()
 
-	gvesMap, err := tvd.GetNumberOfGVELastYear(request.User.TVD)
+	year, err := referenceYear(request)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	gvesMap, err := tvd.GetNumberOfGVELastYear(request.User.TVD, year)
 	if err != nil {
 		beego.Error("Failed to get GVE. ", err)
-		return err
+		return common.Address{}, nil, err
 	}
 	var gvesList = make([]uint32, 0)
 	for _, value := range gvesMap {
 		gvesList = append(gvesList, value)
 	}
 
-	previousYearAmount, err := getRequestAmountFromPreviousYear(request.User)
+	previousYearAmount, err := getRequestAmountFromPreviousYear(request.User, year)
 	if err != nil {
 		beego.Error("Error to get amount from last year: ", err)
-		return err
+		return common.Address{}, nil, err
 	}
 
 	address, tx, _, err := directpaymentrequest.DeployRequestContract(auth, ethereumController.Client, getContributionCodes(request), request.Remark, common.HexToAddress(beego.AppConfig.String("accessControlContract")), gvesList, previousYearAmount)
 	if err != nil {
 		beego.Error("Failed to deploy new token contract: ", err)
-		return err
+		return common.Address{}, nil, err
 	}
 	beego.Info("Contract pending deploy: ", address.String())
 	beego.Info("Transaction waiting to be mined: ", tx.Hash().String())
+	return address, tx, nil
+}
 
-	request.Address = address.String()
-
-	o := orm.NewOrm()
-	_, err = o.Insert(request)
-	if err != nil {
-		beego.Error("Failed to insert new Request: ", err)
-	}
-	return err
+// trackDeployment follows the deployment of the contract of a request, its read model
+// is synced once it is mined
+func trackDeployment(tx *types.Transaction, request *models.Request, auth *bind.TransactOpts) error {
+	return TrackTransaction(tx, &models.EthereumTransaction{Kind: models.TransactionKindDeploy, From: auth.From.String(), Request: request, Account: request.Address})
 }
 
-// GetAllRequests loads all request address stored in the database. With the address, the contract of the request get loaded.
-func GetAllRequests() []*models.Request {
+// GetAllRequests loads a page of the requests stored in the database. With chain consistency the contract of each request gets loaded.
+func GetAllRequests(query *ListQuery, chain bool) (*Page, error) {
 	o := orm.NewOrm()
-	var requests []*models.Request
-	o.QueryTable(new(models.Request)).RelatedSel().All(&requests)
-	for _, request := range requests {
-		requestContract, err := getRequestContractByAddress(request.Address)
-		if err != nil {
-			beego.Error("Failed to instantiate a Token contract: %v", err)
-		}
-		assignRequest(request, requestContract, false)
-	}
-	return requests
+	// drafts are only visible to their farmer
+	return listRequests(o.QueryTable(new(models.Request)).Exclude("Status", models.RequestStatusDraft), query, chain)
 }
 
-func GetAllRequestsByUserId(userId int64) []*models.Request {
+func GetAllRequestsByUserId(userId int64, query *ListQuery, chain bool) (*Page, error) {
 	o := orm.NewOrm()
-	var requests []*models.Request
-	o.QueryTable(new(models.Request)).Filter("user", userId).RelatedSel().All(&requests)
-	for _, request := range requests {
-		requestContract, err := getRequestContractByAddress(request.Address)
-		if err != nil {
-			beego.Error("Failed to instantiate a Token contract: %v", err)
-		}
-		assignRequest(request, requestContract, false)
-	}
-	return requests
+	return listRequests(o.QueryTable(new(models.Request)).Filter("user", userId), query, chain)
 }
 
 func GetRequestById(requestId int64, smartContract bool) *models.Request {
@@ -90,14 +108,13 @@ func GetRequestById(requestId int64, smartContract bool) *models.Request {
 	err := o.QueryTable(new(models.Request)).Filter("Id", requestId).RelatedSel().One(&request)
 	if err != nil {
 		beego.Error("Error while fetching Request by ID.", err)
+		return &request
 	}
 	setTVD(request.User)
+	loadRequestTransitions(&request)
+	loadRequestAmendments(&request)
 	if smartContract {
-		requestContract, err := getRequestContractByAddress(request.Address)
-		if err != nil {
-			beego.Error("Failed to instantiate a Token contract: %v", err)
-		}
-		assignRequest(&request, requestContract, true)
+		assignRequestContent(&request, true)
 	}
 
 	return &request
@@ -123,37 +140,29 @@ func GetRequestIdByAddress(requestAddress string) int64 {
 	return request.Id
 }
 
-func GetAllRequestsForInspection() []*models.Request {
+func GetAllRequestsForInspection(query *ListQuery, chain bool) (*Page, error) {
 	o := orm.NewOrm()
-	var requests []*models.Request
-	o.QueryTable(new(models.Request)).Filter("inspector__isnull", false).RelatedSel().All(&requests)
-	for _, request := range requests {
-		requestContract, err := getRequestContractByAddress(request.Address)
-		if err != nil {
-			beego.Error("Failed to instantiate a Token contract: %v", err)
-		}
-		assignRequest(request, requestContract, false)
-	}
-	return requests
+	return listRequests(o.QueryTable(new(models.Request)).Filter("inspector__isnull", false).Exclude("Status__in", models.RequestStatusWithdrawn, models.RequestStatusRejected), query, chain)
 }
 
-func GetAllRequestsForInspectionByInspectorId(inspectorId int64) []*models.Request {
+func GetAllRequestsForInspectionByInspectorId(inspectorId int64, query *ListQuery, chain bool) (*Page, error) {
 	o := orm.NewOrm()
-	var requests []*models.Request
-	o.QueryTable(new(models.Request)).Filter("inspector", inspectorId).RelatedSel().All(&requests)
-	for _, request := range requests {
-		requestContract, err := getRequestContractByAddress(request.Address)
-		if err != nil {
-			beego.Error("Failed to instantiate a Token contract: %v", err)
-		}
-		assignRequest(request, requestContract, false)
-	}
-	return requests
+	return listRequests(o.QueryTable(new(models.Request)).Filter("inspector", inspectorId).Exclude("Status__in", models.RequestStatusWithdrawn, models.RequestStatusRejected), query, chain)
 }
 
 func AddInspectorToRequest(request *models.Request, auth *bind.TransactOpts) error {
-	// Add to DB
 	o := orm.NewOrm()
+	stored := models.Request{Id: request.Id}
+	if err := o.Read(&stored); err != nil {
+		beego.Error("Error while fetching Request by ID: ", err)
+		return err
+	}
+	if err := CheckRequestTransition(&stored, models.RequestStatusInspectorAssigned); err != nil {
+		return err
+	}
+	request.Address = stored.Address
+
+	// Add to DB
 	o.Update(request, "Inspector")
 
 	// Add to the SmartContract
@@ -172,14 +181,34 @@ func AddInspectorToRequest(request *models.Request, auth *bind.TransactOpts) err
 		return err
 	}
 	beego.Info("Transaction waiting to be mined: ", tx.Hash().String())
-	return err
+	if err := TrackTransaction(tx, &models.EthereumTransaction{Kind: models.TransactionKindSetInspector, From: auth.From.String(), Request: request, Account: request.Inspector.EtherumAddress}); err != nil {
+		return err
+	}
+	if stored.Inspector != nil && stored.Inspector.Id != request.Inspector.Id {
+		// the appointments of the previous inspector are void
+		closeRequestAppointments(request.Id, models.AppointmentStatusCancelled)
+	}
+	return TransitionRequest(&stored, models.RequestStatusInspectorAssigned, auth.From.String(), "")
 }
 
-// Add inspection Lacks to Request
+// Add inspection Lacks to Request. Errors after the lacks were sent are a LacksSentError.
 func AddLacksToRequest(inspection *models.Inspection, auth *bind.TransactOpts) error {
-	requestAddress := GetRequestAddressById(inspection.RequestId)
+	o := orm.NewOrm()
+	var request models.Request
+	if err := o.QueryTable(new(models.Request)).Filter("Id", inspection.RequestId).RelatedSel().One(&request); err != nil {
+		beego.Error("Error while fetching Request by ID: ", err)
+		return err
+	}
+	// lacks can only be added once an inspector is assigned
+	if err := CheckRequestTransition(&request, models.RequestStatusInspected); err != nil {
+		return err
+	}
+	// the deductions are permanent on chain
+	if err := ValidateInspection(inspection, &request); err != nil {
+		return err
+	}
 	// Add to the SmartContract
-	requestContract, err := getRequestContractByAddress(requestAddress)
+	requestContract, err := getRequestContractByAddress(request.Address)
 
 	if err != nil {
 		beego.Error("Error while fetching RequestContract by Address: ", err)
@@ -212,7 +241,20 @@ func AddLacksToRequest(inspection *models.Inspection, auth *bind.TransactOpts) e
 		return err
 	}
 	beego.Info("Transaction waiting to be mined: ", tx.Hash().String())
-	return err
+	// the lacks are sent, the request is inspected even if the transaction is not tracked
+	if err := TrackTransaction(tx, &models.EthereumTransaction{Kind: models.TransactionKindAddLacks, From: auth.From.String(), Request: &request}); err != nil {
+		beego.Error("Failed to track the lacks of request ", request.Id, " sent with ", tx.Hash().String(), ": ", err)
+	}
+	if err := TransitionRequest(&request, models.RequestStatusInspected, auth.From.String(), ""); err != nil {
+		beego.Error("Failed to mark request ", request.Id, " inspected after sending its lacks: ", err)
+		return &LacksSentError{Hash: tx.Hash().String(), Err: err}
+	}
+	closeRequestAppointments(request.Id, models.AppointmentStatusHeld)
+	// the lacks are on chain already, the evidence can be anchored again later
+	if err := AnchorEvidence(&request, auth); err != nil {
+		beego.Error("Failed to anchor evidence of request ", request.Id, ": ", err)
+	}
+	return nil
 }
 
 /*
@@ -246,46 +288,22 @@ func AddLacksToRequest(inspection *models.Inspection, auth *bind.TransactOpts) e
 //	return err
 //}
 
-func GetFirstPaymentAmount(request *models.Request) (*big.Int, error) {
-	requestContract, err := getRequestContractByAddress(request.Address)
-	if err != nil {
-		beego.Error("Error while fetching RequestContract by Address: ", err)
-		return nil, err
-	}
-	amount, err := requestContract.GetFirstPaymentAmount(nil)
-	if err != nil {
-		beego.Error("Error while first payment amount: ", err)
-		return nil, err
-	}
-	return amount, err
-}
-
-func GetSecondPaymentAmount(request *models.Request) (*big.Int, error) {
-	requestContract, err := getRequestContractByAddress(request.Address)
-	if err != nil {
-		beego.Error("Error while fetching RequestContract by Address: ", err)
-		return nil, err
-	}
-	amount, err := requestContract.GetFinalPaymentAmount(nil)
-	if err != nil {
-		beego.Error("Error while first payment amount: ", err)
-		return nil, err
-	}
-	return amount, err
-}
-
 func CheckRausJournal(request *models.Request) (error) {
 	for _, contribution := range request.Contributions {
 		if contribution.Code == 5417 {
 			// RAUS contribution exists
-			cowList := prepareTVDList(request.User.TVD)
-			if len(cowList) == 0 {
-				return nil
+			year, err := referenceYear(request)
+			if err != nil {
+				return err
+			}
+			cowList, err := prepareTVDList(request.User.TVD, year)
+			if err != nil || len(cowList) == 0 {
+				return err
 			}
 			for _, controlCategory := range contribution.ControlCategories {
 				for _, pointGroup := range controlCategory.PointGroups {
 					cows := cowList[pointGroup.PointGroupCode]
-					missedDays, err := requestRausJournal(cows)
+					missedDays, err := requestRausJournal(cows, year)
 					if err != nil {
 						beego.Error("Error while calling RAUS API")
 					}
@@ -305,21 +323,25 @@ func CheckRausJournal(request *models.Request) (error) {
 	return nil
 }
 
-func prepareTVDList(tvdNr int32) (map[uint16]models.EarTagNumbers) {
+func prepareTVDList(tvdNr int32, year int) (map[uint16]models.EarTagNumbers, error) {
 	// Cows per PointGroup
 	var cowList = make(map[uint16]models.EarTagNumbers)
 
-	// get all cows from current year
+	// get all cows from the request year
 	oc, _ := time.LoadLocation("Europe/Zurich")
-	begin := time.Date(time.Now().Year(), time.Month(1), 0, 0, 0, 0, 0, oc)
+	begin := time.Date(year, time.Month(1), 0, 0, 0, 0, 0, oc)
+	end := time.Date(year, time.Month(12), 31, 23, 59, 0, 0, oc)
+	if end.After(time.Now()) {
+		end = time.Now()
+	}
 
-	cattleLivestockV2Response, err := tvd.GetUserCattleLivestock(tvdNr, begin, time.Now())
+	cattleLivestockV2Response, err := tvd.GetUserCattleLivestock(tvdNr, begin, end)
 	if err != nil {
 		beego.Error("Error while fetching GetUserCattleLivestock: ", err)
-		return cowList
+		return cowList, err
 	}
 	for _, cattleLivestockDataItem := range cattleLivestockV2Response.GetCattleLivestockV2Result.Resultdetails.CattleLivestockDataItem {
-		catIndex, err := tvd.GetAnimalCategory(cattleLivestockDataItem)
+		catIndex, err := tvd.GetAnimalCategoryAt(cattleLivestockDataItem, end)
 		if err != nil {
 			beego.Error("Error while fetching GetUserCattleLivestock: ", err)
 			continue
@@ -329,15 +351,15 @@ func prepareTVDList(tvdNr int32) (map[uint16]models.EarTagNumbers) {
 		earTagNumber.EarTagNumbers = append(earTagNumber.EarTagNumbers, cattleLivestockDataItem.EarTagNumber)
 		cowList[pointGroup] = earTagNumber
 	}
-	return cowList
+	return cowList, nil
 }
 
 
-func requestRausJournal(cows models.EarTagNumbers) (int8, error) {
+func requestRausJournal(cows models.EarTagNumbers, year int) (int8, error) {
 	if len(cows.EarTagNumbers) == 0 {
 		return 0, nil
 	}
-	reqBody := models.RausJournalRequest{TVDs:cows.EarTagNumbers, Year: 2017}
+	reqBody := models.RausJournalRequest{TVDs:cows.EarTagNumbers, Year: int16(year)}
 	jsonValue, _ := json.Marshal(reqBody)
 	req, err := http.NewRequest("POST", beego.AppConfig.String("rausJournalURL"),  bytes.NewBuffer(jsonValue))
 	if err != nil {
@@ -360,6 +382,8 @@ func requestRausJournal(cows models.EarTagNumbers) (int8, error) {
 	return record.MissedDays, nil
 }
 
+// getRequestContractSession reads the pending state, which may still be reorganised.
+// It is only displayed, decisions are based on ethereum.ConfirmedCallOpts.
 func getRequestContractSession(requestContract *directpaymentrequest.RequestContract) *directpaymentrequest.RequestContractSession {
 	requestContractSesssion := &directpaymentrequest.RequestContractSession{
 		Contract: requestContract,
@@ -374,9 +398,20 @@ func getRequestContractSession(requestContract *directpaymentrequest.RequestCont
 
 }
 
-// TODO
-func getRequestAmountFromPreviousYear(user *models.User) (*big.Int, error) {
-	return big.NewInt(0), nil
+// referenceYear returns the year of a request. The year of a request stored before the
+// year was kept is backfilled from the creation of its contract.
+func referenceYear(request *models.Request) (int, error) {
+	if request.Year == 0 {
+		if err := backfillRequestYear(request); err != nil {
+			return 0, err
+		}
+	}
+	return request.Year, nil
+}
+
+func currentYear() int {
+	oc, _ := time.LoadLocation("Europe/Zurich")
+	return time.Now().In(oc).Year()
 }
 
 func getContributionCodes(request *models.Request) []uint16 {
@@ -392,8 +427,28 @@ func getRequestContractByAddress(address string) (*directpaymentrequest.RequestC
 	return directpaymentrequest.NewRequestContract(common.HexToAddress(address), ethereumController.Client)
 }
 
+// assignRequestContent fills the request from its contract, or from its draft when
+// it has not been submitted or its contract has been killed
+func assignRequestContent(request *models.Request, full bool) {
+	if !hasRequestContract(request) {
+		if err := assignDraft(request); err != nil {
+			beego.Error("Failed to load draft of request ", request.Id, ": ", err)
+		}
+		return
+	}
+	requestContract, err := getRequestContractByAddress(request.Address)
+	if err != nil {
+		beego.Error("Failed to instantiate a Token contract: %v", err)
+	}
+	assignRequest(request, requestContract, full)
+}
+
 func assignRequest(request *models.Request, requestContract *directpaymentrequest.RequestContract, full bool) {
-	session := getRequestContractSession(requestContract)
+	assignRequestFromSession(request, getRequestContractSession(requestContract), full)
+}
+
+// assignRequestFromSession reads the content of a request with the call options of the session
+func assignRequestFromSession(request *models.Request, session *directpaymentrequest.RequestContractSession, full bool) {
 	remark, err := session.Remark()
 	if err != nil {
 		beego.Error("Failed to instantiate a Token contract: ", err)
@@ -433,7 +488,7 @@ func setContributions(request *models.Request, session *directpaymentrequest.Req
 			next = false
 		}
 		if err == nil {
-			contribution, err := GetContributionByCode(code)
+			contribution, err := GetContributionByCodeForYear(code, request.Year)
 			if err != nil {
 				beego.Error("Error getting Contribution", err)
 			}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/scmo/apayment-backend/models"
+)
+
+// AmendRequest replaces the contract of a submitted request that has not been inspected
+// yet. The replacement is deployed with the amended content and the old contract is
+// killed. An inspector assigned before has to be assigned again.
+func AmendRequest(request *models.Request, auth *bind.TransactOpts) (*models.Request, error) {
+	o := orm.NewOrm()
+	var stored models.Request
+	if err := o.QueryTable(new(models.Request)).Filter("Id", request.Id).RelatedSel().One(&stored); err != nil {
+		beego.Error("Error while fetching Request by ID: ", err)
+		return nil, err
+	}
+	if RequestStatus(&stored) == models.RequestStatusDraft {
+		return nil, &RequestValidationError{Message: "Drafts are edited, not amended"}
+	}
+	if err := CheckRequestTransition(&stored, models.RequestStatusSubmitted); err != nil {
+		return nil, err
+	}
+	if err := ValidateRequest(request); err != nil {
+		return nil, err
+	}
+	year, err := referenceYear(&stored)
+	if err != nil {
+		return nil, err
+	}
+	request.User = stored.User
+	request.Year = year
+	address, tx, err := deployRequestContract(request, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	stored.Remark = request.Remark
+	stored.ContributionCodes = contributionCodesColumn(request)
+	amendment := &models.RequestAmendment{Request: &stored, PreviousAddress: stored.Address, Address: address.String(), Actor: auth.From.String()}
+	indexer.Lock()
+	err = storeAmendment(&stored, amendment)
+	indexer.Unlock()
+	if err != nil {
+		beego.Error("Failed to store amendment of request ", stored.Id, ": ", err)
+		return nil, err
+	}
+	// the subscription to the old contract is replaced
+	unwatchRequest(stored.Id)
+	if err := TransitionRequest(&stored, models.RequestStatusSubmitted, auth.From.String(), "Amended"); err != nil {
+		return nil, err
+	}
+	closeRequestAppointments(stored.Id, models.AppointmentStatusCancelled)
+	watchRequest(&stored)
+	if err := trackDeployment(tx, &stored, auth); err != nil {
+		return nil, err
+	}
+	// the amendment is stored, a kill which fails is tried again
+	scheduleContractKill(amendment.PreviousAddress, &stored, auth.From.String())
+	return &stored, nil
+}
+
+// storeAmendment points the request to the replacement contract. The state indexed
+// from the old contract is dropped, the events of the new one are applied from scratch.
+func storeAmendment(request *models.Request, amendment *models.RequestAmendment) error {
+	o := orm.NewOrm()
+	if err := o.Begin(); err != nil {
+		return err
+	}
+	request.Address = amendment.Address
+	request.Inspector = nil
+	request.NumLacks = 0
+	request.SyncedBlock = 0
+	request.ContractCreated = 0
+	request.ContractModified = 0
+	_, err := o.Insert(amendment)
+	if err == nil {
+		_, err = o.Update(request, "Address", "Remark", "Inspector", "NumLacks", "SyncedBlock", "ContractCreated", "ContractModified", "ContributionCodes")
+	}
+	if err == nil {
+		err = dropReconciliation(o, request.Id)
+	}
+	for _, model := range []interface{}{new(models.ContractEvent), new(models.RequestLack), new(models.RequestPointGroup)} {
+		if err == nil {
+			_, err = o.QueryTable(model).Filter("Request", request.Id).Delete()
+		}
+	}
+	if err != nil {
+		o.Rollback()
+		return err
+	}
+	return o.Commit()
+}
+
+// killRequestContract retires a contract. The owner, the farmer who deployed it, and
+// admins and canton employees can kill it. A kill sent but not tracked is returned without id.
+func killRequestContract(address string, request *models.Request, auth *bind.TransactOpts) (*models.EthereumTransaction, error) {
+	requestContract, err := getRequestContractByAddress(address)
+	if err != nil {
+		beego.Error("Error while fetching RequestContract by Address: ", err)
+		return nil, err
+	}
+	session := getRequestContractSession(requestContract)
+	session.TransactOpts.From = auth.From
+	session.TransactOpts.Signer = auth.Signer
+
+	tx, err := session.Kill()
+	if err != nil {
+		beego.Error("Failed to kill request contract: ", err)
+		return nil, err
+	}
+	beego.Info("Transaction waiting to be mined: ", tx.Hash().String())
+	transaction := &models.EthereumTransaction{Kind: models.TransactionKindKill, From: auth.From.String(), Request: request, Detail: address}
+	if err := TrackTransaction(tx, transaction); err != nil {
+		beego.Error("Kill ", tx.Hash().String(), " was sent but is not tracked: ", err)
+	}
+	return transaction, nil
+}
+
+// loadRequestAmendments attaches the replaced contracts to the request
+func loadRequestAmendments(request *models.Request) {
+	o := orm.NewOrm()
+	if _, err := o.QueryTable(new(models.RequestAmendment)).Filter("Request", request.Id).OrderBy("Id").All(&request.Amendments); err != nil {
+		beego.Error("Failed to load amendments of request ", request.Id, ": ", err)
+	}
+}
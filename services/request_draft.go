@@ -0,0 +1,200 @@
+package services
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/scmo/apayment-backend/models"
+)
+
+// RequestValidationError is returned when the content of a request cannot be submitted
+type RequestValidationError struct {
+	Message string
+}
+
+func (e *RequestValidationError) Error() string {
+	return e.Message
+}
+
+// ValidateRequest checks the contributions of a request before its contract is deployed
+func ValidateRequest(request *models.Request) error {
+	if len(request.Contributions) == 0 {
+		return &RequestValidationError{Message: "Request has no contribution"}
+	}
+	o := orm.NewOrm()
+	codes := make(map[uint16]bool)
+	for _, contribution := range request.Contributions {
+		if codes[contribution.Code] {
+			return &RequestValidationError{Message: "Contribution " + strconv.Itoa(int(contribution.Code)) + " is requested twice"}
+		}
+		codes[contribution.Code] = true
+		if !o.QueryTable(new(models.Contribution)).Filter("Code", contribution.Code).Exist() {
+			return &RequestValidationError{Message: "Unknown contribution " + strconv.Itoa(int(contribution.Code))}
+		}
+	}
+	return nil
+}
+
+// checkRequestYear defaults the year of a new request to the current one. A farmer
+// holds one request per year, withdrawn and rejected requests do not count.
+func checkRequestYear(request *models.Request) error {
+	if request.Year == 0 {
+		request.Year = currentYear()
+	}
+	if request.Year > currentYear() {
+		return &RequestValidationError{Message: "Requests cannot be made for " + strconv.Itoa(request.Year) + " yet"}
+	}
+	o := orm.NewOrm()
+	if o.QueryTable(new(models.Request)).Filter("User", request.User.Id).Filter("Year", request.Year).Exclude("Id", request.Id).Exclude("Status__in", models.RequestStatusWithdrawn, models.RequestStatusRejected).Exist() {
+		return &RequestValidationError{Message: "There is already a request for " + strconv.Itoa(request.Year)}
+	}
+	return nil
+}
+
+// CreateDraft stores a request without deploying its contract. It can be edited until it is submitted.
+func CreateDraft(request *models.Request) error {
+	if err := checkRequestYear(request); err != nil {
+		return err
+	}
+	request.Address = ""
+	request.Status = models.RequestStatusDraft
+	request.OpenYear = &request.Year
+	request.ContributionCodes = contributionCodesColumn(request)
+	request.Canton = farmCanton(request.User)
+	o := orm.NewOrm()
+	if err := o.Begin(); err != nil {
+		return err
+	}
+	if _, err := o.Insert(request); err != nil {
+		o.Rollback()
+		beego.Error("Failed to insert new draft: ", err)
+		return err
+	}
+	if _, err := o.Insert(newRequestDraft(request)); err != nil {
+		o.Rollback()
+		beego.Error("Failed to insert new draft: ", err)
+		return err
+	}
+	if err := o.Commit(); err != nil {
+		return err
+	}
+	if err := startRequestLifecycle(request, request.User.EtherumAddress); err != nil {
+		beego.Error("Failed to store status of new draft: ", err)
+	}
+	return nil
+}
+
+// UpdateDraft replaces the year, the contributions and the remark of a draft
+func UpdateDraft(request *models.Request) error {
+	o := orm.NewOrm()
+	stored := models.Request{Id: request.Id}
+	if err := o.Read(&stored); err != nil {
+		return err
+	}
+	if stored.Status != models.RequestStatusDraft {
+		return &RequestTransitionError{From: RequestStatus(&stored), To: models.RequestStatusDraft}
+	}
+	if request.Year != 0 && request.Year != stored.Year {
+		stored.Year = request.Year
+		if err := checkRequestYear(&stored); err != nil {
+			return err
+		}
+		stored.OpenYear = &stored.Year
+		if _, err := o.Update(&stored, "Year", "OpenYear"); err != nil {
+			return err
+		}
+	}
+	draft := newRequestDraft(request)
+	stored.ContributionCodes = contributionCodesColumn(request)
+	if _, err := o.Update(&stored, "ContributionCodes"); err != nil {
+		return err
+	}
+	_, err := o.QueryTable(new(models.RequestDraft)).Filter("Request", stored.Id).Update(orm.Params{
+		"ContributionCodes": draft.ContributionCodes,
+		"Remark":            draft.Remark,
+	})
+	if err != nil {
+		beego.Error("Failed to update draft: ", err)
+	}
+	return err
+}
+
+// SubmitRequest validates a draft and deploys its contract
+func SubmitRequest(requestId int64, auth *bind.TransactOpts) (*models.Request, error) {
+	o := orm.NewOrm()
+	var request models.Request
+	if err := o.QueryTable(new(models.Request)).Filter("Id", requestId).RelatedSel().One(&request); err != nil {
+		beego.Error("Error while fetching Request by ID: ", err)
+		return nil, err
+	}
+	if err := CheckRequestTransition(&request, models.RequestStatusSubmitted); err != nil {
+		return nil, err
+	}
+	if err := assignDraft(&request); err != nil {
+		return nil, err
+	}
+	if err := ValidateRequest(&request); err != nil {
+		return nil, err
+	}
+	// drafts stored before the year was kept are made for the current year
+	if err := checkRequestYear(&request); err != nil {
+		return nil, err
+	}
+	address, tx, err := deployRequestContract(&request, auth)
+	if err != nil {
+		return nil, err
+	}
+	request.Address = address.String()
+	request.OpenYear = &request.Year
+	request.ContributionCodes = contributionCodesColumn(&request)
+	if _, err := o.Update(&request, "Address", "Remark", "Year", "OpenYear", "ContributionCodes"); err != nil {
+		beego.Error("Failed to store address of submitted request: ", err)
+		return nil, err
+	}
+	if err := TransitionRequest(&request, models.RequestStatusSubmitted, auth.From.String(), ""); err != nil {
+		return nil, err
+	}
+	if _, err := o.QueryTable(new(models.RequestDraft)).Filter("Request", request.Id).Delete(); err != nil {
+		beego.Error("Failed to delete submitted draft: ", err)
+	}
+	watchRequest(&request)
+	return &request, trackDeployment(tx, &request, auth)
+}
+
+func newRequestDraft(request *models.Request) *models.RequestDraft {
+	codes := make([]string, len(request.Contributions))
+	for i, contribution := range request.Contributions {
+		codes[i] = strconv.Itoa(int(contribution.Code))
+	}
+	return &models.RequestDraft{Request: request, ContributionCodes: strings.Join(codes, ","), Remark: request.Remark}
+}
+
+// assignDraft fills the contributions and the remark of a request from its draft
+func assignDraft(request *models.Request) error {
+	o := orm.NewOrm()
+	var draft models.RequestDraft
+	if err := o.QueryTable(new(models.RequestDraft)).Filter("Request", request.Id).One(&draft); err != nil {
+		return err
+	}
+	request.Remark = draft.Remark
+	request.Contributions = make([]*models.Contribution, 0)
+	if draft.ContributionCodes == "" {
+		return nil
+	}
+	for _, code := range strings.Split(draft.ContributionCodes, ",") {
+		c, err := strconv.ParseUint(code, 10, 16)
+		if err != nil {
+			return err
+		}
+		contribution, err := GetContributionByCode(uint16(c))
+		if err != nil {
+			// unknown contributions are reported when the draft is validated
+			contribution = &models.Contribution{Code: uint16(c)}
+		}
+		request.Contributions = append(request.Contributions, contribution)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/scmo/apayment-backend/models"
+)
+
+// requestTransitions lists the states a request may move to from each state. An
+// amendment moves the request back to submitted, a failed transfer to the state
+// before the payment. Tranches between the first and the final one keep the request
+// in firstPaymentMade, a schedule of one tranche pays the final payment first.
+var requestTransitions = map[string][]string{
+	models.RequestStatusDraft:             {models.RequestStatusSubmitted, models.RequestStatusWithdrawn},
+	models.RequestStatusSubmitted:         {models.RequestStatusSubmitted, models.RequestStatusInspectorAssigned, models.RequestStatusWithdrawn, models.RequestStatusRejected},
+	models.RequestStatusInspectorAssigned: {models.RequestStatusSubmitted, models.RequestStatusInspectorAssigned, models.RequestStatusInspected, models.RequestStatusWithdrawn, models.RequestStatusRejected},
+	models.RequestStatusInspected:         {models.RequestStatusInspected, models.RequestStatusFirstPaymentMade, models.RequestStatusFinalPaymentMade, models.RequestStatusWithdrawn, models.RequestStatusRejected},
+	models.RequestStatusFirstPaymentMade:  {models.RequestStatusFirstPaymentMade, models.RequestStatusFinalPaymentMade, models.RequestStatusInspected},
+	models.RequestStatusFinalPaymentMade:  {models.RequestStatusClosed, models.RequestStatusFirstPaymentMade, models.RequestStatusInspected},
+}
+
+// RequestTransitionError is returned when a request cannot move to the requested state
+type RequestTransitionError struct {
+	From string
+	To   string
+}
+
+func (e *RequestTransitionError) Error() string {
+	return "Request cannot change from " + e.From + " to " + e.To
+}
+
+// RequestStatus returns the state of a request. Requests created before the
+// lifecycle was introduced have no state stored, it is inferred from their data.
+func RequestStatus(request *models.Request) string {
+	if request.Status != "" {
+		return request.Status
+	}
+	switch {
+	case len(request.Payments) >= 2:
+		return models.RequestStatusFinalPaymentMade
+	case len(request.Payments) == 1:
+		return models.RequestStatusFirstPaymentMade
+	case request.NumLacks > 0:
+		return models.RequestStatusInspected
+	case request.Inspector != nil:
+		return models.RequestStatusInspectorAssigned
+	}
+	return models.RequestStatusSubmitted
+}
+
+// CheckRequestTransition tells whether the request may move to the given state
+func CheckRequestTransition(request *models.Request, to string) error {
+	from := RequestStatus(request)
+	for _, allowed := range requestTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return &RequestTransitionError{From: from, To: to}
+}
+
+// TransitionRequest moves the request to the given state and records the transition.
+// actor is the address of the user causing it, empty for the system.
+func TransitionRequest(request *models.Request, to string, actor string, reason string) error {
+	return transitionRequest(request, to, actor, reason)
+}
+
+// transitionRequest moves the request and inserts the records in the same database transaction
+func transitionRequest(request *models.Request, to string, actor string, reason string, records ...interface{}) error {
+	if err := CheckRequestTransition(request, to); err != nil {
+		return err
+	}
+	transition := &models.RequestTransition{Request: request, From: RequestStatus(request), To: to, Actor: actor, Reason: reason}
+	o := orm.NewOrm()
+	if err := o.Begin(); err != nil {
+		return err
+	}
+	// only move the request if nobody else moved it in the meantime
+	params := orm.Params{"Status": to}
+	if to == models.RequestStatusWithdrawn || to == models.RequestStatusRejected {
+		// the farmer can make another request for the year
+		params["OpenYear"] = nil
+	}
+	num, err := o.QueryTable(new(models.Request)).Filter("Id", request.Id).Filter("Status", request.Status).Update(params)
+	if err == nil && num == 0 {
+		err = &RequestTransitionError{From: transition.From, To: to}
+	}
+	if err == nil {
+		_, err = o.Insert(transition)
+	}
+	for _, record := range records {
+		if err == nil {
+			_, err = o.Insert(record)
+		}
+	}
+	if err != nil {
+		o.Rollback()
+		beego.Error("Failed to change status of request ", request.Id, ": ", err)
+		return err
+	}
+	request.Status = to
+	if _, ok := params["OpenYear"]; ok {
+		request.OpenYear = nil
+	}
+	return o.Commit()
+}
+
+// startRequestLifecycle stores the first state of a new request
+func startRequestLifecycle(request *models.Request, actor string) error {
+	o := orm.NewOrm()
+	_, err := o.Insert(&models.RequestTransition{Request: request, To: request.Status, Actor: actor})
+	return err
+}
+
+// loadRequestTransitions attaches the transition history to the request
+func loadRequestTransitions(request *models.Request) {
+	o := orm.NewOrm()
+	if _, err := o.QueryTable(new(models.RequestTransition)).Filter("Request", request.Id).OrderBy("Id").All(&request.Transitions); err != nil {
+		beego.Error("Failed to load transitions of request ", request.Id, ": ", err)
+	}
+}
+
+// followPayment closes a request once its final payment is confirmed and moves it
+// back when a payment failed, so that the tranche can be paid again.
+func followPayment(transaction *models.EthereumTransaction) {
+	request := transaction.Request
+	var err error
+	switch {
+	case transaction.Status == models.TransactionStatusConfirmed && request.Status == models.RequestStatusFinalPaymentMade:
+		err = TransitionRequest(request, models.RequestStatusClosed, "", "Final payment confirmed")
+	case transaction.Status == models.TransactionStatusFailed && (request.Status == models.RequestStatusFirstPaymentMade || request.Status == models.RequestStatusFinalPaymentMade):
+		// the state of the tranches which did not fail
+		to := models.RequestStatusInspected
+		if transfers, _ := getRequestTransfers(request.Id); len(transfers) > 0 {
+			to = models.RequestStatusFirstPaymentMade
+		}
+		err = TransitionRequest(request, to, "", transaction.Detail+" failed")
+	}
+	if err != nil {
+		beego.Error("Failed to follow payment of request ", request.Id, ": ", err)
+	}
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/scmo/apayment-backend/models"
+)
+
+var requestSortFields = listFields{
+	"id":       "Id",
+	"year":     "Year",
+	"status":   "Status",
+	"created":  "ContractCreated",
+	"modified": "ContractModified",
+}
+
+var requestFilters = map[string]listFilter{
+	"year":         filterInt("Year"),
+	"status":       filterString("Status"),
+	"inspector":    filterInt("Inspector"),
+	"canton":       filterString("Canton"),
+	"contribution": filterContribution,
+	"createdFrom":  filterDate("ContractCreated", false),
+	"createdTo":    filterDate("ContractCreated", true),
+}
+
+// listRequests loads a page of requests from the read model, or from their contracts with chain consistency
+func listRequests(qs orm.QuerySeter, query *ListQuery, chain bool) (*Page, error) {
+	var requests []*models.Request
+	page, err := paginate(qs.RelatedSel(), query, requestFilters, requestSortFields, &requests)
+	if err != nil {
+		return nil, err
+	}
+	assignRequestList(requests, chain)
+	return page, nil
+}
+
+func filterContribution(qs orm.QuerySeter, value string) (orm.QuerySeter, error) {
+	code, err := strconv.ParseUint(value, 10, 16)
+	if err != nil {
+		return nil, &ListQueryError{Message: "Invalid contribution code " + value}
+	}
+	return qs.Filter("ContributionCodes__contains", ","+strconv.FormatUint(code, 10)+","), nil
+}
+
+// contributionCodesColumn joins the contribution codes of a request for the read model.
+// The codes are enclosed in commas, so that a single code can be matched.
+func contributionCodesColumn(request *models.Request) string {
+	codes := make([]string, len(request.Contributions))
+	for i, contribution := range request.Contributions {
+		codes[i] = strconv.Itoa(int(contribution.Code))
+	}
+	return "," + strings.Join(codes, ",") + ","
+}
+
+// farmCanton returns the canton of the farmer's animal husbandry as known to the TVD
+func farmCanton(user *models.User) string {
+	if user == nil || user.AnimalHusbandryDetailResult == nil {
+		return ""
+	}
+	return user.AnimalHusbandryDetailResult.CantonShortname
+}
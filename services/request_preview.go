@@ -0,0 +1,136 @@
+package services
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/scmo/apayment-backend/models"
+	"github.com/scmo/apayment-backend/services/calculation"
+)
+
+// PreviewPayments calculates the point groups and the payments of a request with the lacks
+// of the preview, like the contract would. Nothing is stored or sent.
+func PreviewPayments(preview *models.RequestPreview, user *models.User) (*calculation.Result, error) {
+	input := &calculation.Input{GVE: make(map[uint16]uint32)}
+	if preview.RequestId != 0 {
+		request := GetRequestById(preview.RequestId, false)
+		if request.Id == 0 {
+			return nil, orm.ErrNoRows
+		}
+		if !hasRequestContract(request) {
+			return nil, &RequestValidationError{Message: "Request " + strconv.FormatInt(request.Id, 10) + " has no contract"}
+		}
+		if err := loadPreviewContract(request, input); err != nil {
+			return nil, err
+		}
+		preview.ContributionCodes = getContributionCodes(request)
+	} else {
+		if err := validatePreviewContributions(preview.ContributionCodes); err != nil {
+			return nil, err
+		}
+		for _, gve := range preview.GVE {
+			if !isPreviewPointGroup(gve.PointGroupCode) {
+				return nil, &RequestValidationError{Message: "Unknown point group " + strconv.Itoa(int(gve.PointGroupCode))}
+			}
+			// the contract stores GVE multiplied by 10'000
+			amount := math.Round(gve.Amount * 10000)
+			if amount < 0 || amount > math.MaxUint32 {
+				return nil, &RequestValidationError{Message: "Invalid GVE of point group " + strconv.Itoa(int(gve.PointGroupCode))}
+			}
+			input.GVE[gve.PointGroupCode] = uint32(amount)
+		}
+		input.AmountPreviousYear = preview.AmountPreviousYear
+		if input.AmountPreviousYear == nil {
+			year := preview.Year
+			if year == 0 {
+				year = currentYear()
+			}
+			amount, err := getRequestAmountFromPreviousYear(user, year)
+			if err != nil {
+				beego.Error("Error to get amount from last year: ", err)
+				return nil, err
+			}
+			input.AmountPreviousYear = amount
+		}
+		if input.AmountPreviousYear.Sign() < 0 {
+			return nil, &RequestValidationError{Message: "Invalid amount of the previous year"}
+		}
+	}
+	for _, lack := range preview.Lacks {
+		if !hasContributionCode(preview.ContributionCodes, lack.ContributionCode) {
+			return nil, &RequestValidationError{Message: "Lack of contribution " + strconv.Itoa(int(lack.ContributionCode)) + " which is not requested"}
+		}
+		if !isPreviewPointGroup(lack.PointGroupCode) {
+			return nil, &RequestValidationError{Message: "Unknown point group " + strconv.Itoa(int(lack.PointGroupCode))}
+		}
+		if lack.Points == 0 {
+			return nil, &RequestValidationError{Message: "A lack needs points"}
+		}
+		input.Lacks = append(input.Lacks, calculation.Lack{ContributionCode: lack.ContributionCode, PointGroupCode: lack.PointGroupCode, Points: lack.Points})
+	}
+	return calculation.Calculate(input), nil
+}
+
+// loadPreviewContract starts the calculation from the GVE, the amount of the previous year
+// and the lacks stored in the contract of the request
+func loadPreviewContract(request *models.Request, input *calculation.Input) error {
+	requestContract, err := getRequestContractByAddress(request.Address)
+	if err != nil {
+		beego.Error("Error while fetching RequestContract by Address: ", err)
+		return err
+	}
+	session := getRequestContractSession(requestContract)
+	for _, code := range calculation.PointGroupCodes {
+		pointGroup, err := session.PointGroups(code)
+		if err != nil {
+			beego.Error("Error while reading point group ", code, ": ", err)
+			return err
+		}
+		input.GVE[code] = pointGroup.Gve
+	}
+	if input.AmountPreviousYear, err = session.AmountPreviousYear(); err != nil {
+		return err
+	}
+	numLacks, err := session.NumLacks()
+	if err != nil {
+		return err
+	}
+	for i := int64(0); i < numLacks.Int64(); i++ {
+		lack, err := session.Lacks(big.NewInt(i))
+		if err != nil {
+			return err
+		}
+		// reversed lacks are stored without points
+		input.Lacks = append(input.Lacks, calculation.Lack{ContributionCode: lack.ContributionCode, PointGroupCode: lack.PointGroupCode, Points: lack.Points})
+	}
+	return nil
+}
+
+func validatePreviewContributions(codes []uint16) error {
+	if len(codes) == 0 {
+		return &RequestValidationError{Message: "No contribution requested"}
+	}
+	o := orm.NewOrm()
+	for _, code := range codes {
+		if !o.QueryTable(new(models.Contribution)).Filter("Code", code).Exist() {
+			return &RequestValidationError{Message: "Unknown contribution " + strconv.Itoa(int(code))}
+		}
+	}
+	return nil
+}
+
+func hasContributionCode(codes []uint16, code uint16) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+func isPreviewPointGroup(code uint16) bool {
+	return hasContributionCode(calculation.PointGroupCodes, code)
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/scmo/apayment-backend/models"
+	"github.com/scmo/apayment-backend/services/tvd"
+)
+
+// ConsistencyChain is the value of the consistency query option for reads from the contracts
+const ConsistencyChain = "chain"
+
+// SyncRequests copies the state of every request contract into the read model
+func SyncRequests() error {
+	o := orm.NewOrm()
+	var requests []*models.Request
+	_, err := o.QueryTable(new(models.Request)).All(&requests)
+	if err != nil {
+		beego.Error("Failed to load requests to sync: ", err)
+		return err
+	}
+	for _, request := range requests {
+		if !hasRequestContract(request) {
+			continue
+		}
+		if err := syncRequest(request); err != nil {
+			beego.Error("Failed to sync request ", request.Id, ": ", err)
+		}
+	}
+	return nil
+}
+
+// syncRequest copies the remark, the timestamps and the contribution codes of the contract of a request into the database.
+// Requests made before their canton was kept get the canton of the farm.
+func syncRequest(request *models.Request) error {
+	requestContract, err := getRequestContractByAddress(request.Address)
+	if err != nil {
+		return err
+	}
+	remark, err := requestContract.Remark(nil)
+	if err != nil {
+		return err
+	}
+	created, err := requestContract.Created(nil)
+	if err != nil {
+		return err
+	}
+	modified, err := requestContract.Modified(nil)
+	if err != nil {
+		return err
+	}
+	contributions := make([]*models.Contribution, 0)
+	for i := int64(0); ; i++ {
+		code, err := requestContract.ContributionCodes(nil, big.NewInt(i))
+		if err != nil {
+			// reading past the end of the array fails
+			break
+		}
+		contributions = append(contributions, &models.Contribution{Code: code})
+	}
+	request.Remark = remark
+	request.ContractCreated = created.Uint64()
+	request.ContractModified = modified.Uint64()
+	request.ContributionCodes = contributionCodesColumn(&models.Request{Contributions: contributions})
+	fields := []string{"Remark", "ContractCreated", "ContractModified", "ContributionCodes"}
+	if request.Canton == "" {
+		if request.Canton = requestFarmCanton(request); request.Canton != "" {
+			fields = append(fields, "Canton")
+		}
+	}
+	o := orm.NewOrm()
+	_, err = o.Update(request, fields...)
+	if err == nil && request.Year == 0 {
+		err = backfillRequestYear(request)
+	}
+	return err
+}
+
+// requestFarmCanton reads the canton of the farm of a request from the TVD
+func requestFarmCanton(request *models.Request) string {
+	farmer := models.User{Id: request.User.Id}
+	o := orm.NewOrm()
+	if err := o.Read(&farmer); err != nil || farmer.TVD == 0 {
+		return ""
+	}
+	detail, err := tvd.GetAnimalHusbandryDetailFromTVD(farmer.TVD)
+	if err != nil {
+		beego.Error("Failed to read the farm of request ", request.Id, " from the TVD: ", err)
+		return ""
+	}
+	farmer.AnimalHusbandryDetailResult = detail
+	return farmCanton(&farmer)
+}
+
+// backfillRequestYear stores the year of a request made before the year was kept, the year
+// its contract was created in
+func backfillRequestYear(request *models.Request) error {
+	if request.ContractCreated == 0 {
+		if !hasRequestContract(request) {
+			return &RequestValidationError{Message: "Request " + strconv.FormatInt(request.Id, 10) + " has no year"}
+		}
+		requestContract, err := getRequestContractByAddress(request.Address)
+		if err != nil {
+			return err
+		}
+		created, err := requestContract.Created(nil)
+		if err != nil {
+			return err
+		}
+		request.ContractCreated = created.Uint64()
+	}
+	oc, _ := time.LoadLocation("Europe/Zurich")
+	request.Year = time.Unix(int64(request.ContractCreated), 0).In(oc).Year()
+	fields := []string{"Year", "ContractCreated"}
+	if RequestStatus(request) != models.RequestStatusWithdrawn && RequestStatus(request) != models.RequestStatusRejected {
+		request.OpenYear = &request.Year
+		fields = append(fields, "OpenYear")
+	}
+	o := orm.NewOrm()
+	_, err := o.Update(request, fields...)
+	if err != nil && request.OpenYear != nil {
+		// requests made twice for a year before the year was kept keep the year open once
+		beego.Warning("Request ", request.Id, " is another request of its farmer for ", request.Year, ": ", err)
+		request.OpenYear = nil
+		_, err = o.Update(request, "Year", "ContractCreated")
+	}
+	if err != nil {
+		beego.Error("Failed to store year of request ", request.Id, ": ", err)
+	}
+	return err
+}
+
+// assignRequestList fills the requests of a list from the read model, or from their
+// contracts with chain consistency
+func assignRequestList(requests []*models.Request, chain bool) {
+	for _, request := range requests {
+		if chain || !hasRequestContract(request) {
+			assignRequestContent(request, false)
+			continue
+		}
+		request.Created = new(big.Int).SetUint64(request.ContractCreated)
+		request.Modified = new(big.Int).SetUint64(request.ContractModified)
+	}
+}
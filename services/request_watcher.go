@@ -0,0 +1,335 @@
+package services
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/scmo/apayment-backend/ethereum"
+	"github.com/scmo/apayment-backend/models"
+	"github.com/scmo/apayment-backend/smart-contracts/direct-payment-request"
+)
+
+// RequestEventHandler applies the events of a Request contract. Events may be
+// delivered more than once, handlers have to be idempotent.
+type RequestEventHandler interface {
+	InspectorAssigned(event *directpaymentrequest.RequestContractInspectorAssigned) error
+	LacksAdded(event *directpaymentrequest.RequestContractLacksAdded) error
+	GVESet(event *directpaymentrequest.RequestContractGVESet) error
+	LackReversed(event *directpaymentrequest.RequestContractLackReversed) error
+}
+
+var requestWatches = struct {
+	sync.Mutex
+	subscriptions map[int64]event.Subscription
+}{subscriptions: make(map[int64]event.Subscription)}
+
+// WatchRequest replays the events of a Request contract since fromBlock and then
+// passes new events to the handler until the subscription is cancelled.
+func WatchRequest(address common.Address, backend bind.ContractFilterer, fromBlock uint64, handler RequestEventHandler) (event.Subscription, error) {
+	filterer, err := directpaymentrequest.NewRequestContractFilterer(address, backend)
+	if err != nil {
+		return nil, err
+	}
+	// Subscribe before replaying, events mined in between are delivered twice instead of never
+	inspectorAssigned := make(chan *directpaymentrequest.RequestContractInspectorAssigned)
+	lacksAdded := make(chan *directpaymentrequest.RequestContractLacksAdded)
+	gveSet := make(chan *directpaymentrequest.RequestContractGVESet)
+	lackReversed := make(chan *directpaymentrequest.RequestContractLackReversed)
+	subscriptions := make([]event.Subscription, 0, 4)
+	unsubscribe := func() {
+		for _, subscription := range subscriptions {
+			subscription.Unsubscribe()
+		}
+	}
+	subscription, err := filterer.WatchInspectorAssigned(nil, inspectorAssigned, nil)
+	if err != nil {
+		return nil, err
+	}
+	subscriptions = append(subscriptions, subscription)
+	subscription, err = filterer.WatchLacksAdded(nil, lacksAdded, nil)
+	if err != nil {
+		unsubscribe()
+		return nil, err
+	}
+	subscriptions = append(subscriptions, subscription)
+	subscription, err = filterer.WatchGVESet(nil, gveSet, nil)
+	if err != nil {
+		unsubscribe()
+		return nil, err
+	}
+	subscriptions = append(subscriptions, subscription)
+	subscription, err = filterer.WatchLackReversed(nil, lackReversed, nil, nil)
+	if err != nil {
+		unsubscribe()
+		return nil, err
+	}
+	subscriptions = append(subscriptions, subscription)
+
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer unsubscribe()
+		if err := replayRequestEvents(filterer, fromBlock, handler); err != nil {
+			return err
+		}
+		for {
+			select {
+			case e := <-inspectorAssigned:
+				logHandlerError(handler.InspectorAssigned(e))
+			case e := <-lacksAdded:
+				logHandlerError(handler.LacksAdded(e))
+			case e := <-gveSet:
+				logHandlerError(handler.GVESet(e))
+			case e := <-lackReversed:
+				logHandlerError(handler.LackReversed(e))
+			case err := <-subscriptions[0].Err():
+				return err
+			case err := <-subscriptions[1].Err():
+				return err
+			case err := <-subscriptions[2].Err():
+				return err
+			case err := <-subscriptions[3].Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+func replayRequestEvents(filterer *directpaymentrequest.RequestContractFilterer, fromBlock uint64, handler RequestEventHandler) error {
+	opts := &bind.FilterOpts{Start: fromBlock}
+	gveSet, err := filterer.FilterGVESet(opts, nil)
+	if err != nil {
+		return err
+	}
+	for gveSet.Next() {
+		logHandlerError(handler.GVESet(gveSet.Event))
+	}
+	gveSet.Close()
+
+	inspectorAssigned, err := filterer.FilterInspectorAssigned(opts, nil)
+	if err != nil {
+		return err
+	}
+	for inspectorAssigned.Next() {
+		logHandlerError(handler.InspectorAssigned(inspectorAssigned.Event))
+	}
+	inspectorAssigned.Close()
+
+	lacksAdded, err := filterer.FilterLacksAdded(opts, nil)
+	if err != nil {
+		return err
+	}
+	for lacksAdded.Next() {
+		logHandlerError(handler.LacksAdded(lacksAdded.Event))
+	}
+	lacksAdded.Close()
+
+	// lacks are reversed after they were added
+	lackReversed, err := filterer.FilterLackReversed(opts, nil, nil)
+	if err != nil {
+		return err
+	}
+	for lackReversed.Next() {
+		logHandlerError(handler.LackReversed(lackReversed.Event))
+	}
+	return lackReversed.Close()
+}
+
+func logHandlerError(err error) {
+	if err != nil {
+		beego.Error("Failed to apply request event: ", err)
+	}
+}
+
+// WatchRequests subscribes to the events of every request that is not watched yet.
+// Failed subscriptions are dropped and renewed on the next run.
+func WatchRequests() error {
+	o := orm.NewOrm()
+	var requests []*models.Request
+	_, err := o.QueryTable(new(models.Request)).All(&requests)
+	if err != nil {
+		beego.Error("Failed to load requests to watch: ", err)
+		return err
+	}
+	for _, request := range requests {
+		if hasRequestContract(request) {
+			watchRequest(request)
+		}
+	}
+	return nil
+}
+
+func watchRequest(request *models.Request) {
+	requestWatches.Lock()
+	defer requestWatches.Unlock()
+	if _, ok := requestWatches.subscriptions[request.Id]; ok {
+		return
+	}
+	ethereumController := ethereum.GetEthereumController()
+	handler := &requestReadModel{requestId: request.Id, address: request.Address}
+	subscription, err := WatchRequest(common.HexToAddress(request.Address), ethereumController.Client, request.SyncedBlock, handler)
+	if err != nil {
+		beego.Error("Failed to watch request ", request.Id, ": ", err)
+		return
+	}
+	requestWatches.subscriptions[request.Id] = subscription
+	go func() {
+		if err := <-subscription.Err(); err != nil {
+			beego.Error("Subscription to request ", request.Id, " failed: ", err)
+		}
+		requestWatches.Lock()
+		// the request may have been watched again in the meantime
+		if requestWatches.subscriptions[request.Id] == subscription {
+			delete(requestWatches.subscriptions, request.Id)
+		}
+		requestWatches.Unlock()
+	}()
+}
+
+// unwatchRequest cancels the subscription of a request, WatchRequests subscribes again
+func unwatchRequest(requestId int64) {
+	requestWatches.Lock()
+	subscription, ok := requestWatches.subscriptions[requestId]
+	delete(requestWatches.subscriptions, requestId)
+	requestWatches.Unlock()
+	if ok {
+		subscription.Unsubscribe()
+	}
+}
+
+// requestReadModel stores the events of a Request contract in the database
+type requestReadModel struct {
+	requestId int64
+	address   string
+}
+
+func (m *requestReadModel) InspectorAssigned(e *directpaymentrequest.RequestContractInspectorAssigned) error {
+	event := &models.ContractEvent{Name: models.ContractEventInspectorAssigned, Inspector: e.Inspector.String()}
+	return m.apply(e.Raw, event, func(o orm.Ormer, request *models.Request) error {
+		inspector := models.User{EtherumAddress: e.Inspector.String()}
+		if err := o.Read(&inspector, "EtherumAddress"); err != nil {
+			return errors.New("unknown inspector " + e.Inspector.String() + ": " + err.Error())
+		}
+		request.Inspector = &inspector
+		_, err := o.Update(request, "Inspector")
+		return err
+	})
+}
+
+func (m *requestReadModel) LacksAdded(e *directpaymentrequest.RequestContractLacksAdded) error {
+	event := &models.ContractEvent{Name: models.ContractEventLacksAdded, Inspector: e.Inspector.String(), NumLacks: e.NumLacks.Int64()}
+	return m.apply(e.Raw, event, func(o orm.Ormer, request *models.Request) error {
+		requestContract, err := getRequestContractByAddress(m.address)
+		if err != nil {
+			return err
+		}
+		// read the lacks as they were when the event was emitted
+		opts := &bind.CallOpts{BlockNumber: new(big.Int).SetUint64(e.Raw.BlockNumber)}
+		for i := request.NumLacks; i < e.NumLacks.Int64(); i++ {
+			lack, err := requestContract.Lacks(opts, big.NewInt(i))
+			if err != nil {
+				return err
+			}
+			requestLack := models.RequestLack{
+				Request:           request,
+				Index:             i,
+				ContributionCode:  lack.ContributionCode,
+				ControlCategoryId: lack.ControlCategoryId,
+				PointGroupCode:    lack.PointGroupCode,
+				ControlPointId:    lack.ControlPointId,
+				LackId:            lack.LackId,
+				Points:            lack.Points,
+			}
+			if _, err := o.Insert(&requestLack); err != nil {
+				return err
+			}
+			request.NumLacks = i + 1
+		}
+		_, err = o.Update(request, "NumLacks")
+		return err
+	})
+}
+
+func (m *requestReadModel) GVESet(e *directpaymentrequest.RequestContractGVESet) error {
+	event := &models.ContractEvent{Name: models.ContractEventGVESet, PointGroupCode: e.PointGroupCode, Gve: e.Gve}
+	return m.apply(e.Raw, event, func(o orm.Ormer, request *models.Request) error {
+		pointGroup := models.RequestPointGroup{Request: request, PointGroupCode: e.PointGroupCode}
+		if _, _, err := o.ReadOrCreate(&pointGroup, "Request", "PointGroupCode"); err != nil {
+			return err
+		}
+		pointGroup.Gve = e.Gve
+		_, err := o.Update(&pointGroup, "Gve")
+		return err
+	})
+}
+
+func (m *requestReadModel) LackReversed(e *directpaymentrequest.RequestContractLackReversed) error {
+	event := &models.ContractEvent{Name: models.ContractEventLackReversed, LackIndex: e.Index.Int64(), Reason: e.Reason}
+	return m.apply(e.Raw, event, func(o orm.Ormer, request *models.Request) error {
+		_, err := o.QueryTable(new(models.RequestLack)).Filter("Request", request.Id).Filter("Index", e.Index.Int64()).Update(orm.Params{"Reversed": true})
+		return err
+	})
+}
+
+// apply records the event and updates the read model in one database transaction.
+// Events that have been applied before or belong to an orphaned block are skipped.
+func (m *requestReadModel) apply(raw types.Log, event *models.ContractEvent, update func(o orm.Ormer, request *models.Request) error) error {
+	if raw.Removed {
+		// the indexer rolls back the state of orphaned blocks
+		return nil
+	}
+	indexer.Lock()
+	defer indexer.Unlock()
+
+	o := orm.NewOrm()
+	var block models.IndexedBlock
+	err := o.QueryTable(new(models.IndexedBlock)).Filter("Number", raw.BlockNumber).One(&block)
+	if err == nil && block.Hash != raw.BlockHash.Hex() {
+		beego.Warning("Event of orphaned block skipped: ", raw.BlockHash.Hex())
+		return nil
+	}
+	if o.QueryTable(new(models.ContractEvent)).Filter("BlockHash", raw.BlockHash.Hex()).Filter("LogIndex", raw.Index).Exist() {
+		return nil
+	}
+
+	request := models.Request{Id: m.requestId}
+	if err := o.Read(&request); err != nil {
+		return err
+	}
+	event.Request = &request
+	event.BlockNumber = raw.BlockNumber
+	event.BlockHash = raw.BlockHash.Hex()
+	event.TransactionHash = raw.TxHash.Hex()
+	event.LogIndex = raw.Index
+
+	if err := o.Begin(); err != nil {
+		return err
+	}
+	if _, err := o.Insert(event); err != nil {
+		o.Rollback()
+		return err
+	}
+	if err := update(o, &request); err != nil {
+		o.Rollback()
+		return err
+	}
+	// the request is reconciled again with the changed read model
+	if err := dropReconciliation(o, request.Id); err != nil {
+		o.Rollback()
+		return err
+	}
+	// watching restarts from the block of the last applied event
+	_, err = o.QueryTable(new(models.Request)).Filter("Id", m.requestId).Filter("SyncedBlock__lt", raw.BlockNumber).Update(orm.Params{"SyncedBlock": raw.BlockNumber})
+	if err != nil {
+		o.Rollback()
+		return err
+	}
+	return o.Commit()
+}
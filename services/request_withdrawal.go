@@ -0,0 +1,85 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/scmo/apayment-backend/models"
+)
+
+// WithdrawRequest ends a request on behalf of its farmer, auth is the key of the farmer
+func WithdrawRequest(decision *models.RequestDecision, actor string, auth *bind.TransactOpts) (*models.Request, error) {
+	return endRequest(decision, models.RequestStatusWithdrawn, actor, auth)
+}
+
+// RejectRequest ends a request on behalf of the canton, auth is the key of the admin or
+// canton employee rejecting it
+func RejectRequest(decision *models.RequestDecision, actor string, auth *bind.TransactOpts) (*models.Request, error) {
+	return endRequest(decision, models.RequestStatusRejected, actor, auth)
+}
+
+// endRequest moves a request which has not been paid to withdrawn or rejected. Its
+// contract is killed with auth, the farmer who deployed it or an admin or canton employee.
+func endRequest(decision *models.RequestDecision, status string, actor string, auth *bind.TransactOpts) (*models.Request, error) {
+	if strings.TrimSpace(decision.Reason) == "" {
+		return nil, &RequestValidationError{Message: "A reason is required"}
+	}
+	o := orm.NewOrm()
+	var request models.Request
+	if err := o.QueryTable(new(models.Request)).Filter("Id", decision.RequestId).RelatedSel().One(&request); err != nil {
+		beego.Error("Error while fetching Request by ID: ", err)
+		return nil, err
+	}
+	if err := CheckRequestTransition(&request, status); err != nil {
+		return nil, err
+	}
+	// drafts have no contract yet
+	hasContract := RequestStatus(&request) != models.RequestStatusDraft
+	records := make([]interface{}, 0)
+	if hasContract {
+		draft, err := archiveRequestContent(&request)
+		if err != nil {
+			beego.Error("Failed to archive request ", request.Id, ": ", err)
+			return nil, err
+		}
+		records = append(records, draft)
+	}
+	// the archive is stored with the transition, a request ended twice is archived once
+	if err := transitionRequest(&request, status, actor, decision.Reason, records...); err != nil {
+		return nil, err
+	}
+	closeRequestAppointments(request.Id, models.AppointmentStatusCancelled)
+	if hasContract {
+		unwatchRequest(request.Id)
+		scheduleContractKill(request.Address, &request, auth.From.String())
+	}
+	return &request, nil
+}
+
+// archiveRequestContent reads the contributions and the remark of a request whose
+// contract is about to be killed into a draft
+func archiveRequestContent(request *models.Request) (*models.RequestDraft, error) {
+	requestContract, err := getRequestContractByAddress(request.Address)
+	if err != nil {
+		return nil, err
+	}
+	session := getRequestContractSession(requestContract)
+	remark, err := session.Remark()
+	if err != nil {
+		return nil, err
+	}
+	request.Remark = remark
+	setContributions(request, session)
+	return newRequestDraft(request), nil
+}
+
+// hasRequestContract tells whether the request has a contract which can be read
+func hasRequestContract(request *models.Request) bool {
+	switch request.Status {
+	case models.RequestStatusDraft, models.RequestStatusWithdrawn, models.RequestStatusRejected:
+		return false
+	}
+	return true
+}
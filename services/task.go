@@ -0,0 +1,25 @@
+package services
+
+import (
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/toolbox"
+)
+
+// InitTasks registers the background jobs, they are started with toolbox.StartTask.
+func InitTasks() {
+	addTask("transactionTracker", beego.AppConfig.DefaultString("transactionTrackerSpec", "*/15 * * * * *"), UpdatePendingTransactions)
+	addTask("gasTopUp", beego.AppConfig.DefaultString("gasTopUpSpec", "0 */10 * * * *"), TopUpUserAccounts)
+	addTask("blockIndexer", beego.AppConfig.DefaultString("blockIndexerSpec", "*/15 * * * * *"), IndexBlocks)
+	addTask("requestWatcher", beego.AppConfig.DefaultString("requestWatcherSpec", "0 * * * * *"), WatchRequests)
+	addTask("requestSync", beego.AppConfig.DefaultString("requestSyncSpec", "0 */10 * * * *"), SyncRequests)
+	addTask("reconciliation", beego.AppConfig.DefaultString("reconciliationSpec", "0 5 * * * *"), ReconcileRequests)
+	addTask("payouts", beego.AppConfig.DefaultString("payoutReconciliationSpec", "0 */5 * * * *"), ReconcilePayouts)
+	addTask("contractKills", beego.AppConfig.DefaultString("contractKillSpec", "0 */5 * * * *"), RetryContractKills)
+	addTask("paymentRunPlanning", beego.AppConfig.DefaultString("paymentRunPlanningSpec", "0 * * * * *"), PlanPaymentRuns)
+	addTask("payoutExecution", beego.AppConfig.DefaultString("payoutExecutionSpec", "*/30 * * * * *"), ExecuteApprovedProposals)
+}
+
+func addTask(name string, spec string, f toolbox.TaskFunc) {
+	beego.Info("Add task ", name, ": ", spec)
+	toolbox.AddTask(name, toolbox.NewTask(name, spec, f))
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/orm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/scmo/apayment-backend/ethereum"
+	"github.com/scmo/apayment-backend/models"
+)
+
+// TrackTransaction stores a sent transaction, its status is updated by UpdatePendingTransactions.
+// Kind, From, Request, Account and Detail have to be set by the caller.
+func TrackTransaction(tx *types.Transaction, transaction *models.EthereumTransaction) error {
+	transaction.Hash = tx.Hash().String()
+	transaction.Value = tx.Value().String()
+	transaction.Status = models.TransactionStatusPending
+	if tx.To() != nil {
+		transaction.To = tx.To().String()
+	}
+	o := orm.NewOrm()
+	_, err := o.Insert(transaction)
+	if err != nil {
+		beego.Error("Failed to track transaction: ", err)
+	}
+	return err
+}
+
+// UpdatePendingTransactions follows the pending and mined transactions until they are confirmed.
+// A mined transaction whose receipt disappears has been reorganised out and is pending again.
+func UpdatePendingTransactions() error {
+	o := orm.NewOrm()
+	var transactions []*models.EthereumTransaction
+	_, err := o.QueryTable(new(models.EthereumTransaction)).Filter("Status__in", models.TransactionStatusPending, models.TransactionStatusMined).RelatedSel().All(&transactions)
+	if err != nil {
+		beego.Error("Failed to load pending transactions: ", err)
+		return err
+	}
+	head, err := ethereum.HeadBlockNumber()
+	if err != nil {
+		return err
+	}
+	ethereumController := ethereum.GetEthereumController()
+	for _, transaction := range transactions {
+		receipt, err := ethereumController.Client.TransactionReceipt(context.Background(), common.HexToHash(transaction.Hash))
+		status := transaction.Status
+		switch {
+		case err != nil || receipt == nil:
+			if transaction.Status == models.TransactionStatusMined {
+				beego.Warning("Transaction removed by reorg: ", transaction.Hash)
+				transaction.Status = models.TransactionStatusPending
+				transaction.Block = 0
+			}
+		case receipt.Status == types.ReceiptStatusFailed:
+			beego.Error("Transaction failed: ", transaction.Hash)
+			transaction.Status = models.TransactionStatusFailed
+		case transaction.Status == models.TransactionStatusPending:
+			transaction.Status = models.TransactionStatusMined
+			transaction.Block = head
+			if len(receipt.Logs) > 0 {
+				transaction.Block = receipt.Logs[0].BlockNumber
+			}
+		}
+		if transaction.Status == models.TransactionStatusMined && head >= transaction.Block+ethereum.ConfirmationDepth() {
+			transaction.Status = models.TransactionStatusConfirmed
+		}
+		if status == transaction.Status {
+			continue
+		}
+		if _, err := o.Update(transaction, "Status", "Block", "Updated"); err != nil {
+			beego.Error("Failed to update transaction: ", err)
+			continue
+		}
+		if transaction.Status == models.TransactionStatusConfirmed {
+			publishTransactionEvent(transaction)
+		}
+		// the transactions of a request change the state its list entry is read from
+		if transaction.Status == models.TransactionStatusMined && transaction.Request != nil && hasRequestContract(transaction.Request) {
+			if err := syncRequest(transaction.Request); err != nil {
+				beego.Error("Failed to sync request ", transaction.Request.Id, ": ", err)
+			}
+		}
+		if transaction.Kind == models.TransactionKindKill {
+			followContractKill(transaction)
+		}
+		if transaction.Kind == models.TransactionKindTransfer && transaction.Request != nil {
+			followPayout(transaction)
+			followPayment(transaction)
+		}
+	}
+	return nil
+}
+
+// HasUnconfirmedPayment tells whether a payment of the request has been reserved or sent but is not confirmed yet
+func HasUnconfirmedPayment(requestId int64) bool {
+	o := orm.NewOrm()
+	if o.QueryTable(new(models.Payout)).Filter("Request", requestId).Filter("Status", models.PayoutStatusReserved).Exist() {
+		return true
+	}
+	return o.QueryTable(new(models.EthereumTransaction)).Filter("Kind", models.TransactionKindTransfer).Filter("Request", requestId).Filter("Status__in", models.TransactionStatusPending, models.TransactionStatusMined).Exist()
+}
+
+// rollbackTransactions sets the transactions mined after the given block back to pending
+func rollbackTransactions(blockNumber uint64) error {
+	o := orm.NewOrm()
+	_, err := o.QueryTable(new(models.EthereumTransaction)).Filter("Status__in", models.TransactionStatusMined, models.TransactionStatusConfirmed).Filter("Block__gt", blockNumber).Update(orm.Params{"Status": models.TransactionStatusPending, "Block": 0})
+	return err
+}
+
+// GetTransactionValueSince sums the value of all transactions of a kind sent to an address since the given time.
+func GetTransactionValueSince(kind string, to string, since time.Time) (*big.Int, error) {
+	o := orm.NewOrm()
+	var transactions []*models.EthereumTransaction
+	_, err := o.QueryTable(new(models.EthereumTransaction)).Filter("Kind", kind).Filter("To", to).Filter("Created__gte", since).Exclude("Status", models.TransactionStatusFailed).All(&transactions)
+	total := big.NewInt(0)
+	for _, transaction := range transactions {
+		value, ok := new(big.Int).SetString(transaction.Value, 10)
+		if ok {
+			total.Add(total, value)
+		}
+	}
+	return total, err
+}
@@ -71,9 +71,10 @@ func getFieldTestGVE() (map[uint16]uint32, error) {
 }
 
 /*
-	Calculates the GVE from previous year.
+	Calculates the GVE from the year before the request year. Ages are taken at the end
+	of that year, so the calculation for a past request year can be repeated.
 */
-func GetNumberOfGVELastYear(userTvd int32) (map[uint16]uint32, error) {
+func GetNumberOfGVELastYear(userTvd int32, requestYear int) (map[uint16]uint32, error) {
 	// FOR FIELD TEST
 	if userTvd == 0 {
 		return getFieldTestGVE()
@@ -89,8 +90,8 @@ func GetNumberOfGVELastYear(userTvd int32) (map[uint16]uint32, error) {
 	a9 := float32(0) // a9 1144   männliche Tiere, bis 160 Tage alt (nur RAUS)
 
 	oc, _ := time.LoadLocation("Europe/Zurich")
-	begin := time.Date(time.Now().Year()-1, time.Month(1), 0, 0, 0, 0, 0, oc)
-	end := time.Date(time.Now().Year()-1, time.Month(12), 30, 23, 59, 0, 0, oc)
+	begin := time.Date(requestYear-1, time.Month(1), 0, 0, 0, 0, 0, oc)
+	end := time.Date(requestYear-1, time.Month(12), 30, 23, 59, 0, 0, oc)
 
 	cattleLivestockV2Response, err := GetUserCattleLivestock(userTvd, begin, end)
 	if err != nil {
@@ -99,11 +100,11 @@ func GetNumberOfGVELastYear(userTvd int32) (map[uint16]uint32, error) {
 		return getFieldTestGVE()
 	}
 	for _, cattleLiveStockDataItem := range cattleLivestockV2Response.GetCattleLivestockV2Result.Resultdetails.CattleLivestockDataItem {
-		cat, err := GetAnimalCategory(cattleLiveStockDataItem)
+		cat, err := GetAnimalCategoryAt(cattleLiveStockDataItem, end)
 		if err != nil {
 			return nil, err
 		}
-		days, err := cattleLiveStockDataItem.getStayLengthInDays()
+		days, err := cattleLiveStockDataItem.getStayLengthInDays(requestYear - 1)
 
 		gve := float32(days) / 365
 		switch cat {
@@ -145,7 +146,12 @@ func GetNumberOfGVELastYear(userTvd int32) (map[uint16]uint32, error) {
 
 
 func GetAnimalCategory(cattleLiveStockDataItem *CattleLivestockDataV2) (uint8, error) {
-	ageInDays, err := getAgeInDays(cattleLiveStockDataItem)
+	return GetAnimalCategoryAt(cattleLiveStockDataItem, time.Now())
+}
+
+// GetAnimalCategoryAt returns the category of the animal by its age at the given time
+func GetAnimalCategoryAt(cattleLiveStockDataItem *CattleLivestockDataV2, at time.Time) (uint8, error) {
+	ageInDays, err := getAgeInDays(cattleLiveStockDataItem, at)
 	if err != nil {
 		return 0, err
 	}
@@ -196,7 +202,7 @@ func GetAnimalCategory(cattleLiveStockDataItem *CattleLivestockDataV2) (uint8, e
 	return 0, errors.New("No Gender specified")
 }
 
-func getAgeInDays(cattleLiveStockDataItem *CattleLivestockDataV2) (uint32, error) {
+func getAgeInDays(cattleLiveStockDataItem *CattleLivestockDataV2, at time.Time) (uint32, error) {
 	if cattleLiveStockDataItem.BirthDate == "" {
 		return 0, nil
 	}
@@ -205,10 +211,11 @@ func getAgeInDays(cattleLiveStockDataItem *CattleLivestockDataV2) (uint32, error
 		beego.Error("Error while converting birthdate to days: ", err)
 		return 0, err
 	}
-	return uint32(time.Now().Sub(birthdate).Hours() / 24), nil
+	return uint32(at.Sub(birthdate).Hours() / 24), nil
 }
 
-func (cattleLiveStockDataItem *CattleLivestockDataV2) getStayLengthInDays() (uint16, error) {
+// getStayLengthInDays counts the days the animal stayed until it left or the end of the given year
+func (cattleLiveStockDataItem *CattleLivestockDataV2) getStayLengthInDays(year int) (uint16, error) {
 	layout := "2006-01-02T00:00:00"
 	arrival, err := time.Parse(layout, cattleLiveStockDataItem.ArrivalDate)
 	if err != nil {
@@ -216,7 +223,7 @@ func (cattleLiveStockDataItem *CattleLivestockDataV2) getStayLengthInDays() (uin
 		return 0, err
 	}
 	oc, _ := time.LoadLocation("Europe/Zurich")
-	leaving := time.Date(time.Now().Year()-1, time.Month(12), 30, 23, 59, 0, 0, oc)
+	leaving := time.Date(year, time.Month(12), 30, 23, 59, 0, 0, oc)
 	if cattleLiveStockDataItem.LeavingDate != "" {
 		leaving, err = time.Parse(layout, cattleLiveStockDataItem.LeavingDate)
 		if err != nil {
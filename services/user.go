@@ -7,13 +7,11 @@ import (
 	"github.com/astaxie/beego/orm"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/params"
 	"github.com/scmo/apayment-backend/ethereum"
 	"github.com/scmo/apayment-backend/models"
 	"github.com/scmo/apayment-backend/services/tvd"
 	"github.com/scmo/apayment-backend/smart-contracts/rbac"
 	"golang.org/x/crypto/bcrypt"
-	"math/big"
 )
 
 func CreateUser(u *models.User) error {
@@ -74,26 +72,26 @@ func addUserToRBAC(address string, role string) {
 		tx, err = rbacContract.AddCantonEmployee(ethereumController.Auth, common.HexToAddress(address))
 	default:
 		beego.Error("Unknown role - address was not added to the RoleBasedAccessControl")
+		return
 	}
 	if err != nil {
 		beego.Critical("Error while adding User to RBAC.", err)
+		return
 	}
 	beego.Info("Transaction waiting to be mined: ", tx.Hash().String())
-
+	TrackTransaction(tx, &models.EthereumTransaction{Kind: models.TransactionKindRole, From: ethereumController.Auth.From.String(), Account: address, Detail: role})
 }
 
 func createNewEthereumAccount() (string, error) {
-	ethereumController := ethereum.GetEthereumController()
-	account, err := ethereumController.Keystore.NewAccount(beego.AppConfig.String("userAccountPassword"))
+	address, err := ethereum.NewAccount()
+	if err != nil {
+		return "", err
+	}
 	// TODO: Uncomment for production
 	//if beego.BConfig.RunMode == "dev" {
-	amountEther := 0.5
-	amount := new(big.Float).Mul(big.NewFloat(amountEther), big.NewFloat(params.Ether))
-	amountWei := new(big.Int)
-	amount.Int(amountWei)
-	ethereum.SendWei(beego.AppConfig.String("systemAccountAddress"), account.Address.String(), amountWei)
+	fundAccount(address, etherToWei(0.5))
 	//}
-	return account.Address.String(), err
+	return address, err
 }
 
 func CheckLoginWithUsername(_username string, _password string) (models.User, error) {
@@ -120,33 +118,27 @@ func CheckLoginWithEmail(_email string, _password string) (models.User, error) {
 	return user, err
 }
 
-func GetAllUsers() []*models.User {
+var userSortFields = listFields{"id": "Id", "username": "Username", "lastname": "Lastname", "tvd": "TVD"}
+
+var userFilters = map[string]listFilter{
+	"role": filterString("Roles__Role__Name"),
+	"tvd":  filterInt("TVD"),
+}
+
+func GetAllUsers(query *ListQuery) (*Page, error) {
 	o := orm.NewOrm()
 	var users []*models.User
-	o.QueryTable(new(models.User)).All(&users)
+	page, err := paginate(o.QueryTable(new(models.User)), query, userFilters, userSortFields, &users)
+	if err != nil {
+		return nil, err
+	}
 	for _, user := range users {
 		o.LoadRelated(user, "Roles")
 		setTVD(user)
 		setEtherBalance(user)
 		setAPaymentTokenBalance(user)
 	}
-	return users
-}
-
-func GetAllUsersByRole(_role string) ([]*models.User, error) {
-	o := orm.NewOrm()
-	role := models.Role{Name: _role}
-	err := o.Read(&role, "Name")
-
-	if err == orm.ErrNoRows {
-		beego.Error("No result found.")
-		return nil, err
-	} else if err == orm.ErrMissPK {
-		beego.Error("No primary key found.")
-		return nil, err
-	}
-	o.LoadRelated(&role, "Users")
-	return role.Users, nil
+	return page, nil
 }
 
 func GetUserById(_id int64) (*models.User, error) {
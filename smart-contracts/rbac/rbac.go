@@ -1,15 +1,30 @@
-// This file is an automatically generated Go binding. Do not modify as any
-// change will likely be lost upon the next re-generation!
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
 
 package rbac
 
 import (
+	"math/big"
 	"strings"
 
+	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = abi.U256
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
 )
 
 // RBACContractABI is the input ABI used to generate the binding from.
@@ -28,13 +43,14 @@ func DeployRBACContract(auth *bind.TransactOpts, backend bind.ContractBackend) (
 	if err != nil {
 		return common.Address{}, nil, nil, err
 	}
-	return address, tx, &RBACContract{RBACContractCaller: RBACContractCaller{contract: contract}, RBACContractTransactor: RBACContractTransactor{contract: contract}}, nil
+	return address, tx, &RBACContract{RBACContractCaller: RBACContractCaller{contract: contract}, RBACContractTransactor: RBACContractTransactor{contract: contract}, RBACContractFilterer: RBACContractFilterer{contract: contract}}, nil
 }
 
 // RBACContract is an auto generated Go binding around an Ethereum contract.
 type RBACContract struct {
 	RBACContractCaller     // Read-only binding to the contract
 	RBACContractTransactor // Write-only binding to the contract
+	RBACContractFilterer   // Log filterer for contract events
 }
 
 // RBACContractCaller is an auto generated read-only Go binding around an Ethereum contract.
@@ -47,6 +63,11 @@ type RBACContractTransactor struct {
 	contract *bind.BoundContract // Generic contract wrapper for the low level calls
 }
 
+// RBACContractFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type RBACContractFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
 // RBACContractSession is an auto generated Go binding around an Ethereum contract,
 // with pre-set call and transact options.
 type RBACContractSession struct {
@@ -86,16 +107,16 @@ type RBACContractTransactorRaw struct {
 
 // NewRBACContract creates a new instance of RBACContract, bound to a specific deployed contract.
 func NewRBACContract(address common.Address, backend bind.ContractBackend) (*RBACContract, error) {
-	contract, err := bindRBACContract(address, backend, backend)
+	contract, err := bindRBACContract(address, backend, backend, backend)
 	if err != nil {
 		return nil, err
 	}
-	return &RBACContract{RBACContractCaller: RBACContractCaller{contract: contract}, RBACContractTransactor: RBACContractTransactor{contract: contract}}, nil
+	return &RBACContract{RBACContractCaller: RBACContractCaller{contract: contract}, RBACContractTransactor: RBACContractTransactor{contract: contract}, RBACContractFilterer: RBACContractFilterer{contract: contract}}, nil
 }
 
 // NewRBACContractCaller creates a new read-only instance of RBACContract, bound to a specific deployed contract.
 func NewRBACContractCaller(address common.Address, caller bind.ContractCaller) (*RBACContractCaller, error) {
-	contract, err := bindRBACContract(address, caller, nil)
+	contract, err := bindRBACContract(address, caller, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -104,20 +125,29 @@ func NewRBACContractCaller(address common.Address, caller bind.ContractCaller) (
 
 // NewRBACContractTransactor creates a new write-only instance of RBACContract, bound to a specific deployed contract.
 func NewRBACContractTransactor(address common.Address, transactor bind.ContractTransactor) (*RBACContractTransactor, error) {
-	contract, err := bindRBACContract(address, nil, transactor)
+	contract, err := bindRBACContract(address, nil, transactor, nil)
 	if err != nil {
 		return nil, err
 	}
 	return &RBACContractTransactor{contract: contract}, nil
 }
 
+// NewRBACContractFilterer creates a new log filterer instance of RBACContract, bound to a specific deployed contract.
+func NewRBACContractFilterer(address common.Address, filterer bind.ContractFilterer) (*RBACContractFilterer, error) {
+	contract, err := bindRBACContract(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &RBACContractFilterer{contract: contract}, nil
+}
+
 // bindRBACContract binds a generic wrapper to an already deployed contract.
-func bindRBACContract(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor) (*bind.BoundContract, error) {
+func bindRBACContract(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
 	parsed, err := abi.JSON(strings.NewReader(RBACContractABI))
 	if err != nil {
 		return nil, err
 	}
-	return bind.NewBoundContract(address, parsed, caller, transactor), nil
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
 }
 
 // Call invokes the (constant) contract method with params as input values and
@@ -160,7 +190,7 @@ func (_RBACContract *RBACContractTransactorRaw) Transact(opts *bind.TransactOpts
 
 // IsAdmin is a free data retrieval call binding the contract method 0x24d7806c.
 //
-// Solidity: function isAdmin(adminAddress address) constant returns(bool)
+// Solidity: function isAdmin(address adminAddress) constant returns(bool)
 func (_RBACContract *RBACContractCaller) IsAdmin(opts *bind.CallOpts, adminAddress common.Address) (bool, error) {
 	var (
 		ret0 = new(bool)
@@ -172,21 +202,21 @@ func (_RBACContract *RBACContractCaller) IsAdmin(opts *bind.CallOpts, adminAddre
 
 // IsAdmin is a free data retrieval call binding the contract method 0x24d7806c.
 //
-// Solidity: function isAdmin(adminAddress address) constant returns(bool)
+// Solidity: function isAdmin(address adminAddress) constant returns(bool)
 func (_RBACContract *RBACContractSession) IsAdmin(adminAddress common.Address) (bool, error) {
 	return _RBACContract.Contract.IsAdmin(&_RBACContract.CallOpts, adminAddress)
 }
 
 // IsAdmin is a free data retrieval call binding the contract method 0x24d7806c.
 //
-// Solidity: function isAdmin(adminAddress address) constant returns(bool)
+// Solidity: function isAdmin(address adminAddress) constant returns(bool)
 func (_RBACContract *RBACContractCallerSession) IsAdmin(adminAddress common.Address) (bool, error) {
 	return _RBACContract.Contract.IsAdmin(&_RBACContract.CallOpts, adminAddress)
 }
 
 // IsCantonEmployee is a free data retrieval call binding the contract method 0x6bb164c9.
 //
-// Solidity: function isCantonEmployee(cantonEmployeeAddress address) constant returns(bool)
+// Solidity: function isCantonEmployee(address cantonEmployeeAddress) constant returns(bool)
 func (_RBACContract *RBACContractCaller) IsCantonEmployee(opts *bind.CallOpts, cantonEmployeeAddress common.Address) (bool, error) {
 	var (
 		ret0 = new(bool)
@@ -198,21 +228,21 @@ func (_RBACContract *RBACContractCaller) IsCantonEmployee(opts *bind.CallOpts, c
 
 // IsCantonEmployee is a free data retrieval call binding the contract method 0x6bb164c9.
 //
-// Solidity: function isCantonEmployee(cantonEmployeeAddress address) constant returns(bool)
+// Solidity: function isCantonEmployee(address cantonEmployeeAddress) constant returns(bool)
 func (_RBACContract *RBACContractSession) IsCantonEmployee(cantonEmployeeAddress common.Address) (bool, error) {
 	return _RBACContract.Contract.IsCantonEmployee(&_RBACContract.CallOpts, cantonEmployeeAddress)
 }
 
 // IsCantonEmployee is a free data retrieval call binding the contract method 0x6bb164c9.
 //
-// Solidity: function isCantonEmployee(cantonEmployeeAddress address) constant returns(bool)
+// Solidity: function isCantonEmployee(address cantonEmployeeAddress) constant returns(bool)
 func (_RBACContract *RBACContractCallerSession) IsCantonEmployee(cantonEmployeeAddress common.Address) (bool, error) {
 	return _RBACContract.Contract.IsCantonEmployee(&_RBACContract.CallOpts, cantonEmployeeAddress)
 }
 
 // IsFarmer is a free data retrieval call binding the contract method 0xb9429069.
 //
-// Solidity: function isFarmer(farmerAddress address) constant returns(bool)
+// Solidity: function isFarmer(address farmerAddress) constant returns(bool)
 func (_RBACContract *RBACContractCaller) IsFarmer(opts *bind.CallOpts, farmerAddress common.Address) (bool, error) {
 	var (
 		ret0 = new(bool)
@@ -224,21 +254,21 @@ func (_RBACContract *RBACContractCaller) IsFarmer(opts *bind.CallOpts, farmerAdd
 
 // IsFarmer is a free data retrieval call binding the contract method 0xb9429069.
 //
-// Solidity: function isFarmer(farmerAddress address) constant returns(bool)
+// Solidity: function isFarmer(address farmerAddress) constant returns(bool)
 func (_RBACContract *RBACContractSession) IsFarmer(farmerAddress common.Address) (bool, error) {
 	return _RBACContract.Contract.IsFarmer(&_RBACContract.CallOpts, farmerAddress)
 }
 
 // IsFarmer is a free data retrieval call binding the contract method 0xb9429069.
 //
-// Solidity: function isFarmer(farmerAddress address) constant returns(bool)
+// Solidity: function isFarmer(address farmerAddress) constant returns(bool)
 func (_RBACContract *RBACContractCallerSession) IsFarmer(farmerAddress common.Address) (bool, error) {
 	return _RBACContract.Contract.IsFarmer(&_RBACContract.CallOpts, farmerAddress)
 }
 
 // IsInspector is a free data retrieval call binding the contract method 0x2cdad41c.
 //
-// Solidity: function isInspector(inspectorAddress address) constant returns(bool)
+// Solidity: function isInspector(address inspectorAddress) constant returns(bool)
 func (_RBACContract *RBACContractCaller) IsInspector(opts *bind.CallOpts, inspectorAddress common.Address) (bool, error) {
 	var (
 		ret0 = new(bool)
@@ -250,182 +280,182 @@ func (_RBACContract *RBACContractCaller) IsInspector(opts *bind.CallOpts, inspec
 
 // IsInspector is a free data retrieval call binding the contract method 0x2cdad41c.
 //
-// Solidity: function isInspector(inspectorAddress address) constant returns(bool)
+// Solidity: function isInspector(address inspectorAddress) constant returns(bool)
 func (_RBACContract *RBACContractSession) IsInspector(inspectorAddress common.Address) (bool, error) {
 	return _RBACContract.Contract.IsInspector(&_RBACContract.CallOpts, inspectorAddress)
 }
 
 // IsInspector is a free data retrieval call binding the contract method 0x2cdad41c.
 //
-// Solidity: function isInspector(inspectorAddress address) constant returns(bool)
+// Solidity: function isInspector(address inspectorAddress) constant returns(bool)
 func (_RBACContract *RBACContractCallerSession) IsInspector(inspectorAddress common.Address) (bool, error) {
 	return _RBACContract.Contract.IsInspector(&_RBACContract.CallOpts, inspectorAddress)
 }
 
 // AddAdmin is a paid mutator transaction binding the contract method 0x70480275.
 //
-// Solidity: function addAdmin(adminAddress address) returns()
+// Solidity: function addAdmin(address adminAddress) returns()
 func (_RBACContract *RBACContractTransactor) AddAdmin(opts *bind.TransactOpts, adminAddress common.Address) (*types.Transaction, error) {
 	return _RBACContract.contract.Transact(opts, "addAdmin", adminAddress)
 }
 
 // AddAdmin is a paid mutator transaction binding the contract method 0x70480275.
 //
-// Solidity: function addAdmin(adminAddress address) returns()
+// Solidity: function addAdmin(address adminAddress) returns()
 func (_RBACContract *RBACContractSession) AddAdmin(adminAddress common.Address) (*types.Transaction, error) {
 	return _RBACContract.Contract.AddAdmin(&_RBACContract.TransactOpts, adminAddress)
 }
 
 // AddAdmin is a paid mutator transaction binding the contract method 0x70480275.
 //
-// Solidity: function addAdmin(adminAddress address) returns()
+// Solidity: function addAdmin(address adminAddress) returns()
 func (_RBACContract *RBACContractTransactorSession) AddAdmin(adminAddress common.Address) (*types.Transaction, error) {
 	return _RBACContract.Contract.AddAdmin(&_RBACContract.TransactOpts, adminAddress)
 }
 
 // AddCantonEmployee is a paid mutator transaction binding the contract method 0xeaec3fc8.
 //
-// Solidity: function addCantonEmployee(cantonEmployeeAddress address) returns()
+// Solidity: function addCantonEmployee(address cantonEmployeeAddress) returns()
 func (_RBACContract *RBACContractTransactor) AddCantonEmployee(opts *bind.TransactOpts, cantonEmployeeAddress common.Address) (*types.Transaction, error) {
 	return _RBACContract.contract.Transact(opts, "addCantonEmployee", cantonEmployeeAddress)
 }
 
 // AddCantonEmployee is a paid mutator transaction binding the contract method 0xeaec3fc8.
 //
-// Solidity: function addCantonEmployee(cantonEmployeeAddress address) returns()
+// Solidity: function addCantonEmployee(address cantonEmployeeAddress) returns()
 func (_RBACContract *RBACContractSession) AddCantonEmployee(cantonEmployeeAddress common.Address) (*types.Transaction, error) {
 	return _RBACContract.Contract.AddCantonEmployee(&_RBACContract.TransactOpts, cantonEmployeeAddress)
 }
 
 // AddCantonEmployee is a paid mutator transaction binding the contract method 0xeaec3fc8.
 //
-// Solidity: function addCantonEmployee(cantonEmployeeAddress address) returns()
+// Solidity: function addCantonEmployee(address cantonEmployeeAddress) returns()
 func (_RBACContract *RBACContractTransactorSession) AddCantonEmployee(cantonEmployeeAddress common.Address) (*types.Transaction, error) {
 	return _RBACContract.Contract.AddCantonEmployee(&_RBACContract.TransactOpts, cantonEmployeeAddress)
 }
 
 // AddFarmer is a paid mutator transaction binding the contract method 0x80c3f96d.
 //
-// Solidity: function addFarmer(farmerAddress address) returns()
+// Solidity: function addFarmer(address farmerAddress) returns()
 func (_RBACContract *RBACContractTransactor) AddFarmer(opts *bind.TransactOpts, farmerAddress common.Address) (*types.Transaction, error) {
 	return _RBACContract.contract.Transact(opts, "addFarmer", farmerAddress)
 }
 
 // AddFarmer is a paid mutator transaction binding the contract method 0x80c3f96d.
 //
-// Solidity: function addFarmer(farmerAddress address) returns()
+// Solidity: function addFarmer(address farmerAddress) returns()
 func (_RBACContract *RBACContractSession) AddFarmer(farmerAddress common.Address) (*types.Transaction, error) {
 	return _RBACContract.Contract.AddFarmer(&_RBACContract.TransactOpts, farmerAddress)
 }
 
 // AddFarmer is a paid mutator transaction binding the contract method 0x80c3f96d.
 //
-// Solidity: function addFarmer(farmerAddress address) returns()
+// Solidity: function addFarmer(address farmerAddress) returns()
 func (_RBACContract *RBACContractTransactorSession) AddFarmer(farmerAddress common.Address) (*types.Transaction, error) {
 	return _RBACContract.Contract.AddFarmer(&_RBACContract.TransactOpts, farmerAddress)
 }
 
 // AddInspector is a paid mutator transaction binding the contract method 0x7e458492.
 //
-// Solidity: function addInspector(inspectorAddress address) returns()
+// Solidity: function addInspector(address inspectorAddress) returns()
 func (_RBACContract *RBACContractTransactor) AddInspector(opts *bind.TransactOpts, inspectorAddress common.Address) (*types.Transaction, error) {
 	return _RBACContract.contract.Transact(opts, "addInspector", inspectorAddress)
 }
 
 // AddInspector is a paid mutator transaction binding the contract method 0x7e458492.
 //
-// Solidity: function addInspector(inspectorAddress address) returns()
+// Solidity: function addInspector(address inspectorAddress) returns()
 func (_RBACContract *RBACContractSession) AddInspector(inspectorAddress common.Address) (*types.Transaction, error) {
 	return _RBACContract.Contract.AddInspector(&_RBACContract.TransactOpts, inspectorAddress)
 }
 
 // AddInspector is a paid mutator transaction binding the contract method 0x7e458492.
 //
-// Solidity: function addInspector(inspectorAddress address) returns()
+// Solidity: function addInspector(address inspectorAddress) returns()
 func (_RBACContract *RBACContractTransactorSession) AddInspector(inspectorAddress common.Address) (*types.Transaction, error) {
 	return _RBACContract.Contract.AddInspector(&_RBACContract.TransactOpts, inspectorAddress)
 }
 
 // RemoveAdmin is a paid mutator transaction binding the contract method 0x1785f53c.
 //
-// Solidity: function removeAdmin(adminAddress address) returns()
+// Solidity: function removeAdmin(address adminAddress) returns()
 func (_RBACContract *RBACContractTransactor) RemoveAdmin(opts *bind.TransactOpts, adminAddress common.Address) (*types.Transaction, error) {
 	return _RBACContract.contract.Transact(opts, "removeAdmin", adminAddress)
 }
 
 // RemoveAdmin is a paid mutator transaction binding the contract method 0x1785f53c.
 //
-// Solidity: function removeAdmin(adminAddress address) returns()
+// Solidity: function removeAdmin(address adminAddress) returns()
 func (_RBACContract *RBACContractSession) RemoveAdmin(adminAddress common.Address) (*types.Transaction, error) {
 	return _RBACContract.Contract.RemoveAdmin(&_RBACContract.TransactOpts, adminAddress)
 }
 
 // RemoveAdmin is a paid mutator transaction binding the contract method 0x1785f53c.
 //
-// Solidity: function removeAdmin(adminAddress address) returns()
+// Solidity: function removeAdmin(address adminAddress) returns()
 func (_RBACContract *RBACContractTransactorSession) RemoveAdmin(adminAddress common.Address) (*types.Transaction, error) {
 	return _RBACContract.Contract.RemoveAdmin(&_RBACContract.TransactOpts, adminAddress)
 }
 
 // RemoveFarmer is a paid mutator transaction binding the contract method 0xe6bf3fdc.
 //
-// Solidity: function removeFarmer(farmerAddress address) returns()
+// Solidity: function removeFarmer(address farmerAddress) returns()
 func (_RBACContract *RBACContractTransactor) RemoveFarmer(opts *bind.TransactOpts, farmerAddress common.Address) (*types.Transaction, error) {
 	return _RBACContract.contract.Transact(opts, "removeFarmer", farmerAddress)
 }
 
 // RemoveFarmer is a paid mutator transaction binding the contract method 0xe6bf3fdc.
 //
-// Solidity: function removeFarmer(farmerAddress address) returns()
+// Solidity: function removeFarmer(address farmerAddress) returns()
 func (_RBACContract *RBACContractSession) RemoveFarmer(farmerAddress common.Address) (*types.Transaction, error) {
 	return _RBACContract.Contract.RemoveFarmer(&_RBACContract.TransactOpts, farmerAddress)
 }
 
 // RemoveFarmer is a paid mutator transaction binding the contract method 0xe6bf3fdc.
 //
-// Solidity: function removeFarmer(farmerAddress address) returns()
+// Solidity: function removeFarmer(address farmerAddress) returns()
 func (_RBACContract *RBACContractTransactorSession) RemoveFarmer(farmerAddress common.Address) (*types.Transaction, error) {
 	return _RBACContract.Contract.RemoveFarmer(&_RBACContract.TransactOpts, farmerAddress)
 }
 
 // RemoveInspector is a paid mutator transaction binding the contract method 0x7c70e791.
 //
-// Solidity: function removeInspector(inspectorAddress address) returns()
+// Solidity: function removeInspector(address inspectorAddress) returns()
 func (_RBACContract *RBACContractTransactor) RemoveInspector(opts *bind.TransactOpts, inspectorAddress common.Address) (*types.Transaction, error) {
 	return _RBACContract.contract.Transact(opts, "removeInspector", inspectorAddress)
 }
 
 // RemoveInspector is a paid mutator transaction binding the contract method 0x7c70e791.
 //
-// Solidity: function removeInspector(inspectorAddress address) returns()
+// Solidity: function removeInspector(address inspectorAddress) returns()
 func (_RBACContract *RBACContractSession) RemoveInspector(inspectorAddress common.Address) (*types.Transaction, error) {
 	return _RBACContract.Contract.RemoveInspector(&_RBACContract.TransactOpts, inspectorAddress)
 }
 
 // RemoveInspector is a paid mutator transaction binding the contract method 0x7c70e791.
 //
-// Solidity: function removeInspector(inspectorAddress address) returns()
+// Solidity: function removeInspector(address inspectorAddress) returns()
 func (_RBACContract *RBACContractTransactorSession) RemoveInspector(inspectorAddress common.Address) (*types.Transaction, error) {
 	return _RBACContract.Contract.RemoveInspector(&_RBACContract.TransactOpts, inspectorAddress)
 }
 
 // RemovecantonEmployee is a paid mutator transaction binding the contract method 0xf6bf0edd.
 //
-// Solidity: function removecantonEmployee(cantonEmployeeAddress address) returns()
+// Solidity: function removecantonEmployee(address cantonEmployeeAddress) returns()
 func (_RBACContract *RBACContractTransactor) RemovecantonEmployee(opts *bind.TransactOpts, cantonEmployeeAddress common.Address) (*types.Transaction, error) {
 	return _RBACContract.contract.Transact(opts, "removecantonEmployee", cantonEmployeeAddress)
 }
 
 // RemovecantonEmployee is a paid mutator transaction binding the contract method 0xf6bf0edd.
 //
-// Solidity: function removecantonEmployee(cantonEmployeeAddress address) returns()
+// Solidity: function removecantonEmployee(address cantonEmployeeAddress) returns()
 func (_RBACContract *RBACContractSession) RemovecantonEmployee(cantonEmployeeAddress common.Address) (*types.Transaction, error) {
 	return _RBACContract.Contract.RemovecantonEmployee(&_RBACContract.TransactOpts, cantonEmployeeAddress)
 }
 
 // RemovecantonEmployee is a paid mutator transaction binding the contract method 0xf6bf0edd.
 //
-// Solidity: function removecantonEmployee(cantonEmployeeAddress address) returns()
+// Solidity: function removecantonEmployee(address cantonEmployeeAddress) returns()
 func (_RBACContract *RBACContractTransactorSession) RemovecantonEmployee(cantonEmployeeAddress common.Address) (*types.Transaction, error) {
 	return _RBACContract.Contract.RemovecantonEmployee(&_RBACContract.TransactOpts, cantonEmployeeAddress)
 }
@@ -1,7 +1,14 @@
-pragma solidity ^0.4.11;
+pragma solidity ^0.8.0;
 
 
-import "rbac.sol";
+// Role Based Access Control, only the checks the request needs (see rbac.sol)
+interface RBAC {
+    function isAdmin(address adminAddress) external view returns (bool);
+
+    function isInspector(address inspectorAddress) external view returns (bool);
+
+    function isCantonEmployee(address cantonEmployeeAddress) external view returns (bool);
+}
 
 
 contract mortal {
@@ -12,10 +19,10 @@ contract mortal {
 
     uint public  modified;
     /* this function is executed at initialization and sets the owner of the contract */
-    function mortal() {owner = msg.sender;}
+    constructor() {owner = msg.sender;}
 
     /* Function to recover the funds on the contract */
-    function kill() {if (msg.sender == owner) selfdestruct(owner);}
+    function kill() public virtual {if (msg.sender == owner) selfdestruct(payable(owner));}
 
     function setCreated() internal {
         created = block.timestamp;
@@ -52,6 +59,14 @@ contract Request is mortal {
     uint256 rausDeduction;  // amount of raus deduction
     }
 
+    /* ______EVENTS______ */
+
+    event InspectorAssigned(address indexed inspector);
+    // numLacks is the number of lacks stored after adding the new ones
+    event LacksAdded(address indexed inspector, uint numLacks);
+    event GVESet(uint16 indexed pointGroupCode, uint32 gve);
+    event LackReversed(uint indexed index, address indexed canton, string reason);
+
     /* ______HELPER VARIABLES______ */
 
     // Role Based Access Control Contract
@@ -71,7 +86,7 @@ contract Request is mortal {
     string public remark;
 
     // Constructor
-    function Request(uint16[] _contributionCodes, string _remark, address _rbacAddress, uint32[] _gves, uint _amountPreviousYear) public {
+    constructor(uint16[] memory _contributionCodes, string memory _remark, address _rbacAddress, uint32[] memory _gves, uint _amountPreviousYear) {
         rbac = RBAC(_rbacAddress);
         contributionCodes = _contributionCodes;
         remark = _remark;
@@ -82,17 +97,18 @@ contract Request is mortal {
 
     // function to assign inspector
     // sender must be Admin or CantonalEmployee
-    function setInspectorId(address _inspectorAddress){
+    function setInspectorId(address _inspectorAddress) public {
         require(rbac.isAdmin(msg.sender) || rbac.isCantonEmployee(msg.sender));
         require(rbac.isInspector(_inspectorAddress));
         inspectorAddress = _inspectorAddress;
         setModified();
+        emit InspectorAssigned(_inspectorAddress);
     }
 
     // function to add issues
     // sender must be assigned as inspector
     // function triggers calculateBTS, calculateRAUS
-    function addLacks(uint16[] _contributionCodes, int64[] _controlCategoryIds, uint16[] _pointGroupCodes, int64[] _controlPointIds, int64[] _lackIds, uint8[] _points) {
+    function addLacks(uint16[] memory _contributionCodes, int64[] memory _controlCategoryIds, uint16[] memory _pointGroupCodes, int64[] memory _controlPointIds, int64[] memory _lackIds, uint8[] memory _points) public {
         require(msg.sender == inspectorAddress);
         for (uint16 i = 0; i < _contributionCodes.length; i++) {
             uint lacksIndex = numLacks++;
@@ -107,28 +123,75 @@ contract Request is mortal {
         calculateBTS();
         calculateRAUS();
         setModified();
+        emit LacksAdded(msg.sender, numLacks);
+    }
+
+    // function to reverse a lack the canton removed after an objection of the farmer
+    // sender must be Admin or CantonalEmployee
+    // the points of the lack are removed and the deductions calculated again
+    function reverseLack(uint _index, string memory _reason) public {
+        require(rbac.isAdmin(msg.sender) || rbac.isCantonEmployee(msg.sender));
+        require(_index < numLacks);
+        Issue storage issue = lacks[_index];
+        require(issue.points > 0);
+        uint8 points = issue.points;
+        issue.points = 0;
+        PointGroupCalculation storage pointGroupCalculation = pointGroups[issue.pointGroupCode];
+        if (issue.contributionCode == 5416) {
+            require(pointGroupCalculation.btsPoints >= points);
+            pointGroupCalculation.btsPoints = pointGroupCalculation.btsPoints - points;
+            if (pointGroupCalculation.btsPoints == 0) {
+                pointGroupCalculation.btsDeduction = 0;
+            }
+        }
+        if (issue.contributionCode == 5417) {
+            require(pointGroupCalculation.rausPoints >= points);
+            pointGroupCalculation.rausPoints = pointGroupCalculation.rausPoints - points;
+            if (pointGroupCalculation.rausPoints == 0) {
+                pointGroupCalculation.rausDeduction = 0;
+            }
+        }
+        calculateBTS();
+        calculateRAUS();
+        setModified();
+        emit LackReversed(_index, msg.sender, _reason);
+    }
+
+    // function to retire the request, the canton rejects requests
+    // sender must be the owner, Admin or CantonalEmployee
+    function kill() public override {
+        if (msg.sender == owner || rbac.isAdmin(msg.sender) || rbac.isCantonEmployee(msg.sender)) selfdestruct(payable(owner));
     }
 
     // internal function to set GVE values
     function setGVE(uint32 _gve1110, uint32 _gve1150, uint32 _gve1128, uint32 _gve1141, uint32 _gve1142, uint32 _gve1124, uint32 _gve1129, uint32 _gve1143, uint32 _gve1144) internal {
         PointGroupCalculation storage btsPointGroup = pointGroups[1110];
         btsPointGroup.gve = _gve1110;
+        emit GVESet(1110, _gve1110);
         btsPointGroup = pointGroups[1150];
         btsPointGroup.gve = _gve1150;
+        emit GVESet(1150, _gve1150);
         btsPointGroup = pointGroups[1128];
         btsPointGroup.gve = _gve1128;
+        emit GVESet(1128, _gve1128);
         btsPointGroup = pointGroups[1141];
         btsPointGroup.gve = _gve1141;
+        emit GVESet(1141, _gve1141);
         btsPointGroup = pointGroups[1142];
         btsPointGroup.gve = _gve1142;
+        emit GVESet(1142, _gve1142);
         btsPointGroup = pointGroups[1124];
         btsPointGroup.gve = _gve1124;
+        emit GVESet(1124, _gve1124);
         btsPointGroup = pointGroups[1129];
         btsPointGroup.gve = _gve1129;
+        emit GVESet(1129, _gve1129);
         btsPointGroup = pointGroups[1143];
         btsPointGroup.gve = _gve1143;
+        emit GVESet(1143, _gve1143);
         btsPointGroup = pointGroups[1144];
         btsPointGroup.gve = _gve1144;
+        emit GVESet(1144, _gve1144);
         calculateBTS();
         calculateRAUS();
         setModified();
@@ -137,13 +200,17 @@ contract Request is mortal {
     // internal function to add  bts points from issues to pointGroup
     function updateBtsPoint(uint16 _pointGroupCode, uint16 _points) internal {
         PointGroupCalculation storage pointGroupCalculation = pointGroups[_pointGroupCode];
-        pointGroupCalculation.btsPoints = pointGroupCalculation.btsPoints + _points;
+        unchecked {
+            pointGroupCalculation.btsPoints = pointGroupCalculation.btsPoints + _points;
+        }
     }
 
     // internal function to add  raus points from issues to pointGroup
     function updateRausPoint(uint16 _pointGroupCode, uint16 _points) internal {
         PointGroupCalculation storage pointGroupCalculation = pointGroups[_pointGroupCode];
-        pointGroupCalculation.rausPoints = pointGroupCalculation.rausPoints + _points;
+        unchecked {
+            pointGroupCalculation.rausPoints = pointGroupCalculation.rausPoints + _points;
+        }
     }
 
     // internal function to calculate the btsTotal and btsDeduction
@@ -160,7 +227,9 @@ contract Request is mortal {
                     continue;
                 }
                 // Multiplied by 10'000 because GVE value is multiplied by 10'000 to allow 4 decimal place
-                btsPointGroup.btsDeduction = (uint256(btsPointGroup.btsPoints - 10) * (9000 / 100)) * 10000;
+                unchecked {
+                    btsPointGroup.btsDeduction = (uint256(btsPointGroup.btsPoints - 10) * (9000 / 100)) * 10000;
+                }
             }
         }
     }
@@ -185,12 +254,14 @@ contract Request is mortal {
                 continue;
             }
             // Multiplied by 10'000 because GVE value is multiplied by 10'000 to allow 4 decimal place
-            rausPointGroup.rausDeduction = (uint256(rausPointGroup.rausPoints - 10) * (multiplier / 100)) * 10000;
+            unchecked {
+                rausPointGroup.rausDeduction = (uint256(rausPointGroup.rausPoints - 10) * (multiplier / 100)) * 10000;
+            }
         }
     }
 
     // Returns amount of the first payment
-    function getFirstPaymentAmount() constant returns (uint256) {
+    function getFirstPaymentAmount() public view returns (uint256) {
         uint256 amount = 0;
         if (amountPreviousYear > 0) {
             // first payment is 50% of amount of previous year
@@ -200,18 +271,22 @@ contract Request is mortal {
     }
 
     // Calculates Final Payment based on btsTotal, btsDeduction, rausTotal and rausDeduction
-    function getFinalPaymentAmount() constant returns (uint256){
+    function getFinalPaymentAmount() public view returns (uint256){
         uint256 amount = 0;
         for (uint16 i = 0; i < pointGroupCodes.length; i++) {
             PointGroupCalculation storage pointGroup = pointGroups[pointGroupCodes[i]];
-            amount += round(pointGroup.btsTotal - pointGroup.btsDeduction);
-            amount += round(pointGroup.rausTotal - pointGroup.rausDeduction);
+            unchecked {
+                amount += round(pointGroup.btsTotal - pointGroup.btsDeduction);
+                amount += round(pointGroup.rausTotal - pointGroup.rausDeduction);
+            }
+        }
+        unchecked {
+            return (amount / 10000) - getFirstPaymentAmount();
         }
-        return (amount / 10000) - getFirstPaymentAmount();
     }
 
     // Round the amount
-    function round(uint256 _amount) internal constant returns (uint256) {
+    function round(uint256 _amount) internal pure returns (uint256) {
         uint256 a = _amount / 5000;
         return a * 5000;
     }
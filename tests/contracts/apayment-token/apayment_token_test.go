@@ -3,6 +3,7 @@ package direct_payment_token
 import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/scmo/apayment-backend/smart-contracts/apayment-token"
@@ -36,7 +37,7 @@ func init() {
 		farmerAuth.From: {Balance: big.NewInt(10000000000)},
 		systemAuth.From: {Balance: big.NewInt(10000000000)},
 		cantonAuth.From: {Balance: big.NewInt(10000000000)},
-	})
+	}, 4712388)
 	sim.Commit()
 }
 
@@ -88,3 +89,25 @@ func Test_TranferTokenToSystem(t *testing.T) {
 		})
 	})
 }
+
+/*
+ Payouts are matched with the Transfer event of the payment, filtered by sender and farmer
+*/
+func Test_TransferWithRequestAddressEvent(t *testing.T) {
+	Convey("Subject: Transfer 20 Token from Canton to Farmer for a request\n", t, func() {
+		amount := big.NewInt(20)
+		_, err := tokenContract.TransferWithMessageAndRequestAddress(cantonAuth, farmerAuth.From, amount, systemAuth.From, []byte("First Payment"))
+		sim.Commit()
+		So(err, ShouldBeNil)
+		Convey("One Transfer event of 20 Token", func() {
+			transfers, err := tokenContract.FilterTransfer(&bind.FilterOpts{Start: 0}, []common.Address{cantonAuth.From}, []common.Address{farmerAuth.From})
+			So(err, ShouldBeNil)
+			var values []*big.Int
+			for transfers.Next() {
+				values = append(values, transfers.Event.Value)
+			}
+			So(len(values), ShouldEqual, 1)
+			So(values[0].Cmp(amount), ShouldEqual, 0)
+		})
+	})
+}
@@ -47,7 +47,7 @@ func init() {
 		cantonAuth.From:    {Balance: big.NewInt(10000000000)},
 		systemAuth.From:    {Balance: big.NewInt(10000000000)},
 		inspectorAuth.From: {Balance: big.NewInt(10000000000)},
-	})
+	}, 4712388)
 
 }
 
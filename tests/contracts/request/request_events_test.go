@@ -0,0 +1,221 @@
+package request
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/scmo/apayment-backend/services"
+	"github.com/scmo/apayment-backend/smart-contracts/direct-payment-request"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// eventRecorder collects the events passed to the RequestEventHandler
+type eventRecorder struct {
+	sync.Mutex
+	inspectorAssigned []*directpaymentrequest.RequestContractInspectorAssigned
+	lacksAdded        []*directpaymentrequest.RequestContractLacksAdded
+	gveSet            []*directpaymentrequest.RequestContractGVESet
+}
+
+func (r *eventRecorder) InspectorAssigned(event *directpaymentrequest.RequestContractInspectorAssigned) error {
+	r.Lock()
+	defer r.Unlock()
+	r.inspectorAssigned = append(r.inspectorAssigned, event)
+	return nil
+}
+
+func (r *eventRecorder) LacksAdded(event *directpaymentrequest.RequestContractLacksAdded) error {
+	r.Lock()
+	defer r.Unlock()
+	r.lacksAdded = append(r.lacksAdded, event)
+	return nil
+}
+
+func (r *eventRecorder) GVESet(event *directpaymentrequest.RequestContractGVESet) error {
+	r.Lock()
+	defer r.Unlock()
+	r.gveSet = append(r.gveSet, event)
+	return nil
+}
+
+func (r *eventRecorder) LackReversed(event *directpaymentrequest.RequestContractLackReversed) error {
+	return nil
+}
+
+func (r *eventRecorder) count() (int, int, int) {
+	r.Lock()
+	defer r.Unlock()
+	return len(r.inspectorAssigned), len(r.lacksAdded), len(r.gveSet)
+}
+
+// waitFor polls the recorder until it has received the expected number of events
+func (r *eventRecorder) waitFor(inspectorAssigned int, lacksAdded int, gveSet int) bool {
+	for i := 0; i < 100; i++ {
+		a, b, c := r.count()
+		if a == inspectorAssigned && b == lacksAdded && c == gveSet {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func deployEventsContract() (common.Address, *directpaymentrequest.RequestContract, []uint32, error) {
+	gvesList := []uint32{290000, 0, 180000, 210000, 0, 0, 50000, 90000, 10000}
+	address, _, rc, err := directpaymentrequest.DeployRequestContract(farmerAuth, sim, []uint16{5416, 5417}, "Events", rbacAddress, gvesList, amountPreviousYear)
+	sim.Commit()
+	return address, rc, gvesList, err
+}
+
+// addLacks lets the inspector add the given lacks, numbered from 1
+func addLacks(rc *directpaymentrequest.RequestContract, cCodes []uint16, pgCodes []uint16, points []uint8) error {
+	ids := make([]int64, len(cCodes))
+	for i := range ids {
+		ids[i] = int64(i + 1)
+	}
+	_, err := rc.AddLacks(inspectorAuth, cCodes, ids, pgCodes, ids, ids, points)
+	sim.Commit()
+	return err
+}
+
+func addEventsLacks(rc *directpaymentrequest.RequestContract) error {
+	return addLacks(rc, []uint16{5416, 5417}, []uint16{1110, 1128}, []uint8{20, 40})
+}
+
+// deployInspectedContract deploys a request, lets the inspector add the given lacks and
+// the canton reverse the lacks at the given indexes
+func deployInspectedContract(cCodes []uint16, pgCodes []uint16, points []uint8, reversed ...int64) (*directpaymentrequest.RequestContract, []uint32, error) {
+	_, rc, gvesList, err := deployEventsContract()
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err = rc.SetInspectorId(cantonAuth, inspectorAuth.From); err != nil {
+		return nil, nil, err
+	}
+	sim.Commit()
+	if len(cCodes) > 0 {
+		if err = addLacks(rc, cCodes, pgCodes, points); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, index := range reversed {
+		if _, err = rc.ReverseLack(cantonAuth, big.NewInt(index), "Reversed"); err != nil {
+			return nil, nil, err
+		}
+		sim.Commit()
+	}
+	return rc, gvesList, nil
+}
+
+/*
+ Deploying a contract emits GVESet for every point group
+*/
+func Test_GVESetEvents(t *testing.T) {
+	_, rc, gvesList, err := deployEventsContract()
+	Convey("Subject: GVESet events\n", t, func() {
+		So(err, ShouldBeNil)
+		it, err := rc.FilterGVESet(&bind.FilterOpts{}, nil)
+		So(err, ShouldBeNil)
+		codes := []uint16{1110, 1150, 1128, 1141, 1142, 1124, 1129, 1143, 1144}
+		i := 0
+		for it.Next() {
+			So(it.Event.PointGroupCode, ShouldEqual, codes[i])
+			So(it.Event.Gve, ShouldEqual, gvesList[i])
+			i++
+		}
+		So(it.Error(), ShouldBeNil)
+		Convey("One event per point group", func() {
+			So(i, ShouldEqual, 9)
+		})
+		Convey("Filter by point group", func() {
+			it, err := rc.FilterGVESet(&bind.FilterOpts{}, []uint16{1128})
+			So(err, ShouldBeNil)
+			So(it.Next(), ShouldBeTrue)
+			So(it.Event.Gve, ShouldEqual, 180000)
+			So(it.Next(), ShouldBeFalse)
+		})
+	})
+}
+
+/*
+ Assigning the inspector and adding lacks emit InspectorAssigned and LacksAdded
+*/
+func Test_InspectorAssignedAndLacksAddedEvents(t *testing.T) {
+	_, rc, _, err := deployEventsContract()
+	sink := make(chan *directpaymentrequest.RequestContractInspectorAssigned, 1)
+	sub, watchErr := rc.WatchInspectorAssigned(nil, sink, nil)
+	if watchErr == nil {
+		defer sub.Unsubscribe()
+	}
+	_, setErr := rc.SetInspectorId(cantonAuth, inspectorAuth.From)
+	sim.Commit()
+	lacksErr := addEventsLacks(rc)
+	if lacksErr == nil {
+		lacksErr = addEventsLacks(rc)
+	}
+
+	Convey("Subject: InspectorAssigned and LacksAdded events\n", t, func() {
+		Convey("No error", func() {
+			So(err, ShouldBeNil)
+			So(watchErr, ShouldBeNil)
+			So(setErr, ShouldBeNil)
+			So(lacksErr, ShouldBeNil)
+		})
+		Convey("Inspector is watched", func() {
+			select {
+			case event := <-sink:
+				So(event.Inspector, ShouldEqual, inspectorAuth.From)
+			case <-time.After(time.Second):
+				So("no InspectorAssigned event", ShouldBeEmpty)
+			}
+		})
+		Convey("Lacks added by the inspector", func() {
+			it, err := rc.FilterLacksAdded(&bind.FilterOpts{}, []common.Address{inspectorAuth.From})
+			So(err, ShouldBeNil)
+			So(it.Next(), ShouldBeTrue)
+			So(it.Event.Inspector, ShouldEqual, inspectorAuth.From)
+			So(it.Event.NumLacks.Int64(), ShouldEqual, 2)
+			So(it.Next(), ShouldBeTrue)
+			So(it.Event.NumLacks.Int64(), ShouldEqual, 4)
+			So(it.Next(), ShouldBeFalse)
+		})
+	})
+}
+
+/*
+ WatchRequest replays the past events and passes on new ones
+*/
+func Test_WatchRequest(t *testing.T) {
+	address, rc, _, err := deployEventsContract()
+	_, setErr := rc.SetInspectorId(cantonAuth, inspectorAuth.From)
+	sim.Commit()
+
+	recorder := &eventRecorder{}
+	sub, watchErr := services.WatchRequest(address, sim, 0, recorder)
+	if watchErr == nil {
+		defer sub.Unsubscribe()
+	}
+	replayed := recorder.waitFor(1, 0, 9)
+	lacksErr := addEventsLacks(rc)
+	delivered := recorder.waitFor(1, 1, 9)
+
+	Convey("Subject: Watch request events\n", t, func() {
+		Convey("No error", func() {
+			So(err, ShouldBeNil)
+			So(setErr, ShouldBeNil)
+			So(watchErr, ShouldBeNil)
+			So(lacksErr, ShouldBeNil)
+		})
+		Convey("Past events are replayed", func() {
+			So(replayed, ShouldBeTrue)
+		})
+		Convey("New events are delivered", func() {
+			So(delivered, ShouldBeTrue)
+			So(recorder.lacksAdded[0].NumLacks.Int64(), ShouldEqual, 2)
+		})
+	})
+}
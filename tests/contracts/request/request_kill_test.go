@@ -0,0 +1,56 @@
+package request
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+ An amended request retires its old contract, other accounts than the farmer who deployed it,
+ admins and canton employees cannot kill it
+*/
+func Test_KillReplacedContract(t *testing.T) {
+	address, rc, _, err := deployEventsContract()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = rc.Kill(inspectorAuth)
+	sim.Commit()
+	Convey("Kill by another account keeps the contract ", t, func() {
+		So(err, ShouldBeNil)
+		code, err := sim.CodeAt(context.Background(), address, nil)
+		So(err, ShouldBeNil)
+		So(len(code), ShouldBeGreaterThan, 0)
+	})
+
+	_, err = rc.Kill(farmerAuth)
+	sim.Commit()
+	Convey("Kill by the owner removes the contract ", t, func() {
+		So(err, ShouldBeNil)
+		code, err := sim.CodeAt(context.Background(), address, nil)
+		So(err, ShouldBeNil)
+		So(len(code), ShouldEqual, 0)
+	})
+}
+
+/*
+ A rejected request is killed by the canton employee who rejected it
+*/
+func Test_KillRejectedContract(t *testing.T) {
+	address, rc, _, err := deployEventsContract()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = rc.Kill(cantonAuth)
+	sim.Commit()
+	Convey("Kill by a canton employee removes the contract ", t, func() {
+		So(err, ShouldBeNil)
+		code, err := sim.CodeAt(context.Background(), address, nil)
+		So(err, ShouldBeNil)
+		So(len(code), ShouldEqual, 0)
+	})
+}
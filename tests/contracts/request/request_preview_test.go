@@ -0,0 +1,103 @@
+package request
+
+import (
+	"testing"
+
+	"github.com/scmo/apayment-backend/services/calculation"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type previewScenario struct {
+	name     string
+	cCodes   []uint16
+	pgCodes  []uint16
+	points   []uint8
+	reversed []int64
+}
+
+/*
+ The calculation engine gives the point groups and payments of the contract
+*/
+func Test_PreviewMatchesContract(t *testing.T) {
+	scenarios := []previewScenario{
+		{name: "No lacks"},
+		{name: "BTS and RAUS lacks", cCodes: []uint16{5416, 5417, 5416}, pgCodes: []uint16{1110, 1128, 1141}, points: []uint8{20, 40, 60}},
+		{name: "Lacks in the same point group add up", cCodes: []uint16{5416, 5416}, pgCodes: []uint16{1110, 1110}, points: []uint8{60, 60}},
+		{name: "RAUS of calves", cCodes: []uint16{5417, 5416}, pgCodes: []uint16{1142, 1144}, points: []uint8{30, 30}},
+		{name: "Lacks of other contributions", cCodes: []uint16{5418, 5416}, pgCodes: []uint16{1110, 1129}, points: []uint8{50, 120}},
+		{name: "Reversed lack", cCodes: []uint16{5416, 5416, 5417}, pgCodes: []uint16{1110, 1110, 1128}, points: []uint8{20, 40, 111}, reversed: []int64{1, 2}},
+	}
+	for _, s := range scenarios {
+		rc, gvesList, err := deployInspectedContract(s.cCodes, s.pgCodes, s.points, s.reversed...)
+		input := &calculation.Input{GVE: make(map[uint16]uint32), AmountPreviousYear: amountPreviousYear}
+		for i, code := range calculation.PointGroupCodes {
+			input.GVE[code] = gvesList[i]
+		}
+		for i := range s.cCodes {
+			points := s.points[i]
+			for _, index := range s.reversed {
+				if index == int64(i) {
+					points = 0
+				}
+			}
+			input.Lacks = append(input.Lacks, calculation.Lack{ContributionCode: s.cCodes[i], PointGroupCode: s.pgCodes[i], Points: points})
+		}
+		result := calculation.Calculate(input)
+
+		Convey("Subject: Preview "+s.name+"\n", t, func() {
+			So(err, ShouldBeNil)
+			Convey("Point groups", func() {
+				for _, pg := range result.PointGroups {
+					expected, err := rc.PointGroups(nil, pg.PointGroupCode)
+					So(err, ShouldBeNil)
+					So(pg.GVE, ShouldEqual, expected.Gve)
+					So(pg.BtsPoints, ShouldEqual, expected.BtsPoints)
+					So(pg.BtsTotal.Cmp(expected.BtsTotal), ShouldEqual, 0)
+					So(pg.BtsDeduction.Cmp(expected.BtsDeduction), ShouldEqual, 0)
+					So(pg.RausPoints, ShouldEqual, expected.RausPoints)
+					So(pg.RausTotal.Cmp(expected.RausTotal), ShouldEqual, 0)
+					So(pg.RausDeduction.Cmp(expected.RausDeduction), ShouldEqual, 0)
+				}
+			})
+			Convey("Payments", func() {
+				first, _ := rc.GetFirstPaymentAmount(nil)
+				final, _ := rc.GetFinalPaymentAmount(nil)
+				So(result.FirstPayment.Cmp(first), ShouldEqual, 0)
+				So(result.FinalPayment.Cmp(final), ShouldEqual, 0)
+			})
+		})
+	}
+}
+
+/*
+ Lacks of fewer than 10 points make the contract deduct more than the total, the final
+ payment wraps around. The calculation flags it.
+*/
+func Test_UnderflowsFlagged(t *testing.T) {
+	rc, gvesList, err := deployInspectedContract([]uint16{5416, 5417}, []uint16{1110, 1128}, []uint8{5, 40})
+	input := &calculation.Input{GVE: make(map[uint16]uint32), AmountPreviousYear: amountPreviousYear, Lacks: []calculation.Lack{{ContributionCode: 5416, PointGroupCode: 1110, Points: 5}, {ContributionCode: 5417, PointGroupCode: 1128, Points: 40}}}
+	for i, code := range calculation.PointGroupCodes {
+		input.GVE[code] = gvesList[i]
+	}
+	result := calculation.Calculate(input)
+	matched := calculation.Calculate(&calculation.Input{GVE: input.GVE, AmountPreviousYear: amountPreviousYear})
+
+	Convey("Subject: Underflows\n", t, func() {
+		So(err, ShouldBeNil)
+		Convey("The contract deducts more than the BTS total", func() {
+			pgc, _ := rc.PointGroups(nil, 1110)
+			So(pgc.BtsDeduction.Cmp(pgc.BtsTotal), ShouldBeGreaterThan, 0)
+			final, _ := rc.GetFinalPaymentAmount(nil)
+			So(result.FinalPayment.Cmp(final), ShouldEqual, 0)
+		})
+		Convey("The point group is flagged", func() {
+			underflows := calculation.Underflows(result)
+			So(len(underflows), ShouldEqual, 1)
+			So(underflows[0].PointGroupCode, ShouldEqual, 1110)
+			So(underflows[0].Field, ShouldEqual, "btsDeduction")
+		})
+		Convey("Nothing is flagged without lacks", func() {
+			So(len(calculation.Underflows(matched)), ShouldEqual, 0)
+		})
+	})
+}
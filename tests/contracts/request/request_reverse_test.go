@@ -0,0 +1,79 @@
+package request
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+ A canton employee reverses lacks removed after an objection, the deductions are
+ calculated as if the lacks had never been added
+*/
+func Test_ReverseLack(t *testing.T) {
+	rc, _, err := deployInspectedContract([]uint16{5416, 5416, 5417}, []uint16{1110, 1110, 1128}, []uint8{20, 40, 111})
+	_, farmerErr := rc.ReverseLack(farmerAuth, big.NewInt(1), "Not allowed")
+	sim.Commit()
+	_, reverseBtsErr := rc.ReverseLack(cantonAuth, big.NewInt(1), "Removed after objection")
+	sim.Commit()
+	_, reverseRausErr := rc.ReverseLack(adminAuth, big.NewInt(2), "Removed after objection")
+	sim.Commit()
+	_, twiceErr := rc.ReverseLack(cantonAuth, big.NewInt(1), "Again")
+	sim.Commit()
+	_, outOfRangeErr := rc.ReverseLack(cantonAuth, big.NewInt(3), "Unknown")
+	sim.Commit()
+	reference, _, referenceErr := deployInspectedContract([]uint16{5416}, []uint16{1110}, []uint8{20})
+
+	Convey("Subject: Reverse lacks\n", t, func() {
+		Convey("No error", func() {
+			So(err, ShouldBeNil)
+			So(referenceErr, ShouldBeNil)
+			So(reverseBtsErr, ShouldBeNil)
+			So(reverseRausErr, ShouldBeNil)
+		})
+		Convey("Only admins and canton employees may reverse a lack", func() {
+			So(farmerErr, ShouldNotBeNil)
+		})
+		Convey("A lack is reversed once and must exist", func() {
+			So(twiceErr, ShouldNotBeNil)
+			So(outOfRangeErr, ShouldNotBeNil)
+		})
+		Convey("The points of the reversed lacks are removed", func() {
+			lack, _ := rc.Lacks(nil, big.NewInt(1))
+			So(lack.Points, ShouldEqual, 0)
+			So(lack.LackId, ShouldEqual, 2)
+			lack, _ = rc.Lacks(nil, big.NewInt(0))
+			So(lack.Points, ShouldEqual, 20)
+			numLacks, _ := rc.NumLacks(nil)
+			So(numLacks.Int64(), ShouldEqual, 3)
+		})
+		Convey("Pointgroup 1110: BTS is calculated with the remaining lack", func() {
+			pgc, _ := rc.PointGroups(nil, 1110)
+			expected, _ := reference.PointGroups(nil, 1110)
+			So(pgc.BtsPoints, ShouldEqual, 20)
+			So(pgc.BtsDeduction.Cmp(expected.BtsDeduction), ShouldEqual, 0)
+		})
+		Convey("Pointgroup 1128: RAUS deduction is dropped", func() {
+			pgc, _ := rc.PointGroups(nil, 1128)
+			So(pgc.RausPoints, ShouldEqual, 0)
+			So(pgc.RausDeduction.Int64(), ShouldEqual, 0)
+		})
+		Convey("Payout amounts match a request without the reversed lacks", func() {
+			amount, _ := rc.GetFinalPaymentAmount(nil)
+			expected, _ := reference.GetFinalPaymentAmount(nil)
+			So(amount.Cmp(expected), ShouldEqual, 0)
+		})
+		Convey("Reversals are logged with their reason", func() {
+			it, err := rc.FilterLackReversed(&bind.FilterOpts{}, nil, []common.Address{cantonAuth.From})
+			So(err, ShouldBeNil)
+			So(it.Next(), ShouldBeTrue)
+			So(it.Event.Index.Int64(), ShouldEqual, 1)
+			So(it.Event.Canton, ShouldEqual, cantonAuth.From)
+			So(it.Event.Reason, ShouldEqual, "Removed after objection")
+			So(it.Next(), ShouldBeFalse)
+		})
+	})
+}
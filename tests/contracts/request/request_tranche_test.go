@@ -0,0 +1,47 @@
+package request
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/scmo/apayment-backend/services/calculation"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+ The default tranches pay the first and the final payment of the contract, any schedule
+ ending with the remainder pays the entitlement
+*/
+func Test_Tranches(t *testing.T) {
+	rc, _, err := deployInspectedContract([]uint16{5416, 5417}, []uint16{1110, 1128}, []uint8{20, 40})
+	first, firstErr := rc.GetFirstPaymentAmount(nil)
+	final, finalErr := rc.GetFinalPaymentAmount(nil)
+	previousYear, previousYearErr := rc.AmountPreviousYear(nil)
+
+	Convey("Subject: Tranches\n", t, func() {
+		So(err, ShouldBeNil)
+		So(firstErr, ShouldBeNil)
+		So(finalErr, ShouldBeNil)
+		So(previousYearErr, ShouldBeNil)
+		entitlement := calculation.Entitlement(final, first)
+		Convey("Default tranches", func() {
+			advance := calculation.PreviousYearTranche(50, previousYear)
+			So(advance.Cmp(first), ShouldEqual, 0)
+			So(calculation.EntitlementTranche(100, entitlement, advance).Cmp(final), ShouldEqual, 0)
+		})
+		Convey("Advance, main payment and final correction", func() {
+			paid := new(big.Int)
+			advance := calculation.PreviousYearTranche(30, previousYear)
+			paid.Add(paid, advance)
+			main := calculation.EntitlementTranche(80, entitlement, paid)
+			paid.Add(paid, main)
+			correction := calculation.EntitlementTranche(100, entitlement, paid)
+			paid.Add(paid, correction)
+			So(advance.Int64(), ShouldEqual, 150000)
+			So(paid.Cmp(entitlement), ShouldEqual, 0)
+		})
+		Convey("Nothing is paid back when the tranches before paid more", func() {
+			So(calculation.EntitlementTranche(10, entitlement, entitlement).Sign(), ShouldEqual, 0)
+		})
+	})
+}